@@ -5,6 +5,8 @@
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents an Adaptive LFU cache that adjusts to access patterns
@@ -20,6 +22,7 @@ type Cache[K comparable, V any] struct {
 	decayInterval time.Duration
 	mu            sync.RWMutex
 	onEvict       func(K, V)
+	stats         cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -90,9 +93,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	if entry, exists := c.items[key]; exists {
 		c.incrementFrequency(entry)
 		entry.lastAccess = time.Now().UnixNano()
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -317,6 +322,7 @@ func (c *Cache[K, V]) evictLeastFrequent() bool {
 		entry := element.Value.(*entry[K, V])
 		c.frequencies[c.minFreq].Remove(element)
 		delete(c.items, entry.key)
+		c.stats.RecordEviction()
 
 		if c.onEvict != nil {
 			c.onEvict(entry.key, entry.value)
@@ -449,6 +455,14 @@ func (c *Cache[K, V]) Stats() Stats {
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
 	Size                  int           // actual cache size
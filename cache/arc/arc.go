@@ -3,6 +3,9 @@
 import (
 	"fmt"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/human"
 )
 
 // Cache represents an ARC (Adaptive Replacement Cache) cache
@@ -27,16 +30,23 @@ type Cache[K comparable, V any] struct {
 
 	mu      sync.RWMutex
 	onEvict func(K, V)
+	stats   cache.StatsCounter
+
+	// 按重量限制容量：weigher 为 nil 时只按条目数限制，ghost 条目不计重量
+	weigher   cache.Weigher[K, V]
+	maxWeight int64
+	weight    int64
 }
 
 // node represents a cache entry in the linked list
 type node[K comparable, V any] struct {
-	key   K
-	value V
-	prev  *node[K, V]
-	next  *node[K, V]
-	list  int8 // 0=t1, 1=t2, 2=b1, 3=b2
-	ghost bool
+	key    K
+	value  V
+	prev   *node[K, V]
+	next   *node[K, V]
+	list   int8 // 0=t1, 1=t2, 2=b1, 3=b2
+	ghost  bool
+	weight int64
 }
 
 // linkedList is a custom doubly-linked list implementation
@@ -138,6 +148,26 @@ func NewWithEvict[K comparable, V any](capacity int, onEvict func(K, V)) (*Cache
 	return cache, nil
 }
 
+// NewWithWeigher creates a new ARC cache bounded by both the entry count and the total weight.
+// weigher returns the weight of each entry (e.g. its size in bytes); entries are moved to the
+// ghost lists following the ARC replacement order until the total weight is at most maxWeight.
+func NewWithWeigher[K comparable, V any](capacity int, maxWeight int64, weigher cache.Weigher[K, V]) (*Cache[K, V], error) {
+	if maxWeight <= 0 {
+		return nil, fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+	if weigher == nil {
+		return nil, fmt.Errorf("weigher must not be nil")
+	}
+
+	cache, err := New[K, V](capacity)
+	if err != nil {
+		return nil, err
+	}
+	cache.weigher = weigher
+	cache.maxWeight = maxWeight
+	return cache, nil
+}
+
 // Get retrieves a value from the cache
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.mu.Lock()
@@ -147,10 +177,12 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 		if !n.ghost {
 			// Cache hit - move to T2 (or keep in T2)
 			c.hit(n)
+			c.stats.RecordHit()
 			return n.value, true
 		}
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -160,12 +192,30 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if n, exists := c.items[key]; exists {
+	weight := c.weigh(key, value)
+	if c.maxWeight > 0 && weight > c.maxWeight {
+		// 单个条目超过重量上限，不缓存；旧值视为被淘汰
+		if n, exists := c.items[key]; exists && !n.ghost {
+			c.stats.RecordEviction()
+			if c.onEvict != nil {
+				c.onEvict(n.key, n.value)
+			}
+			c.removeEntry(n)
+			return true
+		}
+		return false
+	}
+
+	var n *node[K, V]
+	if existing, exists := c.items[key]; exists {
+		n = existing
 		if !n.ghost {
 			// Update existing entry
 			n.value = value
+			c.weight += weight - n.weight
+			n.weight = weight
 			c.hit(n)
-			return false
+			return c.evictForWeight(n)
 		} else {
 			// Ghost hit - handle adaptation
 			evicted = c.ghostHit(n, value)
@@ -173,9 +223,12 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 	} else {
 		// New entry
 		evicted = c.miss(key, value)
+		n = c.items[key]
 	}
 
-	return evicted
+	n.weight = weight
+	c.weight += weight
+	return c.evictForWeight(n) || evicted
 }
 
 // hit handles cache hit by moving entry to T2
@@ -266,48 +319,124 @@ func (c *Cache[K, V]) replace(ghostHit bool) {
 	}
 
 	if target.Len() > 0 {
-		n := target.Back()
+		c.demote(target.Back())
+	}
+}
 
-		// Call eviction callback
-		if c.onEvict != nil && !n.ghost {
-			c.onEvict(n.key, n.value)
-		}
+// demote evicts a resident node into the matching ghost list
+func (c *Cache[K, V]) demote(n *node[K, V]) {
+	c.stats.RecordEviction()
 
-		// Move to appropriate ghost list
-		target.Remove(n)
+	// Call eviction callback
+	if c.onEvict != nil {
+		c.onEvict(n.key, n.value)
+	}
 
-		if target == c.t1 {
-			// Move to B1
-			c.b1.PushFront(n)
-			n.list = 2
-		} else {
-			// Move to B2
-			c.b2.PushFront(n)
-			n.list = 3
+	// ghost 只需要保留键，释放值并扣除重量
+	var zero V
+	n.value = zero
+	c.weight -= n.weight
+	n.weight = 0
+	n.ghost = true
+
+	// Move to appropriate ghost list
+	if n.list == 0 {
+		c.t1.Remove(n)
+		c.b1.PushFront(n)
+		n.list = 2
+	} else {
+		c.t2.Remove(n)
+		c.b2.PushFront(n)
+		n.list = 3
+	}
+
+	// Maintain ghost list sizes - remove oldest ghosts if over capacity
+	for c.b1.Len() > c.capacity {
+		back := c.b1.Back()
+		if back == nil {
+			break
 		}
-		n.ghost = true
+		c.removeEntry(back)
+	}
 
-		// Maintain ghost list sizes - remove oldest ghosts if over capacity
-		for c.b1.Len() > c.capacity {
-			back := c.b1.Back()
-			if back == nil {
-				break
-			}
-			c.removeEntry(back)
+	for c.b2.Len() > c.capacity {
+		back := c.b2.Back()
+		if back == nil {
+			break
 		}
+		c.removeEntry(back)
+	}
+}
 
-		for c.b2.Len() > c.capacity {
-			back := c.b2.Back()
-			if back == nil {
-				break
-			}
-			c.removeEntry(back)
+// evictForWeight demotes resident nodes in ARC replacement order until the
+// total weight fits, never evicting keep
+func (c *Cache[K, V]) evictForWeight(keep *node[K, V]) (evicted bool) {
+	for c.maxWeight > 0 && c.weight > c.maxWeight {
+		first, second := c.t2, c.t1
+		if c.t1.Len() > 0 && c.t1.Len() > c.p {
+			first, second = c.t1, c.t2
+		}
+
+		n := first.Back()
+		if n == nil || n == keep {
+			n = second.Back()
 		}
+		if n == nil || n == keep {
+			break
+		}
+		c.demote(n)
+		evicted = true
+	}
+	return evicted
+}
+
+// weigh returns the weight of an entry, 0 when no weigher is configured
+func (c *Cache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 0
 	}
+	return max(0, c.weigher(key, value))
+}
+
+// Weight returns the total weight of resident entries, 0 when no weigher is configured
+func (c *Cache[K, V]) Weight() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.weight
+}
+
+// MaxWeight returns the weight limit, 0 when no weigher is configured
+func (c *Cache[K, V]) MaxWeight() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.maxWeight
+}
+
+// ResizeWeight changes the weight limit, evicting entries until the total fits
+func (c *Cache[K, V]) ResizeWeight(maxWeight int64) error {
+	if maxWeight <= 0 {
+		return fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.weigher == nil {
+		return fmt.Errorf("cache has no weigher")
+	}
+
+	c.maxWeight = maxWeight
+	c.evictForWeight(nil)
+	return nil
 }
 
 // removeEntry removes an entry completely
 func (c *Cache[K, V]) removeEntry(n *node[K, V]) {
+	if !n.ghost {
+		c.weight -= n.weight
+	}
 	if n.list == 0 {
 		c.t1.Remove(n)
 	} else if n.list == 1 {
@@ -394,6 +523,7 @@ func (c *Cache[K, V]) Clear() {
 	c.b1.Init()
 	c.b2.Init()
 	c.p = 0
+	c.weight = 0
 }
 
 // Keys returns all keys in the cache (excluding ghosts)
@@ -495,25 +625,47 @@ func (c *Cache[K, V]) Stats() Stats {
 	defer c.mu.RUnlock()
 
 	return Stats{
-		Size:     c.t1.Len() + c.t2.Len(),
-		Capacity: c.capacity,
-		T1Size:   c.t1.Len(),
-		T2Size:   c.t2.Len(),
-		B1Size:   c.b1.Len(),
-		B2Size:   c.b2.Len(),
-		P:        c.p,
+		Size:      c.t1.Len() + c.t2.Len(),
+		Capacity:  c.capacity,
+		T1Size:    c.t1.Len(),
+		T2Size:    c.t2.Len(),
+		B1Size:    c.b1.Len(),
+		B2Size:    c.b2.Len(),
+		P:         c.p,
+		Weight:    c.weight,
+		MaxWeight: c.maxWeight,
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	stats.Weight = c.Weight()
+	stats.MaxWeight = c.MaxWeight()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
-	Size     int // actual cache size (T1 + T2)
-	Capacity int // maximum cache capacity
-	T1Size   int // recent entries size
-	T2Size   int // frequent entries size
-	B1Size   int // ghost entries from T1
-	B2Size   int // ghost entries from T2
-	P        int // adaptive parameter (target T1 size)
+	Size      int   // actual cache size (T1 + T2)
+	Capacity  int   // maximum cache capacity
+	T1Size    int   // recent entries size
+	T2Size    int   // frequent entries size
+	B1Size    int   // ghost entries from T1
+	B2Size    int   // ghost entries from T2
+	P         int   // adaptive parameter (target T1 size)
+	Weight    int64 // total weight of resident entries, 0 without a weigher
+	MaxWeight int64 // weight limit, 0 without a weigher
+}
+
+// String returns a short summary; weights are printed as byte sizes
+func (s Stats) String() string {
+	if s.MaxWeight <= 0 {
+		return fmt.Sprintf("size=%d/%d", s.Size, s.Capacity)
+	}
+	return fmt.Sprintf("size=%d/%d weight=%s/%s", s.Size, s.Capacity, human.ByteSize(s.Weight), human.ByteSize(s.MaxWeight))
 }
 
 // Helper functions
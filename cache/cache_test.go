@@ -29,3 +29,44 @@
 	_ cache.Cache[string, int] = (*tinylfu.Cache[string, int])(nil)
 	_ cache.Cache[string, int] = (*wtinylfu.Cache[string, int])(nil)
 )
+
+// Compile-time interface compliance checks for the wrappers.
+var (
+	_ cache.Cache[string, int] = (*cache.Expiring[string, int])(nil)
+	_ cache.Cache[string, int] = (*cache.Sharded[string, int])(nil)
+	_ cache.Cache[string, int] = (*cache.Loading[string, int])(nil)
+)
+
+// Compile-time checks that every policy and wrapper reports unified statistics.
+var (
+	_ cache.StatsProvider = (*lru.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*lfu.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*arc.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*mru.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*slru.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*lruk.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*alfu.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*fbr.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*optimal.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*tinylfu.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*wtinylfu.Cache[string, int])(nil)
+	_ cache.StatsProvider = (*cache.Expiring[string, int])(nil)
+	_ cache.StatsProvider = (*cache.Sharded[string, int])(nil)
+	_ cache.StatsProvider = (*cache.Loading[string, int])(nil)
+)
+
+// Compile-time checks for policies that restore their internal ordering from snapshots.
+var (
+	_ cache.Snapshotter[string, int] = (*lru.Cache[string, int])(nil)
+	_ cache.Snapshotter[string, int] = (*lfu.Cache[string, int])(nil)
+	_ cache.Snapshotter[string, int] = (*tinylfu.Cache[string, int])(nil)
+	_ cache.Snapshotter[string, int] = (*wtinylfu.Cache[string, int])(nil)
+)
+
+// Compile-time checks for policies that can bound the total entry weight.
+var (
+	_ cache.Weighted = (*lru.Cache[string, int])(nil)
+	_ cache.Weighted = (*slru.Cache[string, int])(nil)
+	_ cache.Weighted = (*arc.Cache[string, int])(nil)
+	_ cache.Weighted = (*wtinylfu.Cache[string, int])(nil)
+)
@@ -0,0 +1,413 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EvictReason describes why an entry left an Expiring cache.
+type EvictReason int
+
+const (
+	// EvictReasonCapacity means the underlying policy evicted the entry to make room.
+	EvictReasonCapacity EvictReason = iota + 1
+	// EvictReasonExpired means the entry outlived its TTL.
+	EvictReasonExpired
+)
+
+// String returns the name of the reason
+func (r EvictReason) String() string {
+	switch r {
+	case EvictReasonCapacity:
+		return "capacity"
+	case EvictReasonExpired:
+		return "expired"
+	default:
+		return fmt.Sprintf("EvictReason(%d)", int(r))
+	}
+}
+
+// Expiring adds per-entry TTL on top of any Cache implementation.
+// Expired entries are removed lazily on access and, optionally, by a background janitor.
+// 所有操作由 Expiring 自身的锁串行化，内部缓存的锁只作为第二层保护
+type Expiring[K comparable, V any] struct {
+	cache      Cache[K, V]
+	defaultTTL time.Duration
+	deadlines  map[K]int64 // 过期时间（UnixNano），未设置 TTL 的键不在此表中
+	mu         sync.Mutex
+	onEvict    func(K, V, EvictReason)
+	now        func() time.Time
+
+	// removing 标记当前是否处于 Expiring 主动删除（Remove/Clear/过期）期间，
+	// 此时内部缓存触发的回调不是淘汰；其余时刻（Put、Resize，以及 wtinylfu 等在 Get 中调整分区时）都算作容量淘汰
+	removing bool
+
+	stats StatsCounter
+
+	janitorStop chan struct{}
+	janitorDone chan struct{}
+}
+
+// NewExpiring creates an Expiring cache.
+// newCache is called once with the eviction hook that must be passed to the policy,
+// e.g. func(fn func(string, int)) (cache.Cache[string, int], error) { return lru.NewWithEvict(100, fn) }.
+// A defaultTTL <= 0 means entries stored with Put never expire.
+func NewExpiring[K comparable, V any](newCache func(onEvict func(K, V)) (Cache[K, V], error), defaultTTL time.Duration) (*Expiring[K, V], error) {
+	if newCache == nil {
+		return nil, fmt.Errorf("cache constructor must not be nil")
+	}
+
+	c := &Expiring[K, V]{
+		defaultTTL: defaultTTL,
+		deadlines:  make(map[K]int64),
+		now:        time.Now,
+	}
+
+	inner, err := newCache(c.handleEvict)
+	if err != nil {
+		return nil, err
+	}
+	if inner == nil {
+		return nil, fmt.Errorf("cache constructor returned nil cache")
+	}
+	c.cache = inner
+
+	return c, nil
+}
+
+// NewExpiringWithEvict creates an Expiring cache with an eviction callback.
+// The callback fires for capacity evictions and expirations, not for Remove or Clear.
+func NewExpiringWithEvict[K comparable, V any](newCache func(onEvict func(K, V)) (Cache[K, V], error), defaultTTL time.Duration, onEvict func(K, V, EvictReason)) (*Expiring[K, V], error) {
+	c, err := NewExpiring[K, V](newCache, defaultTTL)
+	if err != nil {
+		return nil, err
+	}
+	c.onEvict = onEvict
+	return c, nil
+}
+
+// handleEvict is installed as the eviction callback of the underlying policy
+func (c *Expiring[K, V]) handleEvict(key K, value V) {
+	// 内部缓存只被 Expiring 持锁调用，回调发生在这些调用之中，这里无需再加锁。
+	// 除 Expiring 主动删除外，任何调用（包括 Get）中发生的回调都是淘汰
+	delete(c.deadlines, key)
+
+	if c.removing {
+		return
+	}
+
+	c.stats.RecordEviction()
+	if c.onEvict != nil {
+		c.onEvict(key, value, EvictReasonCapacity)
+	}
+}
+
+// expiredLocked reports whether key has a deadline that has passed
+func (c *Expiring[K, V]) expiredLocked(key K, now int64) bool {
+	deadline, ok := c.deadlines[key]
+	return ok && now >= deadline
+}
+
+// removeLocked removes key from the underlying cache without reporting an eviction
+func (c *Expiring[K, V]) removeLocked(key K) (value V, ok bool) {
+	c.removing = true
+	defer func() { c.removing = false }()
+
+	return c.cache.Remove(key)
+}
+
+// expireLocked removes an expired key from the underlying cache
+// and reports whether it was still present
+func (c *Expiring[K, V]) expireLocked(key K) bool {
+	delete(c.deadlines, key)
+
+	value, ok := c.removeLocked(key)
+	if !ok {
+		return false
+	}
+
+	c.stats.RecordExpiration()
+	if c.onEvict != nil {
+		c.onEvict(key, value, EvictReasonExpired)
+	}
+	return true
+}
+
+// checkLocked lazily expires key and reports whether it is still live
+func (c *Expiring[K, V]) checkLocked(key K) bool {
+	if c.expiredLocked(key, c.now().UnixNano()) {
+		c.expireLocked(key)
+		return false
+	}
+	return true
+}
+
+// Get retrieves a live value from the cache
+func (c *Expiring[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkLocked(key) {
+		c.stats.RecordMiss()
+		return value, false
+	}
+
+	value, ok = c.cache.Get(key)
+	if ok {
+		c.stats.RecordHit()
+	} else {
+		c.stats.RecordMiss()
+	}
+	return value, ok
+}
+
+// Put adds or updates a value using the default TTL
+func (c *Expiring[K, V]) Put(key K, value V) (evicted bool) {
+	return c.PutWithTTL(key, value, c.defaultTTL)
+}
+
+// PutWithTTL adds or updates a value that expires after ttl.
+// A ttl <= 0 stores the entry without expiration.
+func (c *Expiring[K, V]) PutWithTTL(key K, value V, ttl time.Duration) (evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicted = c.cache.Put(key, value)
+
+	if ttl > 0 {
+		c.deadlines[key] = c.now().Add(ttl).UnixNano()
+	} else {
+		delete(c.deadlines, key)
+	}
+
+	return evicted
+}
+
+// TTL returns the remaining lifetime of key.
+// ok is false if the key is missing or expired; a zero duration with ok means no expiration.
+func (c *Expiring[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkLocked(key) || !c.cache.Contains(key) {
+		return 0, false
+	}
+
+	deadline, has := c.deadlines[key]
+	if !has {
+		return 0, true
+	}
+	return time.Duration(deadline - c.now().UnixNano()), true
+}
+
+// Remove removes a key from the cache
+func (c *Expiring[K, V]) Remove(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expired := c.expiredLocked(key, c.now().UnixNano())
+	delete(c.deadlines, key)
+
+	value, ok = c.removeLocked(key)
+	if expired {
+		var zero V
+		return zero, false
+	}
+	return value, ok
+}
+
+// Contains checks if a live key exists without updating its position
+func (c *Expiring[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkLocked(key) {
+		return false
+	}
+	return c.cache.Contains(key)
+}
+
+// Peek returns a live value without updating its position
+func (c *Expiring[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkLocked(key) {
+		return value, false
+	}
+	return c.cache.Peek(key)
+}
+
+// Len returns the number of items in the cache.
+// Expired entries that have not been collected yet are included.
+func (c *Expiring[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cache.Len()
+}
+
+// Cap returns the capacity of the cache
+func (c *Expiring[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cache.Cap()
+}
+
+// Clear removes all items from the cache
+func (c *Expiring[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removing = true
+	c.cache.Clear()
+	c.removing = false
+	clear(c.deadlines)
+}
+
+// Keys returns all live keys in the underlying policy order
+func (c *Expiring[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UnixNano()
+	keys := c.cache.Keys()
+	live := keys[:0]
+	for _, key := range keys {
+		if !c.expiredLocked(key, now) {
+			live = append(live, key)
+		}
+	}
+	return live
+}
+
+// Values returns all live values in the underlying policy order
+func (c *Expiring[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UnixNano()
+	keys := c.cache.Keys()
+	values := make([]V, 0, len(keys))
+	for _, key := range keys {
+		if c.expiredLocked(key, now) {
+			continue
+		}
+		if value, ok := c.cache.Peek(key); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Items returns all live key-value pairs in the cache
+func (c *Expiring[K, V]) Items() map[K]V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UnixNano()
+	items := c.cache.Items()
+	for key := range items {
+		if c.expiredLocked(key, now) {
+			delete(items, key)
+		}
+	}
+	return items
+}
+
+// Resize changes the capacity of the underlying cache
+func (c *Expiring[K, V]) Resize(capacity int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cache.Resize(capacity)
+}
+
+// DeleteExpired removes every expired entry and returns how many were removed
+func (c *Expiring[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UnixNano()
+	var expired []K
+	for key, deadline := range c.deadlines {
+		if now >= deadline {
+			expired = append(expired, key)
+		}
+	}
+
+	removed := 0
+	for _, key := range expired {
+		if c.expireLocked(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartJanitor starts a background goroutine that calls DeleteExpired every interval.
+// A running janitor is stopped and replaced.
+func (c *Expiring[K, V]) StartJanitor(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("janitor interval must be positive, got %v", interval)
+	}
+
+	c.StopJanitor()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	c.mu.Lock()
+	c.janitorStop = stop
+	c.janitorDone = done
+	c.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.DeleteExpired()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StopJanitor stops the background janitor and waits for it to exit.
+// It is safe to call when no janitor is running.
+func (c *Expiring[K, V]) StopJanitor() {
+	c.mu.Lock()
+	stop, done := c.janitorStop, c.janitorDone
+	c.janitorStop, c.janitorDone = nil, nil
+	c.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// CacheStats returns cache statistics.
+// Evictions count only capacity evictions; expirations are counted separately.
+// Size and Weight include expired entries that have not been collected yet.
+func (c *Expiring[K, V]) CacheStats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats.Snapshot()
+	stats.Size = c.cache.Len()
+	stats.Capacity = c.cache.Cap()
+	weightOf(c.cache, &stats)
+	return stats
+}
@@ -0,0 +1,289 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/cache/lru"
+	"github.com/lazygophers/utils/cache/wtinylfu"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestExpiring(t *testing.T, capacity int, ttl time.Duration, onEvict func(string, int, cache.EvictReason)) (*cache.Expiring[string, int], *fakeClock) {
+	t.Helper()
+
+	c, err := cache.NewExpiringWithEvict[string, int](func(fn func(string, int)) (cache.Cache[string, int], error) {
+		return lru.NewWithEvict[string, int](capacity, fn)
+	}, ttl, onEvict)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	c.SetNow(clock.now)
+	return c, clock
+}
+
+func TestNewExpiringError(t *testing.T) {
+	if _, err := cache.NewExpiring[string, int](nil, time.Second); err == nil {
+		t.Error("Expected error for nil constructor")
+	}
+
+	_, err := cache.NewExpiring[string, int](func(fn func(string, int)) (cache.Cache[string, int], error) {
+		return lru.NewWithEvict[string, int](0, fn)
+	}, time.Second)
+	if err == nil {
+		t.Error("Expected error from underlying constructor")
+	}
+}
+
+func TestExpiringDefaultTTL(t *testing.T) {
+	c, clock := newTestExpiring(t, 10, time.Minute, nil)
+
+	c.Put("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected a=1, got %v, %v", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Expected b to be missing")
+	}
+
+	clock.advance(59 * time.Second)
+	if !c.Contains("a") {
+		t.Error("Expected a to be alive before TTL")
+	}
+
+	clock.advance(time.Second)
+	if c.Contains("a") {
+		t.Error("Expected a to be expired")
+	}
+	if _, ok := c.Peek("a"); ok {
+		t.Error("Expected Peek to miss expired key")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Expected expired key to be removed lazily, got length %d", c.Len())
+	}
+
+	c.Put("a", 1)
+	clock.advance(time.Minute)
+	c.Get("a")
+
+	stats := c.CacheStats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Expirations != 2 {
+		t.Errorf("Expected 1 hit, 2 misses and 2 expirations, got %+v", stats)
+	}
+}
+
+func TestExpiringPutWithTTL(t *testing.T) {
+	c, clock := newTestExpiring(t, 10, 0, nil)
+
+	c.Put("forever", 1)
+	c.PutWithTTL("short", 2, time.Second)
+
+	if ttl, ok := c.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("Expected no TTL for forever, got %v, %v", ttl, ok)
+	}
+	if ttl, ok := c.TTL("short"); !ok || ttl != time.Second {
+		t.Errorf("Expected 1s TTL for short, got %v, %v", ttl, ok)
+	}
+
+	clock.advance(2 * time.Second)
+
+	if _, ok := c.Get("short"); ok {
+		t.Error("Expected short to be expired")
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Error("Expected forever to be alive")
+	}
+
+	// 重新写入且不带 TTL 应清除过期时间
+	c.PutWithTTL("forever", 3, time.Second)
+	c.Put("forever", 4)
+	clock.advance(time.Hour)
+	if v, ok := c.Get("forever"); !ok || v != 4 {
+		t.Errorf("Expected forever=4, got %v, %v", v, ok)
+	}
+}
+
+func TestExpiringEvictReasons(t *testing.T) {
+	reasons := map[string]cache.EvictReason{}
+	c, clock := newTestExpiring(t, 2, 0, func(k string, v int, reason cache.EvictReason) {
+		reasons[k] = reason
+	})
+
+	c.PutWithTTL("a", 1, time.Second)
+	c.Put("b", 2)
+	if !c.Put("c", 3) {
+		t.Error("Expected capacity eviction")
+	}
+	if reasons["a"] != cache.EvictReasonCapacity {
+		t.Errorf("Expected a evicted for capacity, got %v", reasons["a"])
+	}
+
+	c.PutWithTTL("d", 4, time.Second)
+	clock.advance(time.Second)
+	c.Get("d")
+	if reasons["d"] != cache.EvictReasonExpired {
+		t.Errorf("Expected d expired, got %v", reasons["d"])
+	}
+
+	// Remove 和 Clear 不触发回调
+	c.Remove("c")
+	c.Clear()
+	if _, ok := reasons["c"]; ok {
+		t.Error("Expected no callback for Remove")
+	}
+
+	stats := c.CacheStats()
+	if stats.Evictions != 2 || stats.Expirations != 1 {
+		t.Errorf("Expected 2 evictions and 1 expiration, got %+v", stats)
+	}
+}
+
+func TestExpiringEvictOnGet(t *testing.T) {
+	evicted := map[string]cache.EvictReason{}
+	c, err := cache.NewExpiringWithEvict[string, int](func(fn func(string, int)) (cache.Cache[string, int], error) {
+		// 容量 10：窗口 1，probation 2，protected 7
+		return wtinylfu.NewWithEvict[string, int](10, fn)
+	}, 0, func(k string, v int, reason cache.EvictReason) {
+		evicted[k] = reason
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	keys := []string{"a", "b", "c", "d"}
+	for _, k := range keys {
+		c.Put(k, 1)
+	}
+
+	// d 在窗口中、probation 已满：wtinylfu 在 Get 中晋升 d 时淘汰 probation 的受害者
+	before := len(evicted)
+	if _, ok := c.Get("d"); !ok {
+		t.Fatal("Expected d to be cached")
+	}
+	if len(evicted) == before {
+		t.Fatal("Expected wtinylfu to evict during Get")
+	}
+
+	for _, k := range keys {
+		reason, reported := evicted[k]
+		if c.Contains(k) == reported {
+			t.Errorf("Key %s: present=%v reported=%v", k, c.Contains(k), reported)
+		}
+		if reported && reason != cache.EvictReasonCapacity {
+			t.Errorf("Expected %s evicted for capacity, got %v", k, reason)
+		}
+	}
+
+	if stats := c.CacheStats(); stats.Evictions != uint64(len(evicted)) {
+		t.Errorf("Expected %d evictions, got %d", len(evicted), stats.Evictions)
+	}
+}
+
+func TestExpiringListings(t *testing.T) {
+	c, clock := newTestExpiring(t, 10, 0, nil)
+
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, time.Second)
+	c.Put("c", 3)
+	clock.advance(time.Second)
+
+	if keys := c.Keys(); len(keys) != 2 {
+		t.Errorf("Expected 2 live keys, got %v", keys)
+	}
+	if values := c.Values(); len(values) != 2 {
+		t.Errorf("Expected 2 live values, got %v", values)
+	}
+	items := c.Items()
+	if _, ok := items["b"]; ok || len(items) != 2 {
+		t.Errorf("Expected b to be filtered out, got %v", items)
+	}
+	if _, ok := c.Remove("b"); ok {
+		t.Error("Expected Remove to miss expired key")
+	}
+}
+
+func TestExpiringDeleteExpired(t *testing.T) {
+	c, clock := newTestExpiring(t, 10, time.Second, nil)
+
+	for _, k := range []string{"a", "b", "c"} {
+		c.Put(k, 1)
+	}
+	c.PutWithTTL("d", 1, time.Hour)
+
+	clock.advance(time.Second)
+	if n := c.DeleteExpired(); n != 3 {
+		t.Errorf("Expected 3 expired entries, got %d", n)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Expected 1 remaining entry, got %d", c.Len())
+	}
+}
+
+func TestExpiringJanitor(t *testing.T) {
+	c, err := cache.NewExpiring[string, int](func(fn func(string, int)) (cache.Cache[string, int], error) {
+		return lru.NewWithEvict[string, int](10, fn)
+	}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	if err := c.StartJanitor(0); err == nil {
+		t.Error("Expected error for zero interval")
+	}
+	if err := c.StartJanitor(5 * time.Millisecond); err != nil {
+		t.Fatalf("Failed to start janitor: %v", err)
+	}
+	defer c.StopJanitor()
+
+	c.Put("a", 1)
+
+	deadline := time.Now().Add(time.Second)
+	for c.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if c.Len() != 0 {
+		t.Error("Expected janitor to remove expired entry")
+	}
+
+	c.StopJanitor()
+	c.StopJanitor()
+}
+
+func TestExpiringResize(t *testing.T) {
+	evicted := 0
+	c, _ := newTestExpiring(t, 3, 0, func(k string, v int, reason cache.EvictReason) {
+		if reason == cache.EvictReasonCapacity {
+			evicted++
+		}
+	})
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+
+	if err := c.Resize(1); err != nil {
+		t.Fatalf("Failed to resize: %v", err)
+	}
+	if evicted != 2 || c.Cap() != 1 {
+		t.Errorf("Expected 2 evictions and capacity 1, got %d, %d", evicted, c.Cap())
+	}
+}
+
+func TestEvictReasonString(t *testing.T) {
+	if cache.EvictReasonCapacity.String() != "capacity" || cache.EvictReasonExpired.String() != "expired" {
+		t.Error("Unexpected reason names")
+	}
+	if cache.EvictReason(0).String() != "EvictReason(0)" {
+		t.Errorf("Unexpected unknown reason name: %s", cache.EvictReason(0).String())
+	}
+}
@@ -0,0 +1,21 @@
+package cache
+
+import "time"
+
+// SetNow replaces the clock used for expiration in tests
+func (c *Expiring[K, V]) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// SetNow replaces the clock used for negative caching and refresh in tests
+func (c *Loading[K, V]) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// TrackedKeys returns the number of keys with a recorded load time
+func (c *Loading[K, V]) TrackedKeys() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.loadedAt)
+}
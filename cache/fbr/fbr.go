@@ -4,6 +4,8 @@
 	"container/list"
 	"fmt"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents a Frequency-Based Replacement cache
@@ -16,6 +18,7 @@ type Cache[K comparable, V any] struct {
 	maxFreq     int
 	mu          sync.RWMutex
 	onEvict     func(K, V)
+	stats       cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -58,9 +61,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 
 	if entry, exists := c.items[key]; exists {
 		c.incrementFrequency(entry)
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -280,6 +285,7 @@ func (c *Cache[K, V]) evictLeastFrequent() bool {
 		entry := element.Value.(*entry[K, V])
 		c.frequencies[c.minFreq].Remove(element)
 		delete(c.items, entry.key)
+		c.stats.RecordEviction()
 
 		if c.onEvict != nil {
 			c.onEvict(entry.key, entry.value)
@@ -332,6 +338,14 @@ func (c *Cache[K, V]) Stats() Stats {
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
 	Size                  int         // actual cache size
@@ -2,7 +2,10 @@
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents an LFU cache
@@ -13,6 +16,7 @@ type Cache[K comparable, V any] struct {
 	minFreq   int
 	mu        sync.RWMutex
 	onEvict   func(K, V)
+	stats     cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -62,6 +66,7 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.mu.RUnlock()
 
 	if !exists {
+		c.stats.RecordMiss()
 		var zero V
 		return zero, false
 	}
@@ -70,6 +75,7 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.incrementFreq(entry)
 	c.mu.Unlock()
 
+	c.stats.RecordHit()
 	return entry.value, true
 }
 
@@ -280,6 +286,7 @@ func (c *Cache[K, V]) evictLFU() {
 	// Remove the least recently used item among those with minimum frequency
 	entry := freqList.tail
 	if entry != nil {
+		c.stats.RecordEviction()
 		c.removeEntry(entry)
 	}
 }
@@ -368,6 +375,75 @@ func (l *freqList[K, V]) remove(entry *entry[K, V]) {
 	l.size--
 }
 
+// Snapshot returns all entries ordered by frequency, lowest first,
+// and from least to most recently used within the same frequency
+func (c *Cache[K, V]) Snapshot() []cache.SnapshotEntry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	freqs := make([]int, 0, len(c.freqMap))
+	for freq, list := range c.freqMap {
+		if list.size > 0 {
+			freqs = append(freqs, freq)
+		}
+	}
+	sort.Ints(freqs)
+
+	entries := make([]cache.SnapshotEntry[K, V], 0, len(c.items))
+	for _, freq := range freqs {
+		for entry := c.freqMap[freq].tail; entry != nil; entry = entry.prev {
+			entries = append(entries, cache.SnapshotEntry[K, V]{
+				Key:       entry.key,
+				Value:     entry.value,
+				Frequency: uint64(entry.freq), // #nosec G115 -- freq starts at 1 and only grows
+			})
+		}
+	}
+	return entries
+}
+
+// Restore replaces the cache contents with entries ordered from least to most valuable,
+// keeping their frequencies (a zero frequency counts as 1).
+// Only the most valuable entries that fit the capacity are kept; no eviction callbacks are fired.
+func (c *Cache[K, V]) Restore(entries []cache.SnapshotEntry[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*entry[K, V], c.capacity)
+	c.freqMap = make(map[int]*freqList[K, V])
+	c.minFreq = 1
+
+	if len(entries) > c.capacity {
+		entries = entries[len(entries)-c.capacity:]
+	}
+
+	for _, e := range entries {
+		if existing, exists := c.items[e.Key]; exists {
+			existing.value = e.Value
+			continue
+		}
+
+		freq := 1
+		if e.Frequency > 1 {
+			freq = int(e.Frequency) // #nosec G115 -- frequencies come from Snapshot
+		}
+
+		entry := &entry[K, V]{key: e.Key, value: e.Value, freq: freq}
+		list := c.freqMap[freq]
+		if list == nil {
+			list = &freqList[K, V]{}
+			c.freqMap[freq] = list
+		}
+		list.pushFront(entry)
+		entry.freqList = list
+
+		if len(c.items) == 0 || freq < c.minFreq {
+			c.minFreq = freq
+		}
+		c.items[e.Key] = entry
+	}
+}
+
 // Stats returns cache statistics
 func (c *Cache[K, V]) Stats() Stats {
 	c.mu.RLock()
@@ -388,6 +464,14 @@ func (c *Cache[K, V]) Stats() Stats {
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
 	Size             int
@@ -3,6 +3,8 @@
 import (
 	"sync"
 	"testing"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 func TestNew(t *testing.T) {
@@ -920,3 +922,46 @@ func TestAllEntriesZeroFreqBranch(t *testing.T) {
 		t.Errorf("Expected minFreq = 1 when all entries have freq 0, got %d", cache.minFreq)
 	}
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	c, _ := New[string, int](3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+
+	entries := c.Snapshot()
+	if len(entries) != 3 || entries[0].Key != "c" || entries[2].Key != "a" || entries[2].Frequency != 3 {
+		t.Fatalf("Expected entries ordered by frequency, got %v", entries)
+	}
+
+	restored, _ := New[string, int](3)
+	restored.Restore(entries)
+	if restored.GetFreq("a") != 3 || restored.GetFreq("b") != 2 || restored.GetFreq("c") != 1 {
+		t.Errorf("Expected frequencies to be restored, got a=%d b=%d c=%d",
+			restored.GetFreq("a"), restored.GetFreq("b"), restored.GetFreq("c"))
+	}
+
+	restored.Put("d", 4)
+	if restored.Contains("c") || !restored.Contains("a") {
+		t.Errorf("Expected least frequent c to be evicted, got %v", restored.Keys())
+	}
+}
+
+func TestRestoreTruncatesToCapacity(t *testing.T) {
+	c, _ := New[string, int](2)
+	c.Restore([]cache.SnapshotEntry[string, int]{
+		{Key: "a", Value: 1, Frequency: 1},
+		{Key: "b", Value: 2, Frequency: 5},
+		{Key: "c", Value: 3},
+	})
+
+	if c.Len() != 2 || c.Contains("a") {
+		t.Errorf("Expected the two most valuable entries, got %v", c.Keys())
+	}
+	if c.GetFreq("c") != 1 {
+		t.Errorf("Expected zero frequency to restore as 1, got %d", c.GetFreq("c"))
+	}
+}
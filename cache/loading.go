@@ -0,0 +1,483 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoadingConfig configures a Loading cache
+type LoadingConfig[K comparable, V any] struct {
+	// NegativeTTL caches loader errors for this long; <= 0 disables negative caching
+	NegativeTTL time.Duration
+
+	// RefreshAfter reloads a value in the background once it is older than this,
+	// while callers keep receiving the stale value; <= 0 disables refresh-ahead
+	RefreshAfter time.Duration
+
+	// BatchLoader loads several keys in one call for GetAll.
+	// Keys missing from the returned map are treated as not found.
+	// If nil, GetAll falls back to calling the single-key loader concurrently.
+	BatchLoader func(ctx context.Context, keys []K) (map[K]V, error)
+}
+
+// Loading wraps a Cache and fills misses through a loader function.
+// Concurrent misses on the same key share a single loader call.
+// All Cache methods are available and operate on the underlying cache directly.
+type Loading[K comparable, V any] struct {
+	Cache[K, V]
+
+	loader func(ctx context.Context, key K) (V, error)
+	config LoadingConfig[K, V]
+
+	stats StatsCounter
+
+	mu       sync.Mutex
+	calls    map[K]*loadCall[V] // 正在进行的加载（含后台刷新）
+	failures map[K]loadFailure  // 负缓存：加载失败的错误及其过期时间
+	loadedAt map[K]int64        // 值的加载时间（UnixNano），用于提前刷新
+	now      func() time.Time
+}
+
+// loadCall represents an in-flight loader call shared by all waiters of a key
+type loadCall[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+
+	// stale 加载期间键被 Put/Remove/Clear 改写，结果只交给等待者，不写入缓存
+	stale bool
+}
+
+// loadFailure represents a negatively cached loader error
+type loadFailure struct {
+	err   error
+	until int64
+}
+
+// NewLoading creates a Loading cache on top of c
+func NewLoading[K comparable, V any](c Cache[K, V], loader func(ctx context.Context, key K) (V, error)) (*Loading[K, V], error) {
+	return NewLoadingWithConfig(c, loader, LoadingConfig[K, V]{})
+}
+
+// NewLoadingWithConfig creates a Loading cache on top of c with custom configuration
+func NewLoadingWithConfig[K comparable, V any](c Cache[K, V], loader func(ctx context.Context, key K) (V, error), config LoadingConfig[K, V]) (*Loading[K, V], error) {
+	if c == nil {
+		return nil, fmt.Errorf("cache must not be nil")
+	}
+	if loader == nil {
+		return nil, fmt.Errorf("loader must not be nil")
+	}
+
+	return &Loading[K, V]{
+		Cache:    c,
+		loader:   loader,
+		config:   config,
+		calls:    make(map[K]*loadCall[V]),
+		failures: make(map[K]loadFailure),
+		loadedAt: make(map[K]int64),
+		now:      time.Now,
+	}, nil
+}
+
+// GetOrLoad returns the cached value for key, loading it on a miss.
+// The loader runs with context.WithoutCancel(ctx): it is shared by every caller of the key,
+// so one caller's cancellation must not fail the others. A caller whose ctx is done
+// returns early with ctx.Err(); the load itself keeps running and still fills the cache.
+func (c *Loading[K, V]) GetOrLoad(ctx context.Context, key K) (V, error) {
+	if value, ok := c.Cache.Get(key); ok {
+		c.maybeRefresh(ctx, key)
+		return value, nil
+	}
+
+	c.mu.Lock()
+	call, owner, hit, value, err := c.startLocked(key)
+	c.mu.Unlock()
+
+	if hit {
+		return value, err
+	}
+	if owner {
+		go c.load(context.WithoutCancel(ctx), key, call)
+	}
+
+	return c.wait(ctx, call)
+}
+
+// startLocked either returns a cached result (hit), joins an in-flight call,
+// or registers a new call owned by the caller
+func (c *Loading[K, V]) startLocked(key K) (call *loadCall[V], owner, hit bool, value V, err error) {
+	// 拿锁期间可能已有其他协程加载完成
+	if value, ok := c.Cache.Peek(key); ok {
+		return nil, false, true, value, nil
+	}
+
+	delete(c.loadedAt, key)
+
+	if failure, ok := c.failures[key]; ok {
+		if c.now().UnixNano() < failure.until {
+			return nil, false, true, value, failure.err
+		}
+		delete(c.failures, key)
+	}
+
+	if call, ok := c.calls[key]; ok {
+		return call, false, false, value, nil
+	}
+
+	call = &loadCall[V]{done: make(chan struct{})}
+	c.calls[key] = call
+	return call, true, false, value, nil
+}
+
+// wait blocks until call finishes or ctx is done
+func (c *Loading[K, V]) wait(ctx context.Context, call *loadCall[V]) (V, error) {
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// load runs the loader for key and publishes the result to call
+func (c *Loading[K, V]) load(ctx context.Context, key K, call *loadCall[V]) {
+	call.val, call.err = c.invoke(ctx, key)
+	c.finish(key, call, call.val, call.err, true)
+}
+
+// invoke calls the loader, converting a panic into an error
+func (c *Loading[K, V]) invoke(ctx context.Context, key K) (value V, err error) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("loader panic: %v", r)
+		}
+		c.recordLoad(time.Since(start), err)
+	}()
+
+	return c.loader(ctx, key)
+}
+
+// finish stores a load result and releases the waiters of call.
+// Context errors are never negatively cached: they describe the call, not the key.
+func (c *Loading[K, V]) finish(key K, call *loadCall[V], value V, err error, negative bool) {
+	c.mu.Lock()
+	switch {
+	case call.stale:
+		// 键在加载期间被改写，写入结果会覆盖更新的状态
+	case err == nil:
+		c.Cache.Put(key, value)
+		c.loadedAt[key] = c.now().UnixNano()
+		delete(c.failures, key)
+		c.pruneLocked()
+	case negative && c.config.NegativeTTL > 0 && !isContextError(err):
+		c.failures[key] = loadFailure{
+			err:   err,
+			until: c.now().Add(c.config.NegativeTTL).UnixNano(),
+		}
+		c.pruneLocked()
+	}
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// invalidateLocked marks the in-flight call for key stale and detaches it,
+// so that later callers start a fresh load
+func (c *Loading[K, V]) invalidateLocked(key K) {
+	if call, ok := c.calls[key]; ok {
+		call.stale = true
+		delete(c.calls, key)
+	}
+}
+
+// isContextError reports whether err comes from a cancelled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// pruneLocked drops bookkeeping for keys the underlying policy has evicted.
+// 内部缓存淘汰时不会通知 Loading，因此在记录数明显超过容量时批量清理
+func (c *Loading[K, V]) pruneLocked() {
+	limit := 2 * c.Cache.Cap()
+	if len(c.loadedAt) <= limit && len(c.failures) <= limit {
+		return
+	}
+
+	for key := range c.loadedAt {
+		if !c.Cache.Contains(key) {
+			delete(c.loadedAt, key)
+		}
+	}
+
+	now := c.now().UnixNano()
+	for key, failure := range c.failures {
+		if now >= failure.until {
+			delete(c.failures, key)
+		}
+	}
+}
+
+// maybeRefresh starts a background reload of key if its value is older than RefreshAfter
+func (c *Loading[K, V]) maybeRefresh(ctx context.Context, key K) {
+	if c.config.RefreshAfter <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	loadedAt, ok := c.loadedAt[key]
+	if !ok || c.now().UnixNano()-loadedAt < int64(c.config.RefreshAfter) {
+		c.mu.Unlock()
+		return
+	}
+	if _, loading := c.calls[key]; loading {
+		c.mu.Unlock()
+		return
+	}
+	call := &loadCall[V]{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	// 刷新不应随请求取消而中断，但保留 ctx 中的值
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		value, err := c.invoke(ctx, key)
+		// 刷新失败时保留旧值，不写入负缓存
+		call.val, call.err = value, err
+		c.finish(key, call, value, err, false)
+	}()
+}
+
+// GetAll returns the values for keys, loading all misses together.
+// Keys that could not be loaded are absent from the result; their errors are joined,
+// except that keys missing from the BatchLoader result (ErrNotFound) are only absent.
+func (c *Loading[K, V]) GetAll(ctx context.Context, keys []K) (map[K]V, error) {
+	result := make(map[K]V, len(keys))
+
+	var misses []K
+	for _, key := range keys {
+		if value, ok := c.Cache.Get(key); ok {
+			c.maybeRefresh(ctx, key)
+			result[key] = value
+			continue
+		}
+		misses = append(misses, key)
+	}
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	if c.config.BatchLoader == nil {
+		return c.getAllSingle(ctx, misses, result)
+	}
+	return c.getAllBatch(ctx, misses, result)
+}
+
+// getAllSingle loads misses concurrently through the single-key loader
+func (c *Loading[K, V]) getAllSingle(ctx context.Context, misses []K, result map[K]V) (map[K]V, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	seen := make(map[K]struct{}, len(misses))
+	for _, key := range misses {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		wg.Add(1)
+		go func(key K) {
+			defer wg.Done()
+
+			value, err := c.GetOrLoad(ctx, key)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("load %v: %w", key, err))
+				return
+			}
+			result[key] = value
+		}(key)
+	}
+	wg.Wait()
+
+	return result, errors.Join(errs...)
+}
+
+// getAllBatch loads misses with a single BatchLoader call,
+// joining loads already in flight instead of repeating them
+func (c *Loading[K, V]) getAllBatch(ctx context.Context, misses []K, result map[K]V) (map[K]V, error) {
+	var (
+		errs    []error
+		owned   = make(map[K]*loadCall[V])
+		joined  = make(map[K]*loadCall[V])
+		ownKeys []K
+	)
+
+	c.mu.Lock()
+	for _, key := range misses {
+		if _, ok := owned[key]; ok {
+			continue
+		}
+		if _, ok := joined[key]; ok {
+			continue
+		}
+
+		call, owner, hit, value, err := c.startLocked(key)
+		switch {
+		case hit && err != nil:
+			errs = append(errs, fmt.Errorf("load %v: %w", key, err))
+		case hit:
+			result[key] = value
+		case owner:
+			owned[key] = call
+			ownKeys = append(ownKeys, key)
+		default:
+			joined[key] = call
+		}
+	}
+	c.mu.Unlock()
+
+	if len(ownKeys) > 0 {
+		// 与 GetOrLoad 相同：其他调用方可能在等待这些键，批量加载不随 ctx 取消
+		go c.loadBatch(context.WithoutCancel(ctx), ownKeys, owned)
+	}
+
+	for key, call := range owned {
+		value, err := c.wait(ctx, call)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				errs = append(errs, fmt.Errorf("load %v: %w", key, err))
+			}
+			continue
+		}
+		result[key] = value
+	}
+
+	// 加入其他批次的键同样只是缺席，结果不随先后顺序变化
+	for key, call := range joined {
+		value, err := c.wait(ctx, call)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				errs = append(errs, fmt.Errorf("load %v: %w", key, err))
+			}
+			continue
+		}
+		result[key] = value
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// loadBatch runs the batch loader for keys and publishes each result to its call
+func (c *Loading[K, V]) loadBatch(ctx context.Context, keys []K, calls map[K]*loadCall[V]) {
+	values, err := c.invokeBatch(ctx, keys)
+	for _, key := range keys {
+		call := calls[key]
+		value, ok := values[key]
+		switch {
+		case err != nil:
+			call.err = err
+		case !ok:
+			call.err = ErrNotFound
+		default:
+			call.val = value
+		}
+		c.finish(key, call, call.val, call.err, !errors.Is(call.err, ErrNotFound))
+	}
+}
+
+// invokeBatch calls the batch loader, converting a panic into an error
+func (c *Loading[K, V]) invokeBatch(ctx context.Context, keys []K) (values map[K]V, err error) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("batch loader panic: %v", r)
+		}
+		c.recordLoad(time.Since(start), err)
+	}()
+
+	return c.config.BatchLoader(ctx, keys)
+}
+
+// recordLoad records the outcome of one loader call
+func (c *Loading[K, V]) recordLoad(d time.Duration, err error) {
+	if err != nil {
+		c.stats.RecordLoadFailure(d)
+		return
+	}
+	c.stats.RecordLoadSuccess(d)
+}
+
+// CacheStats returns the underlying cache's statistics combined with loader statistics.
+// Hits, misses and evictions come from the underlying cache if it implements StatsProvider;
+// a batch load counts as a single loader call.
+func (c *Loading[K, V]) CacheStats() Stats {
+	var stats Stats
+	if provider, ok := c.Cache.(StatsProvider); ok {
+		stats = provider.CacheStats()
+	} else {
+		stats.Size = c.Cache.Len()
+		stats.Capacity = c.Cache.Cap()
+		weightOf(c.Cache, &stats)
+	}
+
+	loads := c.stats.Snapshot()
+	stats.LoadSuccesses = loads.LoadSuccesses
+	stats.LoadFailures = loads.LoadFailures
+	stats.TotalLoadTime = loads.TotalLoadTime
+	return stats
+}
+
+// Put adds or updates a value and resets its refresh timer and negative cache entry.
+// A load of key still in flight will not overwrite the value.
+func (c *Loading[K, V]) Put(key K, value V) (evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidateLocked(key)
+	c.loadedAt[key] = c.now().UnixNano()
+	delete(c.failures, key)
+	evicted = c.Cache.Put(key, value)
+	c.pruneLocked()
+	return evicted
+}
+
+// Remove removes a key and forgets any negatively cached error for it.
+// A load of key still in flight, including a background refresh, will not re-insert it.
+func (c *Loading[K, V]) Remove(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidateLocked(key)
+	delete(c.loadedAt, key)
+	delete(c.failures, key)
+	return c.Cache.Remove(key)
+}
+
+// Clear removes all items and negatively cached errors
+func (c *Loading[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.calls {
+		c.invalidateLocked(key)
+	}
+	clear(c.loadedAt)
+	clear(c.failures)
+	c.Cache.Clear()
+}
+
+// ErrNotFound is returned by a batch load for keys missing from the BatchLoader result
+var ErrNotFound = errors.New("cache: key not found")
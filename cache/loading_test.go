@@ -0,0 +1,469 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/cache/lru"
+)
+
+func newTestLoading(t *testing.T, loader func(ctx context.Context, key string) (int, error), config cache.LoadingConfig[string, int]) *cache.Loading[string, int] {
+	t.Helper()
+
+	inner, err := lru.New[string, int](100)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	c, err := cache.NewLoadingWithConfig[string, int](inner, loader, config)
+	if err != nil {
+		t.Fatalf("Failed to create loading cache: %v", err)
+	}
+	return c
+}
+
+func TestNewLoadingError(t *testing.T) {
+	inner, _ := lru.New[string, int](1)
+	if _, err := cache.NewLoading[string, int](nil, func(ctx context.Context, key string) (int, error) { return 0, nil }); err == nil {
+		t.Error("Expected error for nil cache")
+	}
+	if _, err := cache.NewLoading[string, int](inner, nil); err == nil {
+		t.Error("Expected error for nil loader")
+	}
+}
+
+func TestLoadingGetOrLoad(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		return len(key), nil
+	}, cache.LoadingConfig[string, int]{})
+
+	v, err := c.GetOrLoad(context.Background(), "abc")
+	if err != nil || v != 3 {
+		t.Errorf("Expected 3, got %v, %v", v, err)
+	}
+	v, err = c.GetOrLoad(context.Background(), "abc")
+	if err != nil || v != 3 {
+		t.Errorf("Expected 3, got %v, %v", v, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 loader call, got %d", calls.Load())
+	}
+	if !c.Contains("abc") {
+		t.Error("Expected loaded value to be cached")
+	}
+}
+
+func TestLoadingDeduplicatesConcurrentLoads(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}, cache.LoadingConfig[string, int]{})
+
+	var wg sync.WaitGroup
+	results := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _ := c.GetOrLoad(context.Background(), "k")
+			results <- v
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for v := range results {
+		if v != 42 {
+			t.Errorf("Expected 42, got %d", v)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 loader call, got %d", calls.Load())
+	}
+}
+
+func TestLoadingWaiterContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		<-release
+		return 1, nil
+	}, cache.LoadingConfig[string, int]{})
+
+	go c.GetOrLoad(context.Background(), "k")
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetOrLoad(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestLoadingOwnerContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}, cache.LoadingConfig[string, int]{NegativeTTL: time.Minute})
+
+	// 发起加载的调用方取消，不影响共享同一次加载的其他调用方
+	ctx, cancel := context.WithCancel(context.Background())
+	ownerDone := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad(ctx, "k")
+		ownerDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	waiterDone := make(chan int, 1)
+	go func() {
+		v, _ := c.GetOrLoad(context.Background(), "k")
+		waiterDone <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-ownerDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected owner to return context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if v := <-waiterDone; v != 1 {
+		t.Errorf("Expected waiter to receive 1, got %d", v)
+	}
+	if v, ok := c.Peek("k"); !ok || v != 1 {
+		t.Errorf("Expected loaded value to be cached, got %v, %v", v, ok)
+	}
+}
+
+func TestLoadingContextErrorNotNegativelyCached(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, fmt.Errorf("query: %w", context.DeadlineExceeded)
+		}
+		return 2, nil
+	}, cache.LoadingConfig[string, int]{NegativeTTL: time.Minute})
+
+	if _, err := c.GetOrLoad(context.Background(), "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if v, err := c.GetOrLoad(context.Background(), "k"); err != nil || v != 2 {
+		t.Errorf("Expected reload after context error, got %v, %v", v, err)
+	}
+}
+
+func TestLoadingRemoveDuringLoad(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var version atomic.Int32
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		v := int(version.Add(1))
+		if v == 2 {
+			started <- struct{}{}
+			<-release
+		}
+		return v, nil
+	}, cache.LoadingConfig[string, int]{RefreshAfter: time.Minute})
+
+	var mu sync.Mutex
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	c.SetNow(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock.now()
+	})
+
+	c.GetOrLoad(context.Background(), "k")
+	mu.Lock()
+	clock.advance(time.Minute)
+	mu.Unlock()
+
+	// 触发后台刷新，刷新进行中删除键
+	c.GetOrLoad(context.Background(), "k")
+	<-started
+	c.Remove("k")
+	close(release)
+
+	// 刷新结果不能把已删除的键写回；之后的加载重新开始
+	v, err := c.GetOrLoad(context.Background(), "k")
+	if err != nil || v != 3 {
+		t.Errorf("Expected fresh load 3 after Remove, got %v, %v", v, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if v, _ := c.Peek("k"); v != 3 {
+		t.Errorf("Expected refresh result to be discarded, got %d", v)
+	}
+}
+
+func TestLoadingNegativeCache(t *testing.T) {
+	var calls atomic.Int32
+	boom := errors.New("boom")
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		return 0, boom
+	}, cache.LoadingConfig[string, int]{NegativeTTL: time.Minute})
+
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	c.SetNow(clock.now)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetOrLoad(context.Background(), "k"); !errors.Is(err, boom) {
+			t.Errorf("Expected boom, got %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 loader call while negatively cached, got %d", calls.Load())
+	}
+
+	clock.advance(time.Minute)
+	c.GetOrLoad(context.Background(), "k")
+	if calls.Load() != 2 {
+		t.Errorf("Expected reload after negative TTL, got %d calls", calls.Load())
+	}
+
+	c.Remove("k")
+	c.GetOrLoad(context.Background(), "k")
+	if calls.Load() != 3 {
+		t.Errorf("Expected Remove to drop negative entry, got %d calls", calls.Load())
+	}
+}
+
+func TestLoadingWithoutNegativeCache(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		return 0, errors.New("boom")
+	}, cache.LoadingConfig[string, int]{})
+
+	c.GetOrLoad(context.Background(), "k")
+	c.GetOrLoad(context.Background(), "k")
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 loader calls, got %d", calls.Load())
+	}
+}
+
+func TestLoadingPanic(t *testing.T) {
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		panic("bad loader")
+	}, cache.LoadingConfig[string, int]{})
+
+	if _, err := c.GetOrLoad(context.Background(), "k"); err == nil {
+		t.Error("Expected panic to be converted to error")
+	}
+}
+
+func TestLoadingRefreshAhead(t *testing.T) {
+	var version atomic.Int32
+	refreshed := make(chan struct{}, 1)
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		v := int(version.Add(1))
+		if v > 1 {
+			refreshed <- struct{}{}
+		}
+		return v, nil
+	}, cache.LoadingConfig[string, int]{RefreshAfter: time.Minute})
+
+	var mu sync.Mutex
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	c.SetNow(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock.now()
+	})
+
+	if v, _ := c.GetOrLoad(context.Background(), "k"); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+
+	mu.Lock()
+	clock.advance(time.Minute)
+	mu.Unlock()
+
+	// 超过刷新阈值后仍返回旧值，同时后台刷新
+	if v, _ := c.GetOrLoad(context.Background(), "k"); v != 1 {
+		t.Errorf("Expected stale value 1, got %d", v)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected background refresh")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := c.Peek("k"); v == 2 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected refreshed value 2")
+}
+
+func TestLoadingGetAllSingle(t *testing.T) {
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		if key == "bad" {
+			return 0, errors.New("bad key")
+		}
+		return len(key), nil
+	}, cache.LoadingConfig[string, int]{})
+	c.Put("a", 100)
+
+	values, err := c.GetAll(context.Background(), []string{"a", "bb", "ccc", "bb", "bad"})
+	if err == nil {
+		t.Error("Expected joined error for bad key")
+	}
+	if len(values) != 3 || values["a"] != 100 || values["bb"] != 2 || values["ccc"] != 3 {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
+
+func TestLoadingGetAllBatch(t *testing.T) {
+	var batches [][]string
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		t.Error("Single loader should not be used")
+		return 0, nil
+	}, cache.LoadingConfig[string, int]{
+		BatchLoader: func(ctx context.Context, keys []string) (map[string]int, error) {
+			batches = append(batches, append([]string(nil), keys...))
+			result := make(map[string]int, len(keys))
+			for _, key := range keys {
+				if key != "missing" {
+					result[key] = len(key)
+				}
+			}
+			return result, nil
+		},
+	})
+	c.Put("a", 100)
+
+	values, err := c.GetAll(context.Background(), []string{"a", "bb", "ccc", "missing", "bb"})
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if len(values) != 3 || values["bb"] != 2 || values["ccc"] != 3 {
+		t.Errorf("Unexpected values %v", values)
+	}
+
+	if len(batches) != 1 {
+		t.Fatalf("Expected 1 batch call, got %d", len(batches))
+	}
+	sort.Strings(batches[0])
+	if fmt.Sprint(batches[0]) != "[bb ccc missing]" {
+		t.Errorf("Unexpected batch keys %v", batches[0])
+	}
+
+	values, _ = c.GetAll(context.Background(), []string{"bb", "ccc"})
+	if len(values) != 2 || len(batches) != 1 {
+		t.Errorf("Expected cached values without batch call, got %v, %d batches", values, len(batches))
+	}
+}
+
+func TestLoadingGetAllBatchConcurrentMissing(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		return 0, nil
+	}, cache.LoadingConfig[string, int]{
+		BatchLoader: func(ctx context.Context, keys []string) (map[string]int, error) {
+			if calls.Add(1) == 1 {
+				<-release
+			}
+			result := make(map[string]int, len(keys))
+			for _, key := range keys {
+				if key != "missing" {
+					result[key] = len(key)
+				}
+			}
+			return result, nil
+		},
+	})
+
+	// 第一个调用持有 missing 的加载，其余调用加入它；缺失的键对所有调用方都只是缺席
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	values := make([]map[string]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], errs[i] = c.GetAll(context.Background(), []string{"missing", "bb"})
+		}(i)
+		if i == 0 {
+			for calls.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Errorf("GetAll %d: unexpected error %v", i, errs[i])
+		}
+		if len(values[i]) != 1 || values[i]["bb"] != 2 {
+			t.Errorf("GetAll %d: unexpected values %v", i, values[i])
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 batch call, got %d", calls.Load())
+	}
+}
+
+func TestLoadingGetAllBatchError(t *testing.T) {
+	boom := errors.New("boom")
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		return 0, nil
+	}, cache.LoadingConfig[string, int]{
+		NegativeTTL: time.Minute,
+		BatchLoader: func(ctx context.Context, keys []string) (map[string]int, error) {
+			return nil, boom
+		},
+	})
+
+	values, err := c.GetAll(context.Background(), []string{"a", "b"})
+	if !errors.Is(err, boom) || len(values) != 0 {
+		t.Errorf("Expected boom and no values, got %v, %v", values, err)
+	}
+
+	// 失败结果进入负缓存，单键读取直接返回错误
+	if _, err := c.GetOrLoad(context.Background(), "a"); !errors.Is(err, boom) {
+		t.Errorf("Expected negatively cached boom, got %v", err)
+	}
+}
+
+func TestLoadingClear(t *testing.T) {
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		return 1, nil
+	}, cache.LoadingConfig[string, int]{})
+
+	c.GetOrLoad(context.Background(), "a")
+	c.Clear()
+	if c.Len() != 0 || c.TrackedKeys() != 0 {
+		t.Errorf("Expected empty cache, got length %d", c.Len())
+	}
+}
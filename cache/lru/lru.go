@@ -4,6 +4,9 @@
 	"container/list"
 	"fmt"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/human"
 )
 
 // Cache represents an LRU cache
@@ -15,12 +18,19 @@ type Cache[K comparable, V any] struct {
 	evictList *list.List
 	mu        sync.Mutex
 	onEvict   func(K, V)
+	stats     cache.StatsCounter
+
+	// 按重量限制容量：weigher 为 nil 时只按条目数限制
+	weigher   cache.Weigher[K, V]
+	maxWeight int64
+	weight    int64
 }
 
 // entry represents a cache entry
 type entry[K comparable, V any] struct {
-	key   K
-	value V
+	key    K
+	value  V
+	weight int64
 }
 
 // New creates a new LRU cache with the given capacity
@@ -46,6 +56,26 @@ func NewWithEvict[K comparable, V any](capacity int, onEvict func(K, V)) (*Cache
 	return cache, nil
 }
 
+// NewWithWeigher creates a new LRU cache bounded by both the entry count and the total weight.
+// weigher returns the weight of each entry (e.g. its size in bytes); least recently used
+// entries are evicted until the total weight is at most maxWeight.
+func NewWithWeigher[K comparable, V any](capacity int, maxWeight int64, weigher cache.Weigher[K, V]) (*Cache[K, V], error) {
+	if maxWeight <= 0 {
+		return nil, fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+	if weigher == nil {
+		return nil, fmt.Errorf("weigher must not be nil")
+	}
+
+	cache, err := New[K, V](capacity)
+	if err != nil {
+		return nil, err
+	}
+	cache.weigher = weigher
+	cache.maxWeight = maxWeight
+	return cache, nil
+}
+
 // Get retrieves a value from the cache
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.mu.Lock()
@@ -55,9 +85,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 		// Move to front (most recently used)
 		c.evictList.MoveToFront(element)
 		entry := element.Value.(*entry[K, V])
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -67,27 +99,41 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	weight := c.weigh(key, value)
+	if c.maxWeight > 0 && weight > c.maxWeight {
+		// 单个条目超过重量上限，不缓存；旧值视为被淘汰
+		if element, exists := c.items[key]; exists {
+			c.stats.RecordEviction()
+			c.removeElement(element)
+			return true
+		}
+		return false
+	}
+
 	// Check if key already exists
 	if element, exists := c.items[key]; exists {
 		// Update existing entry
 		c.evictList.MoveToFront(element)
 		entry := element.Value.(*entry[K, V])
 		entry.value = value
-		return false
+		c.weight += weight - entry.weight
+		entry.weight = weight
+		return c.evictForWeight(element)
 	}
 
 	// Add new entry
-	entry := &entry[K, V]{key: key, value: value}
+	entry := &entry[K, V]{key: key, value: value, weight: weight}
 	element := c.evictList.PushFront(entry)
 	c.items[key] = element
+	c.weight += weight
 
 	// Check if we need to evict
 	if c.evictList.Len() > c.capacity {
 		c.removeOldest()
-		return true
+		evicted = true
 	}
 
-	return false
+	return c.evictForWeight(element) || evicted
 }
 
 // Remove removes a key from the cache
@@ -154,6 +200,7 @@ func (c *Cache[K, V]) Clear() {
 		delete(c.items, k)
 	}
 	c.evictList.Init()
+	c.weight = 0
 }
 
 // Keys returns all keys in the cache (from most to least recently used)
@@ -217,34 +264,165 @@ func (c *Cache[K, V]) Resize(capacity int) error {
 func (c *Cache[K, V]) removeOldest() {
 	element := c.evictList.Back()
 	if element != nil {
+		c.stats.RecordEviction()
 		c.removeElement(element)
 	}
 }
 
+// evictForWeight evicts least recently used entries until the total weight fits,
+// never evicting keep
+func (c *Cache[K, V]) evictForWeight(keep *list.Element) (evicted bool) {
+	for c.maxWeight > 0 && c.weight > c.maxWeight {
+		element := c.evictList.Back()
+		if element == nil || element == keep {
+			break
+		}
+		c.stats.RecordEviction()
+		c.removeElement(element)
+		evicted = true
+	}
+	return evicted
+}
+
+// weigh returns the weight of an entry, 0 when no weigher is configured
+func (c *Cache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 0
+	}
+	return max(0, c.weigher(key, value))
+}
+
+// Weight returns the total weight of all entries, 0 when no weigher is configured
+func (c *Cache[K, V]) Weight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.weight
+}
+
+// MaxWeight returns the weight limit, 0 when no weigher is configured
+func (c *Cache[K, V]) MaxWeight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.maxWeight
+}
+
+// ResizeWeight changes the weight limit, evicting least recently used entries until the total fits
+func (c *Cache[K, V]) ResizeWeight(maxWeight int64) error {
+	if maxWeight <= 0 {
+		return fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.weigher == nil {
+		return fmt.Errorf("cache has no weigher")
+	}
+
+	c.maxWeight = maxWeight
+	c.evictForWeight(nil)
+	return nil
+}
+
 // removeElement removes a specific element from the cache
 func (c *Cache[K, V]) removeElement(element *list.Element) {
 	c.evictList.Remove(element)
 	entry := element.Value.(*entry[K, V])
 	delete(c.items, entry.key)
+	c.weight -= entry.weight
 
 	if c.onEvict != nil {
 		c.onEvict(entry.key, entry.value)
 	}
 }
 
+// Snapshot returns all entries from least to most recently used
+func (c *Cache[K, V]) Snapshot() []cache.SnapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]cache.SnapshotEntry[K, V], 0, c.evictList.Len())
+	for element := c.evictList.Back(); element != nil; element = element.Prev() {
+		entry := element.Value.(*entry[K, V])
+		entries = append(entries, cache.SnapshotEntry[K, V]{Key: entry.key, Value: entry.value})
+	}
+	return entries
+}
+
+// Restore replaces the cache contents with entries ordered from least to most recently used.
+// Only the most recent entries that fit the capacity are kept; no eviction callbacks are fired.
+func (c *Cache[K, V]) Restore(entries []cache.SnapshotEntry[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element, min(len(entries), c.capacity))
+	c.evictList.Init()
+	c.weight = 0
+
+	for _, e := range entries[max(0, len(entries)-c.capacity):] {
+		weight := c.weigh(e.Key, e.Value)
+		if c.maxWeight > 0 && weight > c.maxWeight {
+			continue
+		}
+		if element, exists := c.items[e.Key]; exists {
+			c.evictList.MoveToFront(element)
+			entry := element.Value.(*entry[K, V])
+			entry.value = e.Value
+			c.weight += weight - entry.weight
+			entry.weight = weight
+			continue
+		}
+		c.items[e.Key] = c.evictList.PushFront(&entry[K, V]{key: e.Key, value: e.Value, weight: weight})
+		c.weight += weight
+	}
+
+	// 超出重量上限的最久未使用条目直接丢弃，不触发回调
+	for c.maxWeight > 0 && c.weight > c.maxWeight {
+		element := c.evictList.Back()
+		c.evictList.Remove(element)
+		entry := element.Value.(*entry[K, V])
+		delete(c.items, entry.key)
+		c.weight -= entry.weight
+	}
+}
+
 // Stats returns cache statistics
 func (c *Cache[K, V]) Stats() Stats {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	return Stats{
-		Size:     c.evictList.Len(),
-		Capacity: c.capacity,
+		Size:      c.evictList.Len(),
+		Capacity:  c.capacity,
+		Weight:    c.weight,
+		MaxWeight: c.maxWeight,
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	stats.Weight = c.Weight()
+	stats.MaxWeight = c.MaxWeight()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
-	Size     int
-	Capacity int
+	Size      int
+	Capacity  int
+	Weight    int64 // total weight of all entries, 0 without a weigher
+	MaxWeight int64 // weight limit, 0 without a weigher
+}
+
+// String returns a short summary; weights are printed as byte sizes
+func (s Stats) String() string {
+	if s.MaxWeight <= 0 {
+		return fmt.Sprintf("size=%d/%d", s.Size, s.Capacity)
+	}
+	return fmt.Sprintf("size=%d/%d weight=%s/%s", s.Size, s.Capacity, human.ByteSize(s.Weight), human.ByteSize(s.MaxWeight))
 }
@@ -1,8 +1,11 @@
 package lru
 
 import (
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 func TestNew(t *testing.T) {
@@ -548,3 +551,140 @@ func BenchmarkPutGet(b *testing.B) {
 		cache.Get(key)
 	}
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	c, _ := New[string, int](3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+
+	entries := c.Snapshot()
+	if len(entries) != 3 || entries[0].Key != "b" || entries[2].Key != "a" {
+		t.Fatalf("Expected least recently used first, got %v", entries)
+	}
+
+	restored, _ := New[string, int](2)
+	restored.Restore(entries)
+
+	keys := restored.Keys()
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Errorf("Expected most recent entries [a c], got %v", keys)
+	}
+
+	restored.Put("d", 4)
+	if restored.Contains("c") {
+		t.Error("Expected restored LRU order to evict c first")
+	}
+}
+
+func TestRestoreNoEvictCallback(t *testing.T) {
+	evicted := 0
+	c, _ := NewWithEvict[string, int](1, func(string, int) { evicted++ })
+	c.Put("old", 0)
+	c.Restore([]cache.SnapshotEntry[string, int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}})
+
+	if evicted != 0 {
+		t.Errorf("Expected no eviction callbacks, got %d", evicted)
+	}
+	if v, ok := c.Peek("b"); !ok || v != 2 || c.Len() != 1 {
+		t.Errorf("Expected only b to be restored, got %v", c.Items())
+	}
+}
+
+func TestNewWithWeigher(t *testing.T) {
+	weigher := func(key string, value []byte) int64 { return int64(len(value)) }
+	if _, err := NewWithWeigher[string, []byte](10, 0, weigher); err == nil {
+		t.Error("Expected error for non-positive max weight")
+	}
+	if _, err := NewWithWeigher[string, []byte](10, 100, nil); err == nil {
+		t.Error("Expected error for nil weigher")
+	}
+	if _, err := NewWithWeigher[string, []byte](0, 100, weigher); err == nil {
+		t.Error("Expected error for non-positive capacity")
+	}
+}
+
+func TestWeigherEviction(t *testing.T) {
+	var evicted []string
+	c, err := NewWithWeigher[string, []byte](100, 10, func(key string, value []byte) int64 { return int64(len(value)) })
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	c.onEvict = func(key string, value []byte) { evicted = append(evicted, key) }
+
+	c.Put("a", make([]byte, 4))
+	c.Put("b", make([]byte, 4))
+	if c.Weight() != 8 {
+		t.Errorf("Expected weight 8, got %d", c.Weight())
+	}
+
+	if !c.Put("c", make([]byte, 4)) {
+		t.Error("Expected Put to report a weight eviction")
+	}
+	if c.Weight() > c.MaxWeight() || !c.Contains("c") || len(evicted) != 1 {
+		t.Errorf("Expected one eviction keeping c, weight %d, evicted %v", c.Weight(), evicted)
+	}
+
+	// 更新为更大的值同样触发按重量淘汰
+	c.Put("c", make([]byte, 9))
+	if c.Weight() != 9 || c.Len() != 1 {
+		t.Errorf("Expected only c with weight 9, got %d entries weighing %d", c.Len(), c.Weight())
+	}
+
+	c.Remove("c")
+	if c.Weight() != 0 {
+		t.Errorf("Expected weight 0 after Remove, got %d", c.Weight())
+	}
+}
+
+func TestWeigherOversizedEntry(t *testing.T) {
+	c, _ := NewWithWeigher[string, []byte](100, 10, func(key string, value []byte) int64 { return int64(len(value)) })
+
+	c.Put("a", make([]byte, 5))
+	if c.Put("big", make([]byte, 11)) || c.Contains("big") {
+		t.Error("Expected entry heavier than max weight not to be cached")
+	}
+	if !c.Put("a", make([]byte, 11)) || c.Contains("a") {
+		t.Error("Expected oversized update to evict the old value")
+	}
+	if c.Weight() != 0 {
+		t.Errorf("Expected weight 0, got %d", c.Weight())
+	}
+}
+
+func TestResizeWeight(t *testing.T) {
+	c, _ := NewWithWeigher[string, []byte](100, 100, func(key string, value []byte) int64 { return int64(len(value)) })
+	for i := 0; i < 10; i++ {
+		c.Put(string(rune('a'+i)), make([]byte, 10))
+	}
+
+	if err := c.ResizeWeight(35); err != nil {
+		t.Fatalf("ResizeWeight failed: %v", err)
+	}
+	if c.Weight() > 35 || c.Len() != 3 {
+		t.Errorf("Expected 3 entries within weight 35, got %d weighing %d", c.Len(), c.Weight())
+	}
+	if err := c.ResizeWeight(0); err == nil {
+		t.Error("Expected error for non-positive max weight")
+	}
+
+	plain, _ := New[string, []byte](10)
+	if err := plain.ResizeWeight(10); err == nil {
+		t.Error("Expected error without weigher")
+	}
+	if plain.Weight() != 0 || plain.MaxWeight() != 0 {
+		t.Error("Expected zero weight without weigher")
+	}
+
+	stats := c.Stats()
+	if stats.Weight != c.Weight() || stats.MaxWeight != 35 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if s := stats.String(); !strings.Contains(s, "weight=30 B/35 B") {
+		t.Errorf("Unexpected stats string %q", s)
+	}
+	if cs := c.CacheStats(); cs.Weight != 30 || cs.MaxWeight != 35 {
+		t.Errorf("Unexpected cache stats %+v", cs)
+	}
+}
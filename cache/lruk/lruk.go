@@ -5,6 +5,8 @@
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents an LRU-K cache
@@ -16,6 +18,7 @@ type Cache[K comparable, V any] struct {
 	cache    *list.List // Main cache list
 	mu       sync.RWMutex
 	onEvict  func(K, V)
+	stats    cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -68,10 +71,12 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 		if entry.inCache {
 			// Move to front of cache list
 			c.cache.MoveToFront(entry.element)
+			c.stats.RecordHit()
 			return entry.value, true
 		}
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -292,6 +297,7 @@ func (c *Cache[K, V]) evictFromCache() bool {
 	element := c.cache.Back()
 	if element != nil {
 		entry := element.Value.(*entry[K, V])
+		c.stats.RecordEviction()
 		c.removeEntry(entry, true)
 		return true
 	}
@@ -342,6 +348,14 @@ func (c *Cache[K, V]) Stats() Stats {
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
 	Size         int // actual cache size (entries in main cache)
@@ -4,6 +4,8 @@
 	"container/list"
 	"fmt"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents an MRU cache
@@ -15,6 +17,7 @@ type Cache[K comparable, V any] struct {
 	evictList *list.List
 	mu        sync.Mutex
 	onEvict   func(K, V)
+	stats     cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -55,9 +58,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 		// Move to front (most recently used)
 		c.evictList.MoveToFront(element)
 		entry := element.Value.(*entry[K, V])
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -217,6 +222,7 @@ func (c *Cache[K, V]) Resize(capacity int) error {
 func (c *Cache[K, V]) removeMostRecentlyUsed() {
 	element := c.evictList.Front()
 	if element != nil {
+		c.stats.RecordEviction()
 		c.removeElement(element)
 	}
 }
@@ -243,6 +249,14 @@ func (c *Cache[K, V]) Stats() Stats {
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
 	Size     int
@@ -3,6 +3,8 @@
 import (
 	"fmt"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents Belady's Optimal cache replacement algorithm
@@ -14,6 +16,7 @@ type Cache[K comparable, V any] struct {
 	currentTime   int // Current position in access pattern
 	mu            sync.RWMutex
 	onEvict       func(K, V)
+	stats         cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -82,9 +85,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	if entry, exists := c.items[key]; exists {
 		// Update next access time for this entry
 		c.updateNextAccessTime(entry)
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -318,6 +323,7 @@ func (c *Cache[K, V]) evictOptimal() bool {
 
 	// Remove the victim
 	delete(c.items, victimKey)
+	c.stats.RecordEviction()
 
 	// Call eviction callback
 	if c.onEvict != nil {
@@ -404,6 +410,14 @@ type Operation[K comparable, V any] struct {
 	OpPut
 )
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache simulation statistics
 type Stats struct {
 	Hits      int     // Number of cache hits
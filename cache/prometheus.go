@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PrometheusExporter renders the statistics of registered caches
+// in the Prometheus text exposition format (version 0.0.4).
+// It implements http.Handler, so it can be mounted directly on a mux.
+type PrometheusExporter struct {
+	namespace string
+
+	mu     sync.RWMutex
+	caches map[string]StatsProvider
+}
+
+// NewPrometheusExporter creates an exporter whose metric names start with namespace,
+// e.g. "myapp" produces "myapp_cache_hits_total". An empty namespace yields "cache_hits_total".
+func NewPrometheusExporter(namespace string) *PrometheusExporter {
+	return &PrometheusExporter{
+		namespace: namespace,
+		caches:    make(map[string]StatsProvider),
+	}
+}
+
+// Register adds a cache under name, which becomes the value of the "cache" label.
+// Registering an existing name replaces it.
+func (e *PrometheusExporter) Register(name string, provider StatsProvider) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.caches[name] = provider
+}
+
+// Unregister removes the cache registered under name
+func (e *PrometheusExporter) Unregister(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.caches, name)
+}
+
+// promMetric describes one exported metric family
+type promMetric struct {
+	name  string
+	typ   string
+	help  string
+	value func(Stats) string
+}
+
+var promMetrics = []promMetric{
+	{"hits_total", "counter", "Number of cache lookups that found a value.", func(s Stats) string { return formatUint(s.Hits) }},
+	{"misses_total", "counter", "Number of cache lookups that found nothing.", func(s Stats) string { return formatUint(s.Misses) }},
+	{"evictions_total", "counter", "Number of entries evicted to make room.", func(s Stats) string { return formatUint(s.Evictions) }},
+	{"expirations_total", "counter", "Number of entries removed because their TTL elapsed.", func(s Stats) string { return formatUint(s.Expirations) }},
+	{"load_successes_total", "counter", "Number of successful loader calls.", func(s Stats) string { return formatUint(s.LoadSuccesses) }},
+	{"load_failures_total", "counter", "Number of failed loader calls.", func(s Stats) string { return formatUint(s.LoadFailures) }},
+	{"load_duration_seconds_total", "counter", "Total time spent in loader calls.", func(s Stats) string { return formatFloat(s.TotalLoadTime.Seconds()) }},
+	{"hit_ratio", "gauge", "Ratio of hits to lookups.", func(s Stats) string { return formatFloat(s.HitRatio()) }},
+	{"size", "gauge", "Number of entries currently held.", func(s Stats) string { return fmt.Sprint(s.Size) }},
+	{"capacity", "gauge", "Maximum number of entries.", func(s Stats) string { return fmt.Sprint(s.Capacity) }},
+	{"weight", "gauge", "Total weight of entries currently held.", func(s Stats) string { return fmt.Sprint(s.Weight) }},
+	{"max_weight", "gauge", "Maximum total weight, 0 when unbounded by weight.", func(s Stats) string { return fmt.Sprint(s.MaxWeight) }},
+}
+
+// WriteTo writes all metrics to w; caches are ordered by name so the output is stable
+func (e *PrometheusExporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.RLock()
+	names := make([]string, 0, len(e.caches))
+	for name := range e.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stats := make([]Stats, len(names))
+	for i, name := range names {
+		stats[i] = e.caches[name].CacheStats()
+	}
+	e.mu.RUnlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	prefix := "cache_"
+	if e.namespace != "" {
+		prefix = e.namespace + "_cache_"
+	}
+
+	for _, metric := range promMetrics {
+		name := prefix + metric.name
+		fmt.Fprintf(cw, "# HELP %s %s\n", name, metric.help)
+		fmt.Fprintf(cw, "# TYPE %s %s\n", name, metric.typ)
+		for i, cacheName := range names {
+			fmt.Fprintf(cw, "%s{cache=\"%s\"} %s\n", name, escapeLabelValue(cacheName), metric.value(stats[i]))
+		}
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP writes the metrics as a Prometheus scrape response
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = e.WriteTo(w)
+}
+
+// countingWriter counts written bytes and keeps the first error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes a label value per the exposition format
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatUint(v uint64) string {
+	return fmt.Sprint(v)
+}
+
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
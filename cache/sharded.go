@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// Sharded splits keys across several independent caches to reduce lock contention.
+// Each shard is built by the supplied constructor and guarded by its own lock,
+// so operations on keys in different shards never block each other.
+type Sharded[K comparable, V any] struct {
+	shards []Cache[K, V]
+	seed   maphash.Seed
+}
+
+// NewSharded creates a Sharded cache with the given total capacity split across shards.
+// newCache is called once per shard with that shard's capacity; the remainder of
+// capacity/shards goes to the first shards so the total matches capacity exactly.
+func NewSharded[K comparable, V any](capacity, shards int, newCache func(capacity int) (Cache[K, V], error)) (*Sharded[K, V], error) {
+	if shards <= 0 {
+		return nil, fmt.Errorf("shard count must be positive, got %d", shards)
+	}
+	if capacity < shards {
+		return nil, fmt.Errorf("capacity must be at least the shard count %d, got %d", shards, capacity)
+	}
+	if newCache == nil {
+		return nil, fmt.Errorf("cache constructor must not be nil")
+	}
+
+	c := &Sharded[K, V]{
+		shards: make([]Cache[K, V], shards),
+		seed:   maphash.MakeSeed(),
+	}
+
+	for i := range c.shards {
+		shard, err := newCache(shardCapacity(capacity, shards, i))
+		if err != nil {
+			return nil, fmt.Errorf("create shard %d: %w", i, err)
+		}
+		if shard == nil {
+			return nil, fmt.Errorf("cache constructor returned nil cache for shard %d", i)
+		}
+		c.shards[i] = shard
+	}
+
+	return c, nil
+}
+
+// shardCapacity returns the capacity of the i-th shard
+func shardCapacity(capacity, shards, i int) int {
+	n := capacity / shards
+	if i < capacity%shards {
+		n++
+	}
+	return n
+}
+
+// shard returns the shard that owns key
+func (c *Sharded[K, V]) shard(key K) Cache[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Get retrieves a value from the cache
+func (c *Sharded[K, V]) Get(key K) (value V, ok bool) {
+	return c.shard(key).Get(key)
+}
+
+// Put adds or updates a value in the cache
+func (c *Sharded[K, V]) Put(key K, value V) (evicted bool) {
+	return c.shard(key).Put(key, value)
+}
+
+// Remove removes a key from the cache
+func (c *Sharded[K, V]) Remove(key K) (value V, ok bool) {
+	return c.shard(key).Remove(key)
+}
+
+// Contains checks if a key exists in the cache without updating internal state
+func (c *Sharded[K, V]) Contains(key K) bool {
+	return c.shard(key).Contains(key)
+}
+
+// Peek returns a value without updating internal state
+func (c *Sharded[K, V]) Peek(key K) (value V, ok bool) {
+	return c.shard(key).Peek(key)
+}
+
+// Len returns the number of items across all shards
+func (c *Sharded[K, V]) Len() int {
+	n := 0
+	for _, shard := range c.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Cap returns the total capacity across all shards
+func (c *Sharded[K, V]) Cap() int {
+	n := 0
+	for _, shard := range c.shards {
+		n += shard.Cap()
+	}
+	return n
+}
+
+// Clear removes all items from every shard
+func (c *Sharded[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+// Keys returns all keys, shard by shard in each shard's own order.
+// Shards are visited one at a time, so the result is not an atomic snapshot.
+func (c *Sharded[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Values returns all values, shard by shard in each shard's own order.
+// Shards are visited one at a time, so the result is not an atomic snapshot.
+func (c *Sharded[K, V]) Values() []V {
+	values := make([]V, 0, c.Len())
+	for _, shard := range c.shards {
+		values = append(values, shard.Values()...)
+	}
+	return values
+}
+
+// Items returns all key-value pairs across all shards.
+// Shards are visited one at a time, so the result is not an atomic snapshot.
+func (c *Sharded[K, V]) Items() map[K]V {
+	items := make(map[K]V, c.Len())
+	for _, shard := range c.shards {
+		for k, v := range shard.Items() {
+			items[k] = v
+		}
+	}
+	return items
+}
+
+// Resize redistributes capacity across shards.
+// Shards shrunk below their current size evict items according to their own policy.
+func (c *Sharded[K, V]) Resize(capacity int) error {
+	if capacity < len(c.shards) {
+		return fmt.Errorf("capacity must be at least the shard count %d, got %d", len(c.shards), capacity)
+	}
+
+	for i, shard := range c.shards {
+		if err := shard.Resize(shardCapacity(capacity, len(c.shards), i)); err != nil {
+			return fmt.Errorf("resize shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// CacheStats returns the sum of the shards' statistics.
+// Shards that do not implement StatsProvider contribute only Size, Capacity and weight.
+func (c *Sharded[K, V]) CacheStats() Stats {
+	var stats Stats
+	for _, shard := range c.shards {
+		if provider, ok := shard.(StatsProvider); ok {
+			stats = stats.Add(provider.CacheStats())
+			continue
+		}
+		shardStats := Stats{Size: shard.Len(), Capacity: shard.Cap()}
+		weightOf(shard, &shardStats)
+		stats = stats.Add(shardStats)
+	}
+	return stats
+}
+
+// ShardCount returns the number of shards
+func (c *Sharded[K, V]) ShardCount() int {
+	return len(c.shards)
+}
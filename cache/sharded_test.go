@@ -0,0 +1,168 @@
+package cache_test
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/cache/lru"
+	"github.com/lazygophers/utils/cache/wtinylfu"
+)
+
+func newLRUShard(capacity int) (cache.Cache[string, int], error) {
+	return lru.New[string, int](capacity)
+}
+
+func TestNewShardedError(t *testing.T) {
+	if _, err := cache.NewSharded[string, int](10, 0, newLRUShard); err == nil {
+		t.Error("Expected error for zero shards")
+	}
+	if _, err := cache.NewSharded[string, int](3, 4, newLRUShard); err == nil {
+		t.Error("Expected error for capacity below shard count")
+	}
+	if _, err := cache.NewSharded[string, int](10, 2, nil); err == nil {
+		t.Error("Expected error for nil constructor")
+	}
+
+	_, err := cache.NewSharded[string, int](10, 2, func(capacity int) (cache.Cache[string, int], error) {
+		return nil, fmt.Errorf("boom")
+	})
+	if err == nil {
+		t.Error("Expected error from constructor")
+	}
+}
+
+func TestShardedCapacitySplit(t *testing.T) {
+	var caps []int
+	c, err := cache.NewSharded[string, int](10, 4, func(capacity int) (cache.Cache[string, int], error) {
+		caps = append(caps, capacity)
+		return lru.New[string, int](capacity)
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	if fmt.Sprint(caps) != "[3 3 2 2]" {
+		t.Errorf("Unexpected shard capacities %v", caps)
+	}
+	if c.Cap() != 10 || c.ShardCount() != 4 {
+		t.Errorf("Expected capacity 10 and 4 shards, got %d, %d", c.Cap(), c.ShardCount())
+	}
+}
+
+func TestShardedBasicOperations(t *testing.T) {
+	c, err := cache.NewSharded[string, int](800, 8, newLRUShard)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		c.Put(fmt.Sprintf("k%d", i), i)
+	}
+
+	if c.Len() != 50 {
+		t.Errorf("Expected length 50, got %d", c.Len())
+	}
+	if v, ok := c.Get("k7"); !ok || v != 7 {
+		t.Errorf("Expected k7=7, got %v, %v", v, ok)
+	}
+	if v, ok := c.Peek("k8"); !ok || v != 8 {
+		t.Errorf("Expected k8=8, got %v, %v", v, ok)
+	}
+	if !c.Contains("k9") {
+		t.Error("Expected k9 to exist")
+	}
+	if v, ok := c.Remove("k9"); !ok || v != 9 {
+		t.Errorf("Expected to remove k9=9, got %v, %v", v, ok)
+	}
+	if c.Contains("k9") {
+		t.Error("Expected k9 to be removed")
+	}
+
+	keys := c.Keys()
+	values := c.Values()
+	items := c.Items()
+	if len(keys) != 49 || len(values) != 49 || len(items) != 49 {
+		t.Errorf("Expected 49 entries, got %d keys, %d values, %d items", len(keys), len(values), len(items))
+	}
+	sort.Strings(keys)
+	if keys[0] != "k0" {
+		t.Errorf("Unexpected first key %s", keys[0])
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("Expected empty cache, got length %d", c.Len())
+	}
+}
+
+func TestShardedResize(t *testing.T) {
+	c, err := cache.NewSharded[string, int](16, 4, newLRUShard)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Put(fmt.Sprintf("k%d", i), i)
+	}
+	if c.Len() > 16 {
+		t.Errorf("Expected at most 16 items, got %d", c.Len())
+	}
+
+	if err := c.Resize(2); err == nil {
+		t.Error("Expected error for capacity below shard count")
+	}
+	if err := c.Resize(4); err != nil {
+		t.Fatalf("Failed to resize: %v", err)
+	}
+	if c.Cap() != 4 || c.Len() > 4 {
+		t.Errorf("Expected capacity 4 and at most 4 items, got %d, %d", c.Cap(), c.Len())
+	}
+}
+
+func TestShardedConcurrent(t *testing.T) {
+	c, err := cache.NewSharded[string, int](1024, 16, func(capacity int) (cache.Cache[string, int], error) {
+		return wtinylfu.New[string, int](capacity)
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprintf("k%d", (g*1000+i)%2048)
+				c.Put(key, i)
+				c.Get(key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if c.Len() > c.Cap() {
+		t.Errorf("Length %d exceeds capacity %d", c.Len(), c.Cap())
+	}
+}
+
+func BenchmarkShardedParallel(b *testing.B) {
+	c, _ := cache.NewSharded[string, int](10000, 32, newLRUShard)
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%d", i)
+		c.Put(keys[i], i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
@@ -4,6 +4,9 @@
 	"container/list"
 	"fmt"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/human"
 )
 
 // Cache represents a Segmented LRU cache
@@ -14,8 +17,14 @@ type Cache[K comparable, V any] struct {
 	items        map[K]*entry[K, V]
 	mu           sync.RWMutex
 	onEvict      func(K, V)
+	stats        cache.StatsCounter
 	pSize        int // probationary segment size
 	protSize     int // protected segment size
+
+	// 按重量限制容量：weigher 为 nil 时只按条目数限制
+	weigher   cache.Weigher[K, V]
+	maxWeight int64
+	weight    int64
 }
 
 // entry represents a cache entry
@@ -24,6 +33,7 @@ type entry[K comparable, V any] struct {
 	value   V
 	element *list.Element
 	segment *list.List // which segment this entry belongs to
+	weight  int64
 }
 
 // New creates a new SLRU cache with the given capacity
@@ -84,6 +94,26 @@ func NewWithEvict[K comparable, V any](capacity int, onEvict func(K, V)) (*Cache
 	return cache, nil
 }
 
+// NewWithWeigher creates a new SLRU cache bounded by both the entry count and the total weight.
+// weigher returns the weight of each entry (e.g. its size in bytes); entries are evicted from
+// the probationary segment first, then from the protected one, until the total weight is at most maxWeight.
+func NewWithWeigher[K comparable, V any](capacity int, maxWeight int64, weigher cache.Weigher[K, V]) (*Cache[K, V], error) {
+	if maxWeight <= 0 {
+		return nil, fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+	if weigher == nil {
+		return nil, fmt.Errorf("weigher must not be nil")
+	}
+
+	cache, err := New[K, V](capacity)
+	if err != nil {
+		return nil, err
+	}
+	cache.weigher = weigher
+	cache.maxWeight = maxWeight
+	return cache, nil
+}
+
 // Get retrieves a value from the cache
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.mu.Lock()
@@ -97,9 +127,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 			// Move to front of protected segment
 			c.protected.MoveToFront(entry.element)
 		}
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -109,16 +141,28 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	weight := c.weigh(key, value)
+	if c.maxWeight > 0 && weight > c.maxWeight {
+		// 单个条目超过重量上限，不缓存；旧值视为被淘汰
+		if entry, exists := c.items[key]; exists {
+			c.removeEntry(entry)
+			return true
+		}
+		return false
+	}
+
 	// Check if key already exists
 	if entry, exists := c.items[key]; exists {
 		// Update existing entry
 		entry.value = value
+		c.weight += weight - entry.weight
+		entry.weight = weight
 		if entry.segment == c.probationary {
 			c.promoteToProtected(entry)
 		} else {
 			c.protected.MoveToFront(entry.element)
 		}
-		return false
+		return c.evictForWeight(entry)
 	}
 
 	// Add new entry to probationary segment
@@ -126,6 +170,7 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 		key:     key,
 		value:   value,
 		segment: c.probationary,
+		weight:  weight,
 	}
 
 	// Check if probationary segment is full
@@ -136,8 +181,9 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 	element := c.probationary.PushFront(entry)
 	entry.element = element
 	c.items[key] = entry
+	c.weight += weight
 
-	return evicted
+	return c.evictForWeight(entry) || evicted
 }
 
 // Remove removes a key from the cache
@@ -203,6 +249,7 @@ func (c *Cache[K, V]) Clear() {
 	}
 	c.probationary.Init()
 	c.protected.Init()
+	c.weight = 0
 }
 
 // Keys returns all keys in the cache (protected first, then probationary)
@@ -352,8 +399,68 @@ func (c *Cache[K, V]) evictFromProtected() bool {
 	return false
 }
 
+// evictForWeight evicts least recently used entries, probationary first,
+// until the total weight fits, never evicting keep
+func (c *Cache[K, V]) evictForWeight(keep *entry[K, V]) (evicted bool) {
+	for c.maxWeight > 0 && c.weight > c.maxWeight {
+		element := c.probationary.Back()
+		if element == nil || element.Value.(*entry[K, V]) == keep {
+			element = c.protected.Back()
+		}
+		if element == nil || element.Value.(*entry[K, V]) == keep {
+			break
+		}
+		c.removeEntry(element.Value.(*entry[K, V]))
+		evicted = true
+	}
+	return evicted
+}
+
+// weigh returns the weight of an entry, 0 when no weigher is configured
+func (c *Cache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 0
+	}
+	return max(0, c.weigher(key, value))
+}
+
+// Weight returns the total weight of all entries, 0 when no weigher is configured
+func (c *Cache[K, V]) Weight() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.weight
+}
+
+// MaxWeight returns the weight limit, 0 when no weigher is configured
+func (c *Cache[K, V]) MaxWeight() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.maxWeight
+}
+
+// ResizeWeight changes the weight limit, evicting entries until the total fits
+func (c *Cache[K, V]) ResizeWeight(maxWeight int64) error {
+	if maxWeight <= 0 {
+		return fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.weigher == nil {
+		return fmt.Errorf("cache has no weigher")
+	}
+
+	c.maxWeight = maxWeight
+	c.evictForWeight(nil)
+	return nil
+}
+
 // removeEntry removes an entry completely
 func (c *Cache[K, V]) removeEntry(entry *entry[K, V]) {
+	c.stats.RecordEviction()
 	c.removeEntryWithEvict(entry, true)
 }
 
@@ -361,6 +468,7 @@ func (c *Cache[K, V]) removeEntry(entry *entry[K, V]) {
 func (c *Cache[K, V]) removeEntryWithEvict(entry *entry[K, V], callEvict bool) {
 	entry.segment.Remove(entry.element)
 	delete(c.items, entry.key)
+	c.weight -= entry.weight
 
 	if callEvict && c.onEvict != nil {
 		c.onEvict(entry.key, entry.value)
@@ -379,15 +487,37 @@ func (c *Cache[K, V]) Stats() Stats {
 		ProtectedSize:        c.protected.Len(),
 		ProbationaryCapacity: c.pSize,
 		ProtectedCapacity:    c.protSize,
+		Weight:               c.weight,
+		MaxWeight:            c.maxWeight,
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	stats.Weight = c.Weight()
+	stats.MaxWeight = c.MaxWeight()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
-	Size                 int // actual cache size
-	Capacity             int // maximum cache capacity
-	ProbationarySize     int // current probationary segment size
-	ProtectedSize        int // current protected segment size
-	ProbationaryCapacity int // probationary segment capacity
-	ProtectedCapacity    int // protected segment capacity
+	Size                 int   // actual cache size
+	Capacity             int   // maximum cache capacity
+	ProbationarySize     int   // current probationary segment size
+	ProtectedSize        int   // current protected segment size
+	ProbationaryCapacity int   // probationary segment capacity
+	ProtectedCapacity    int   // protected segment capacity
+	Weight               int64 // total weight of all entries, 0 without a weigher
+	MaxWeight            int64 // weight limit, 0 without a weigher
+}
+
+// String returns a short summary; weights are printed as byte sizes
+func (s Stats) String() string {
+	if s.MaxWeight <= 0 {
+		return fmt.Sprintf("size=%d/%d", s.Size, s.Capacity)
+	}
+	return fmt.Sprintf("size=%d/%d weight=%s/%s", s.Size, s.Capacity, human.ByteSize(s.Weight), human.ByteSize(s.MaxWeight))
 }
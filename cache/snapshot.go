@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lazygophers/utils/json"
+)
+
+// snapshotVersion is the current snapshot format version
+const snapshotVersion = 1
+
+// Codec encodes and decodes snapshots.
+// Any serializer with Marshal/Unmarshal functions can be adapted to it.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// jsonCodec adapts the project's json package to Codec
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// JSONCodec is the default Codec, backed by github.com/lazygophers/utils/json
+var JSONCodec Codec = jsonCodec{}
+
+// SnapshotEntry is a single cached entry in a snapshot.
+// Frequency is the policy's access count estimate, or 0 when the policy does not track one.
+type SnapshotEntry[K comparable, V any] struct {
+	Key       K      `json:"key"`
+	Value     V      `json:"value"`
+	Frequency uint64 `json:"frequency,omitempty"`
+}
+
+// Snapshotter is implemented by policies that can export and restore their internal
+// ordering, such as LRU recency, LFU counts or TinyLFU sketch estimates.
+// Entries are ordered from the least to the most valuable.
+type Snapshotter[K comparable, V any] interface {
+	// Snapshot returns all entries, least valuable first
+	Snapshot() []SnapshotEntry[K, V]
+	// Restore replaces the cache contents with entries, least valuable first.
+	// When entries exceed the capacity the least valuable ones are dropped without eviction callbacks.
+	Restore(entries []SnapshotEntry[K, V])
+}
+
+// snapshot is the serialized form written by Save
+type snapshot[K comparable, V any] struct {
+	Version int                   `json:"version"`
+	Entries []SnapshotEntry[K, V] `json:"entries"`
+}
+
+// Save writes a versioned snapshot of c to w.
+// Policies implementing Snapshotter keep their internal ordering; for other caches
+// the reverse of Keys() is treated as least to most valuable. A nil codec means JSONCodec.
+func Save[K comparable, V any](w io.Writer, c Cache[K, V], codec Codec) error {
+	if codec == nil {
+		codec = JSONCodec
+	}
+
+	data, err := codec.Marshal(snapshot[K, V]{
+		Version: snapshotVersion,
+		Entries: snapshotEntries(c),
+	})
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotEntries collects the entries of c, least valuable first
+func snapshotEntries[K comparable, V any](c Cache[K, V]) []SnapshotEntry[K, V] {
+	if s, ok := c.(Snapshotter[K, V]); ok {
+		return s.Snapshot()
+	}
+
+	keys := c.Keys()
+	entries := make([]SnapshotEntry[K, V], 0, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		if value, ok := c.Peek(keys[i]); ok {
+			entries = append(entries, SnapshotEntry[K, V]{Key: keys[i], Value: value})
+		}
+	}
+	return entries
+}
+
+// Load reads a snapshot written by Save from r and replaces the contents of c with it.
+// Policies implementing Snapshotter restore their internal ordering; other caches are
+// cleared and refilled with Put from the least to the most valuable entry. A nil codec means JSONCodec.
+func Load[K comparable, V any](r io.Reader, c Cache[K, V], codec Codec) error {
+	if codec == nil {
+		codec = JSONCodec
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var snap snapshot[K, V]
+	if err = codec.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+
+	if s, ok := c.(Snapshotter[K, V]); ok {
+		s.Restore(snap.Entries)
+		return nil
+	}
+
+	c.Clear()
+	for _, e := range snap.Entries {
+		c.Put(e.Key, e.Value)
+	}
+	return nil
+}
+
+// SaveFile writes a snapshot of c to filename.
+// The snapshot is written to a temporary file first and renamed into place,
+// so a crash during shutdown never leaves a truncated snapshot behind.
+func SaveFile[K comparable, V any](filename string, c Cache[K, V], codec Codec) error {
+	tmp := filename + ".tmp"
+
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	err = Save(file, c, codec)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, filename)
+}
+
+// LoadFile reads a snapshot from filename into c.
+// A missing file returns an error matching os.ErrNotExist, which callers usually ignore on first start.
+func LoadFile[K comparable, V any](filename string, c Cache[K, V], codec Codec) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return Load(file, c, codec)
+}
@@ -0,0 +1,198 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/cache/lfu"
+	"github.com/lazygophers/utils/cache/lru"
+	"github.com/lazygophers/utils/cache/slru"
+	"github.com/lazygophers/utils/cache/wtinylfu"
+)
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	src, _ := lru.New[string, user](10)
+	src.Put("alice", user{Name: "Alice", Age: 30})
+	src.Put("bob", user{Name: "Bob", Age: 25})
+	src.Get("alice")
+
+	var buf bytes.Buffer
+	if err := cache.Save[string, user](&buf, src, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"version":1`) {
+		t.Errorf("Expected versioned snapshot, got %s", buf.String())
+	}
+
+	dst, _ := lru.New[string, user](10)
+	dst.Put("stale", user{})
+	if err := cache.Load[string, user](&buf, dst, nil); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if dst.Contains("stale") {
+		t.Error("Expected Load to replace existing contents")
+	}
+	if fmt.Sprint(dst.Keys()) != fmt.Sprint(src.Keys()) {
+		t.Errorf("Expected order %v, got %v", src.Keys(), dst.Keys())
+	}
+	if v, _ := dst.Peek("alice"); v != (user{Name: "Alice", Age: 30}) {
+		t.Errorf("Unexpected value %+v", v)
+	}
+}
+
+func TestSaveLoadKeepsFrequencies(t *testing.T) {
+	src, _ := lfu.New[string, int](10)
+	src.Put("hot", 1)
+	src.Put("cold", 2)
+	for i := 0; i < 4; i++ {
+		src.Get("hot")
+	}
+
+	var buf bytes.Buffer
+	cache.Save[string, int](&buf, src, cache.JSONCodec)
+
+	dst, _ := lfu.New[string, int](10)
+	if err := cache.Load[string, int](&buf, dst, cache.JSONCodec); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if dst.GetFreq("hot") != 5 || dst.GetFreq("cold") != 1 {
+		t.Errorf("Expected frequencies 5 and 1, got %d and %d", dst.GetFreq("hot"), dst.GetFreq("cold"))
+	}
+}
+
+func TestSaveLoadWarmStartWTinyLFU(t *testing.T) {
+	src, _ := wtinylfu.New[int, int](100)
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 200; i++ {
+			src.Put(i, i)
+			src.Get(i)
+		}
+	}
+
+	var buf bytes.Buffer
+	cache.Save[int, int](&buf, src, nil)
+
+	dst, _ := wtinylfu.New[int, int](100)
+	if err := cache.Load[int, int](&buf, dst, nil); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if dst.Len() != src.Len() {
+		t.Errorf("Expected %d warm entries, got %d", src.Len(), dst.Len())
+	}
+	for key, value := range src.Items() {
+		if v, ok := dst.Peek(key); !ok || v != value {
+			t.Errorf("Expected %d=%d after warm start", key, value)
+		}
+	}
+}
+
+func TestSaveLoadFallback(t *testing.T) {
+	// slru 未实现 Snapshotter，按 Keys() 逆序回放 Put
+	src, _ := slru.New[string, int](10)
+	src.Put("a", 1)
+	src.Put("b", 2)
+	src.Get("a")
+
+	var buf bytes.Buffer
+	if err := cache.Save[string, int](&buf, src, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	dst, _ := lru.New[string, int](10)
+	if err := cache.Load[string, int](&buf, dst, nil); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if keys := dst.Keys(); len(keys) != 2 || keys[0] != src.Keys()[0] {
+		t.Errorf("Expected %v, got %v", src.Keys(), keys)
+	}
+}
+
+func TestSaveLoadWrapper(t *testing.T) {
+	src, _ := cache.NewSharded[string, int](40, 4, newLRUShard)
+	for i := 0; i < 20; i++ {
+		src.Put(fmt.Sprintf("k%d", i), i)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.Save[string, int](&buf, src, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	dst := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		return -1, nil
+	}, cache.LoadingConfig[string, int]{})
+	if err := cache.Load[string, int](&buf, dst, nil); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if dst.Len() != 20 {
+		t.Errorf("Expected 20 entries, got %d", dst.Len())
+	}
+	if v, _ := dst.GetOrLoad(context.Background(), "k7"); v != 7 {
+		t.Errorf("Expected restored value 7, got %d", v)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	c, _ := lru.New[string, int](10)
+
+	if err := cache.Load[string, int](strings.NewReader(`{"version":2,"entries":[]}`), c, nil); err == nil ||
+		!strings.Contains(err.Error(), "unsupported snapshot version 2") {
+		t.Errorf("Expected version error, got %v", err)
+	}
+	if err := cache.Load[string, int](strings.NewReader(`not json`), c, nil); err == nil {
+		t.Error("Expected decode error")
+	}
+}
+
+type failingCodec struct{ err error }
+
+func (f failingCodec) Marshal(v any) ([]byte, error)      { return nil, f.err }
+func (f failingCodec) Unmarshal(data []byte, v any) error { return f.err }
+
+func TestSaveCodecError(t *testing.T) {
+	boom := errors.New("boom")
+	c, _ := lru.New[string, int](10)
+
+	var buf bytes.Buffer
+	if err := cache.Save[string, int](&buf, c, failingCodec{boom}); !errors.Is(err, boom) {
+		t.Errorf("Expected wrapped codec error, got %v", err)
+	}
+}
+
+func TestSaveFileLoadFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	dst, _ := lru.New[string, int](10)
+	if err := cache.LoadFile[string, int](filename, dst, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not exist error, got %v", err)
+	}
+
+	src, _ := lru.New[string, int](10)
+	src.Put("a", 1)
+	if err := cache.SaveFile[string, int](filename, src, nil); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	if _, err := os.Stat(filename + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected temporary file to be renamed")
+	}
+
+	if err := cache.LoadFile[string, int](filename, dst, nil); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if v, ok := dst.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected a=1, got %v, %v", v, ok)
+	}
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lazygophers/utils/human"
+)
+
+// Stats is the policy-independent view of cache statistics.
+// Counters are cumulative since the cache was created or last reset.
+type Stats struct {
+	Hits          uint64        // Get calls that found a value
+	Misses        uint64        // Get calls that found nothing
+	Evictions     uint64        // entries removed to make room (not Remove/Clear)
+	Expirations   uint64        // entries removed because their TTL elapsed
+	LoadSuccesses uint64        // loader calls that returned a value
+	LoadFailures  uint64        // loader calls that returned an error
+	TotalLoadTime time.Duration // time spent in loader calls
+	Size          int           // entries currently held
+	Capacity      int           // maximum number of entries
+	Weight        int64         // total weight of held entries, 0 without a Weigher
+	MaxWeight     int64         // maximum total weight, 0 without a Weigher
+}
+
+// Requests returns the number of Get calls, hits plus misses
+func (s Stats) Requests() uint64 {
+	return s.Hits + s.Misses
+}
+
+// HitRatio returns hits / (hits + misses), or 0 if there were no requests
+func (s Stats) HitRatio() float64 {
+	requests := s.Requests()
+	if requests == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(requests)
+}
+
+// Loads returns the number of loader calls
+func (s Stats) Loads() uint64 {
+	return s.LoadSuccesses + s.LoadFailures
+}
+
+// AverageLoadPenalty returns the average time spent per loader call
+func (s Stats) AverageLoadPenalty() time.Duration {
+	loads := s.Loads()
+	if loads == 0 {
+		return 0
+	}
+	return s.TotalLoadTime / time.Duration(loads)
+}
+
+// Add returns the field-wise sum of s and o
+func (s Stats) Add(o Stats) Stats {
+	return Stats{
+		Hits:          s.Hits + o.Hits,
+		Misses:        s.Misses + o.Misses,
+		Evictions:     s.Evictions + o.Evictions,
+		Expirations:   s.Expirations + o.Expirations,
+		LoadSuccesses: s.LoadSuccesses + o.LoadSuccesses,
+		LoadFailures:  s.LoadFailures + o.LoadFailures,
+		TotalLoadTime: s.TotalLoadTime + o.TotalLoadTime,
+		Size:          s.Size + o.Size,
+		Capacity:      s.Capacity + o.Capacity,
+		Weight:        s.Weight + o.Weight,
+		MaxWeight:     s.MaxWeight + o.MaxWeight,
+	}
+}
+
+// String returns a one-line summary for logs and debugging.
+// Weights are printed as byte sizes, since weighers usually measure bytes.
+func (s Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "hits=%d misses=%d hit_ratio=%.4f evictions=%d", s.Hits, s.Misses, s.HitRatio(), s.Evictions)
+	if s.Expirations > 0 {
+		fmt.Fprintf(&b, " expirations=%d", s.Expirations)
+	}
+	if s.Loads() > 0 {
+		fmt.Fprintf(&b, " loads=%d load_failures=%d avg_load=%v", s.Loads(), s.LoadFailures, s.AverageLoadPenalty())
+	}
+	fmt.Fprintf(&b, " size=%d/%d", s.Size, s.Capacity)
+	if s.MaxWeight > 0 {
+		fmt.Fprintf(&b, " weight=%s/%s", human.ByteSize(s.Weight), human.ByteSize(s.MaxWeight))
+	}
+	return b.String()
+}
+
+// StatsProvider is implemented by caches that report common statistics.
+// All 11 algorithms and the wrappers in this package implement it.
+type StatsProvider interface {
+	CacheStats() Stats
+}
+
+// StatsCounter accumulates cache counters; it is safe for concurrent use
+// and its zero value is ready to use.
+type StatsCounter struct {
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	evictions     atomic.Uint64
+	expirations   atomic.Uint64
+	loadSuccesses atomic.Uint64
+	loadFailures  atomic.Uint64
+	loadTime      atomic.Int64
+}
+
+// RecordHit records a Get that found a value
+func (s *StatsCounter) RecordHit() {
+	s.hits.Add(1)
+}
+
+// RecordMiss records a Get that found nothing
+func (s *StatsCounter) RecordMiss() {
+	s.misses.Add(1)
+}
+
+// RecordEviction records an entry removed to make room
+func (s *StatsCounter) RecordEviction() {
+	s.evictions.Add(1)
+}
+
+// RecordExpiration records an entry removed because its TTL elapsed
+func (s *StatsCounter) RecordExpiration() {
+	s.expirations.Add(1)
+}
+
+// RecordLoadSuccess records a successful loader call that took d
+func (s *StatsCounter) RecordLoadSuccess(d time.Duration) {
+	s.loadSuccesses.Add(1)
+	s.loadTime.Add(int64(d))
+}
+
+// RecordLoadFailure records a failed loader call that took d
+func (s *StatsCounter) RecordLoadFailure(d time.Duration) {
+	s.loadFailures.Add(1)
+	s.loadTime.Add(int64(d))
+}
+
+// Snapshot returns the current counters; Size and Capacity are left zero
+func (s *StatsCounter) Snapshot() Stats {
+	return Stats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Evictions:     s.evictions.Load(),
+		Expirations:   s.expirations.Load(),
+		LoadSuccesses: s.loadSuccesses.Load(),
+		LoadFailures:  s.loadFailures.Load(),
+		TotalLoadTime: time.Duration(s.loadTime.Load()),
+	}
+}
+
+// Reset sets all counters back to zero
+func (s *StatsCounter) Reset() {
+	s.hits.Store(0)
+	s.misses.Store(0)
+	s.evictions.Store(0)
+	s.expirations.Store(0)
+	s.loadSuccesses.Store(0)
+	s.loadFailures.Store(0)
+	s.loadTime.Store(0)
+}
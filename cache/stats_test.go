@@ -0,0 +1,272 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/cache/alfu"
+	"github.com/lazygophers/utils/cache/arc"
+	"github.com/lazygophers/utils/cache/fbr"
+	"github.com/lazygophers/utils/cache/lfu"
+	"github.com/lazygophers/utils/cache/lru"
+	"github.com/lazygophers/utils/cache/lruk"
+	"github.com/lazygophers/utils/cache/mru"
+	"github.com/lazygophers/utils/cache/optimal"
+	"github.com/lazygophers/utils/cache/slru"
+	"github.com/lazygophers/utils/cache/tinylfu"
+	"github.com/lazygophers/utils/cache/wtinylfu"
+)
+
+type statsCache interface {
+	cache.Cache[string, int]
+	cache.StatsProvider
+}
+
+func allPolicies(t *testing.T, capacity int) map[string]statsCache {
+	t.Helper()
+
+	must := func(c statsCache, err error) statsCache {
+		if err != nil {
+			t.Fatalf("Failed to create cache: %v", err)
+		}
+		return c
+	}
+
+	return map[string]statsCache{
+		"alfu":     must(alfu.New[string, int](capacity)),
+		"arc":      must(arc.New[string, int](capacity)),
+		"fbr":      must(fbr.New[string, int](capacity)),
+		"lfu":      must(lfu.New[string, int](capacity)),
+		"lru":      must(lru.New[string, int](capacity)),
+		"lruk":     must(lruk.New[string, int](capacity, 1)),
+		"mru":      must(mru.New[string, int](capacity)),
+		"optimal":  must(optimal.New[string, int](capacity)),
+		"slru":     must(slru.New[string, int](capacity)),
+		"tinylfu":  must(tinylfu.New[string, int](capacity)),
+		"wtinylfu": must(wtinylfu.New[string, int](capacity)),
+	}
+}
+
+func TestPolicyCacheStats(t *testing.T) {
+	for name, c := range allPolicies(t, 10) {
+		t.Run(name, func(t *testing.T) {
+			c.Put("a", 1)
+			c.Get("a")
+			c.Get("missing")
+			c.Peek("a")
+			c.Contains("a")
+
+			stats := c.CacheStats()
+			if stats.Hits != 1 || stats.Misses != 1 {
+				t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+			}
+			if stats.HitRatio() != 0.5 {
+				t.Errorf("Expected hit ratio 0.5, got %v", stats.HitRatio())
+			}
+			if stats.Size != c.Len() || stats.Capacity != 10 {
+				t.Errorf("Unexpected size/capacity %+v", stats)
+			}
+
+			for i := 0; i < 100; i++ {
+				c.Put(fmt.Sprintf("k%d", i), i)
+			}
+			if c.CacheStats().Evictions == 0 {
+				t.Error("Expected evictions to be counted")
+			}
+
+			before := c.CacheStats().Evictions
+			c.Remove(c.Keys()[0])
+			c.Clear()
+			if after := c.CacheStats().Evictions; after != before {
+				t.Errorf("Expected Remove/Clear not to count as evictions, got %d -> %d", before, after)
+			}
+		})
+	}
+}
+
+func TestStatsHelpers(t *testing.T) {
+	var s cache.Stats
+	if s.HitRatio() != 0 || s.AverageLoadPenalty() != 0 {
+		t.Error("Expected zero ratios for empty stats")
+	}
+
+	s = cache.Stats{Hits: 3, Misses: 1, LoadSuccesses: 1, LoadFailures: 1, TotalLoadTime: 4 * time.Second}
+	if s.Requests() != 4 || s.HitRatio() != 0.75 {
+		t.Errorf("Unexpected requests/ratio %d %v", s.Requests(), s.HitRatio())
+	}
+	if s.Loads() != 2 || s.AverageLoadPenalty() != 2*time.Second {
+		t.Errorf("Unexpected loads/penalty %d %v", s.Loads(), s.AverageLoadPenalty())
+	}
+
+	sum := s.Add(cache.Stats{Hits: 1, Size: 2, Capacity: 3})
+	if sum.Hits != 4 || sum.Size != 2 || sum.Capacity != 3 || sum.LoadFailures != 1 {
+		t.Errorf("Unexpected sum %+v", sum)
+	}
+}
+
+func TestStatsCounter(t *testing.T) {
+	var c cache.StatsCounter
+	c.RecordHit()
+	c.RecordMiss()
+	c.RecordEviction()
+	c.RecordExpiration()
+	c.RecordLoadSuccess(time.Second)
+	c.RecordLoadFailure(time.Second)
+
+	s := c.Snapshot()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 1 || s.Expirations != 1 ||
+		s.LoadSuccesses != 1 || s.LoadFailures != 1 || s.TotalLoadTime != 2*time.Second {
+		t.Errorf("Unexpected snapshot %+v", s)
+	}
+
+	c.Reset()
+	if c.Snapshot() != (cache.Stats{}) {
+		t.Errorf("Expected zero stats after reset, got %+v", c.Snapshot())
+	}
+}
+
+func TestShardedCacheStats(t *testing.T) {
+	c, err := cache.NewSharded[string, int](400, 4, newLRUShard)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		key := fmt.Sprintf("k%d", i)
+		c.Put(key, i)
+		c.Get(key)
+		c.Get("missing" + key)
+	}
+
+	stats := c.CacheStats()
+	if stats.Hits != 4 || stats.Misses != 4 || stats.Capacity != 400 || stats.Size != 4 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestLoadingCacheStats(t *testing.T) {
+	c := newTestLoading(t, func(ctx context.Context, key string) (int, error) {
+		if key == "bad" {
+			return 0, errors.New("bad key")
+		}
+		return 1, nil
+	}, cache.LoadingConfig[string, int]{})
+
+	c.GetOrLoad(context.Background(), "a")
+	c.GetOrLoad(context.Background(), "a")
+	c.GetOrLoad(context.Background(), "bad")
+
+	stats := c.CacheStats()
+	if stats.LoadSuccesses != 1 || stats.LoadFailures != 1 {
+		t.Errorf("Expected 1 load success and 1 failure, got %+v", stats)
+	}
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Expected hits/misses from underlying cache, got %+v", stats)
+	}
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	a, _ := lru.New[string, int](10)
+	b, _ := lfu.New[string, int](20)
+	a.Put("x", 1)
+	a.Get("x")
+	b.Get("y")
+
+	e := cache.NewPrometheusExporter("app")
+	e.Register("users", a)
+	e.Register("b\"ad\n", b)
+	e.Register("gone", a)
+	e.Unregister("gone")
+
+	var buf bytes.Buffer
+	n, err := e.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("WriteTo returned %d, %v for %d bytes", n, err, buf.Len())
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE app_cache_hits_total counter\n",
+		"app_cache_hits_total{cache=\"users\"} 1\n",
+		"app_cache_misses_total{cache=\"b\\\"ad\\n\"} 1\n",
+		"app_cache_hit_ratio{cache=\"users\"} 1\n",
+		"app_cache_capacity{cache=\"users\"} 10\n",
+		"# TYPE app_cache_size gauge\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "gone") {
+		t.Error("Expected unregistered cache to be absent")
+	}
+
+	// 名称排序保证输出稳定
+	if strings.Index(out, `cache="b\"ad\n"`) > strings.Index(out, `cache="users"`) {
+		t.Error("Expected caches sorted by name")
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if rec.Body.String() != out {
+		t.Error("Expected handler output to match WriteTo")
+	}
+}
+
+func TestPrometheusExporterDefaultNamespace(t *testing.T) {
+	e := cache.NewPrometheusExporter("")
+	var buf bytes.Buffer
+	e.WriteTo(&buf)
+	if !strings.HasPrefix(buf.String(), "# HELP cache_hits_total ") {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestStatsString(t *testing.T) {
+	s := cache.Stats{Hits: 3, Misses: 1, Size: 2, Capacity: 10}
+	if got := s.String(); got != "hits=3 misses=1 hit_ratio=0.7500 evictions=0 size=2/10" {
+		t.Errorf("Unexpected string %q", got)
+	}
+
+	s.Weight, s.MaxWeight = 1536, 4096
+	if got := s.String(); !strings.HasSuffix(got, " weight=1.5 KB/4 KB") {
+		t.Errorf("Unexpected string %q", got)
+	}
+}
+
+func TestWeightedStats(t *testing.T) {
+	weigher := func(key string, value int) int64 { return int64(value) }
+	c, err := cache.NewSharded[string, int](40, 4, func(capacity int) (cache.Cache[string, int], error) {
+		return lru.NewWithWeigher[string, int](capacity, 100, weigher)
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	c.Put("a", 30)
+	c.Put("b", 12)
+
+	stats := c.CacheStats()
+	if stats.Weight != 42 || stats.MaxWeight != 400 {
+		t.Errorf("Expected summed weights, got %+v", stats)
+	}
+
+	e := cache.NewPrometheusExporter("")
+	e.Register("weighted", c)
+	var buf bytes.Buffer
+	e.WriteTo(&buf)
+	if !strings.Contains(buf.String(), "cache_weight{cache=\"weighted\"} 42\n") ||
+		!strings.Contains(buf.String(), "cache_max_weight{cache=\"weighted\"} 400\n") {
+		t.Errorf("Expected weight gauges, got:\n%s", buf.String())
+	}
+}
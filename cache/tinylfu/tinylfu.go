@@ -6,6 +6,8 @@
 	"hash/fnv"
 	"math"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 // Cache represents a TinyLFU cache with Count-Min Sketch for frequency estimation
@@ -22,6 +24,7 @@ type Cache[K comparable, V any] struct {
 	admissions int             // Admission count for sketch reset
 	mu         sync.RWMutex
 	onEvict    func(K, V)
+	stats      cache.StatsCounter
 }
 
 // entry represents a cache entry
@@ -195,9 +198,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 			c.protected.MoveToFront(entry.element)
 		}
 
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -571,11 +576,86 @@ func (c *Cache[K, V]) removeEntry(entry *entry[K, V], callEvict bool) {
 	entry.segment.Remove(entry.element)
 	delete(c.items, entry.key)
 
+	if callEvict {
+		c.stats.RecordEviction()
+	}
+
 	if callEvict && c.onEvict != nil {
 		c.onEvict(entry.key, entry.value)
 	}
 }
 
+// Snapshot returns all entries from least to most valuable:
+// probation, then window, then protected, each from least to most recently used.
+// Frequency holds the sketch estimate of each key.
+func (c *Cache[K, V]) Snapshot() []cache.SnapshotEntry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]cache.SnapshotEntry[K, V], 0, len(c.items))
+	for _, segment := range []*list.List{c.probation, c.window, c.protected} {
+		for element := segment.Back(); element != nil; element = element.Prev() {
+			entry := element.Value.(*entry[K, V])
+			entries = append(entries, cache.SnapshotEntry[K, V]{
+				Key:       entry.key,
+				Value:     entry.value,
+				Frequency: uint64(c.sketch.EstimateCount(keyToBytes(entry.key))), // #nosec G115 -- sketch counters are capped at 15
+			})
+		}
+	}
+	return entries
+}
+
+// Restore replaces the cache contents with entries ordered from least to most valuable.
+// The most valuable entries fill protected, then window, then probation; the rest are dropped
+// without eviction callbacks. The sketch is rebuilt from each entry's Frequency.
+func (c *Cache[K, V]) Restore(entries []cache.SnapshotEntry[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*entry[K, V], c.capacity)
+	c.window.Init()
+	c.probation.Init()
+	c.protected.Init()
+	c.sketch = NewCountMinSketch(c.capacity)
+	c.doorkeeper = make(map[K]struct{}, c.capacity*2)
+	c.admissions = 0
+
+	protectedCapacity := int(float64(c.mainSize) * 0.8)
+	tiers := []struct {
+		segment  *list.List
+		capacity int
+	}{
+		{c.protected, protectedCapacity},
+		{c.window, c.windowSize},
+		{c.probation, c.mainSize - protectedCapacity},
+	}
+
+	tier := 0
+	for i := len(entries) - 1; i >= 0 && tier < len(tiers); i-- {
+		e := entries[i]
+		if _, exists := c.items[e.Key]; exists {
+			continue
+		}
+		for tier < len(tiers) && tiers[tier].segment.Len() >= tiers[tier].capacity {
+			tier++
+		}
+		if tier == len(tiers) {
+			break
+		}
+
+		entry := &entry[K, V]{key: e.Key, value: e.Value, segment: tiers[tier].segment}
+		entry.element = entry.segment.PushBack(entry)
+		c.items[e.Key] = entry
+		c.doorkeeper[e.Key] = struct{}{}
+
+		keyBytes := keyToBytes(e.Key)
+		for n := uint64(0); n < min(e.Frequency, 15); n++ {
+			c.sketch.Add(keyBytes)
+		}
+	}
+}
+
 // Stats returns cache statistics
 func (c *Cache[K, V]) Stats() Stats {
 	c.mu.RLock()
@@ -594,6 +674,14 @@ func (c *Cache[K, V]) Stats() Stats {
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
 	Size           int // actual cache size
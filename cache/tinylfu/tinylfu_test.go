@@ -4,6 +4,8 @@
 	"fmt"
 	"sync"
 	"testing"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 func TestNew(t *testing.T) {
@@ -3579,3 +3581,54 @@ func TestZipfAccessPattern(t *testing.T) {
 		t.Errorf("Cache size %d exceeds capacity %d", cache.Len(), cache.Cap())
 	}
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	c, _ := New[string, int](100)
+	for i := 0; i < 50; i++ {
+		c.Put(fmt.Sprintf("k%d", i), i)
+	}
+	for i := 0; i < 5; i++ {
+		c.Get("k49")
+	}
+
+	entries := c.Snapshot()
+	if len(entries) != c.Len() {
+		t.Fatalf("Expected %d entries, got %d", c.Len(), len(entries))
+	}
+
+	restored, _ := New[string, int](100)
+	restored.Restore(entries)
+
+	if restored.Len() != len(entries) {
+		t.Errorf("Expected %d restored entries, got %d", len(entries), restored.Len())
+	}
+	for _, e := range entries {
+		if v, ok := restored.Peek(e.Key); !ok || v != e.Value {
+			t.Errorf("Expected %s=%d to be restored", e.Key, e.Value)
+		}
+	}
+	if got := restored.sketch.EstimateCount(keyToBytes("k49")); got < 5 {
+		t.Errorf("Expected sketch estimate of k49 to be restored, got %d", got)
+	}
+}
+
+func TestRestoreFillsSegments(t *testing.T) {
+	c, _ := New[int, int](100)
+
+	entries := make([]cache.SnapshotEntry[int, int], 150)
+	for i := range entries {
+		entries[i] = cache.SnapshotEntry[int, int]{Key: i, Value: i, Frequency: 1}
+	}
+	c.Restore(entries)
+
+	stats := c.Stats()
+	if stats.Size != 100 || stats.WindowSize != stats.WindowCapacity {
+		t.Errorf("Expected full cache with full window, got %+v", stats)
+	}
+	if c.Contains(49) || !c.Contains(149) {
+		t.Error("Expected least valuable entries to be dropped")
+	}
+	if c.protected.Front().Value.(*entry[int, int]).key != 149 {
+		t.Error("Expected most valuable entry at the front of protected")
+	}
+}
@@ -0,0 +1,24 @@
+package cache
+
+// Weigher returns the cost of an entry, usually its approximate size in bytes.
+// It must be deterministic for a given key and value and never return a negative weight.
+type Weigher[K comparable, V any] func(key K, value V) int64
+
+// Weighted is implemented by caches that can bound the total weight of their entries
+// in addition to the entry count.
+type Weighted interface {
+	// Weight returns the total weight of the entries currently held
+	Weight() int64
+	// MaxWeight returns the weight limit, or 0 when no Weigher is configured
+	MaxWeight() int64
+	// ResizeWeight changes the weight limit, evicting entries until the total fits
+	ResizeWeight(maxWeight int64) error
+}
+
+// weightOf copies the weight of c into stats when c implements Weighted
+func weightOf(c any, stats *Stats) {
+	if w, ok := c.(Weighted); ok {
+		stats.Weight = w.Weight()
+		stats.MaxWeight = w.MaxWeight()
+	}
+}
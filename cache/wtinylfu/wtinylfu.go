@@ -5,6 +5,9 @@
 	"fmt"
 	"hash/fnv"
 	"sync"
+
+	"github.com/lazygophers/utils/cache"
+	"github.com/lazygophers/utils/human"
 )
 
 // Cache represents a Window-TinyLFU cache that combines LRU window with frequency-based main cache
@@ -20,6 +23,12 @@ type Cache[K comparable, V any] struct {
 	probationCap int                // Probation space capacity (20% of main cache)
 	mu           sync.RWMutex
 	onEvict      func(K, V)
+	stats        cache.StatsCounter
+
+	// 按重量限制容量：weigher 为 nil 时只按条目数限制
+	weigher   cache.Weigher[K, V]
+	maxWeight int64
+	weight    int64
 }
 
 // entry represents a cache entry
@@ -28,6 +37,7 @@ type entry[K comparable, V any] struct {
 	value   V
 	element *list.Element // Element in window, probation, or protected list
 	inSpace space         // Which space the entry is in
+	weight  int64
 }
 
 // space represents which cache space an entry belongs to
@@ -100,6 +110,27 @@ func NewWithEvict[K comparable, V any](capacity int, onEvict func(K, V)) (*Cache
 	return cache, nil
 }
 
+// NewWithWeigher creates a new Window-TinyLFU cache bounded by both the entry count and the total weight.
+// weigher returns the weight of each entry (e.g. its size in bytes); entries are evicted from probation,
+// then protected, then the window, until the total weight is at most maxWeight.
+// capacity still sizes the window, the main spaces and the frequency sketch.
+func NewWithWeigher[K comparable, V any](capacity int, maxWeight int64, weigher cache.Weigher[K, V]) (*Cache[K, V], error) {
+	if maxWeight <= 0 {
+		return nil, fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+	if weigher == nil {
+		return nil, fmt.Errorf("weigher must not be nil")
+	}
+
+	cache, err := New[K, V](capacity)
+	if err != nil {
+		return nil, err
+	}
+	cache.weigher = weigher
+	cache.maxWeight = maxWeight
+	return cache, nil
+}
+
 // Get retrieves a value from the cache
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.mu.Lock()
@@ -107,9 +138,11 @@ func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 
 	if entry, exists := c.items[key]; exists {
 		c.recordAccess(entry)
+		c.stats.RecordHit()
 		return entry.value, true
 	}
 
+	c.stats.RecordMiss()
 	var zero V
 	return zero, false
 }
@@ -119,12 +152,24 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	weight := c.weigh(key, value)
+	if c.maxWeight > 0 && weight > c.maxWeight {
+		// 单个条目超过重量上限，不缓存；旧值视为被淘汰
+		if entry, exists := c.items[key]; exists {
+			c.evictEntry(entry)
+			return true
+		}
+		return false
+	}
+
 	// Check if key already exists
 	if entry, exists := c.items[key]; exists {
 		// Update existing entry
 		entry.value = value
+		c.weight += weight - entry.weight
+		entry.weight = weight
 		c.recordAccess(entry)
-		return false
+		return c.evictForWeight(entry)
 	}
 
 	// Check if cache is full
@@ -137,6 +182,7 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 		key:     key,
 		value:   value,
 		inSpace: spaceWindow,
+		weight:  weight,
 	}
 
 	// Check if window is full before adding
@@ -147,11 +193,12 @@ func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
 
 	entry.element = c.window.PushFront(entry)
 	c.items[key] = entry
+	c.weight += weight
 
 	// Record access in sketch
 	c.sketch.increment(c.hash(key))
 
-	return evicted
+	return c.evictForWeight(entry) || evicted
 }
 
 // Remove removes a key from the cache
@@ -220,6 +267,7 @@ func (c *Cache[K, V]) Clear() {
 	c.probation.Init()
 	c.protected.Init()
 	c.sketch.clear()
+	c.weight = 0
 }
 
 // Keys returns all keys in the cache
@@ -343,7 +391,7 @@ func (c *Cache[K, V]) recordAccess(e *entry[K, V]) {
 			probationElement := c.probation.Back()
 			if probationElement != nil {
 				probationVictim := probationElement.Value.(*cacheEntry)
-				c.removeEntry(probationVictim)
+				c.evictEntry(probationVictim)
 			}
 			e.element = c.probation.PushFront(e)
 			e.inSpace = spaceProbation
@@ -417,7 +465,7 @@ func (c *Cache[K, V]) evictFromWindow() bool {
 			// Window victim has higher frequency, admit it
 			c.window.Remove(victim.element)
 			c.probation.Remove(probationVictim.element)
-			c.removeEntry(probationVictim)
+			c.evictEntry(probationVictim)
 
 			victim.element = c.probation.PushFront(victim)
 			victim.inSpace = spaceProbation
@@ -426,7 +474,7 @@ func (c *Cache[K, V]) evictFromWindow() bool {
 	}
 
 	// Evict window victim
-	c.removeEntry(victim)
+	c.evictEntry(victim)
 	return true
 }
 
@@ -436,7 +484,7 @@ func (c *Cache[K, V]) evictFromProbation() bool {
 	if element != nil {
 		type cacheEntry = entry[K, V]
 		entry := element.Value.(*cacheEntry)
-		c.removeEntry(entry)
+		c.evictEntry(entry)
 		return true
 	}
 	return false
@@ -448,7 +496,7 @@ func (c *Cache[K, V]) evictFromProtected() bool {
 	if element != nil {
 		type cacheEntry = entry[K, V]
 		entry := element.Value.(*cacheEntry)
-		c.removeEntry(entry)
+		c.evictEntry(entry)
 		return true
 	}
 	return false
@@ -476,12 +524,18 @@ func (c *Cache[K, V]) demoteFromProtected() {
 	probationElement := c.probation.Back()
 	if probationElement != nil {
 		probationVictim := probationElement.Value.(*cacheEntry)
-		c.removeEntry(probationVictim)
+		c.evictEntry(probationVictim)
 	}
 	entry.element = c.probation.PushFront(entry)
 	entry.inSpace = spaceProbation
 }
 
+// evictEntry removes an entry to make room and records the eviction
+func (c *Cache[K, V]) evictEntry(entry *entry[K, V]) {
+	c.stats.RecordEviction()
+	c.removeEntry(entry)
+}
+
 // removeEntry removes an entry completely from the cache
 func (c *Cache[K, V]) removeEntry(entry *entry[K, V]) {
 	switch entry.inSpace {
@@ -494,6 +548,7 @@ func (c *Cache[K, V]) removeEntry(entry *entry[K, V]) {
 	}
 
 	delete(c.items, entry.key)
+	c.weight -= entry.weight
 
 	// Call eviction callback
 	if c.onEvict != nil {
@@ -501,6 +556,78 @@ func (c *Cache[K, V]) removeEntry(entry *entry[K, V]) {
 	}
 }
 
+// weightVictim returns the entry to evict for weight: the least recently used
+// entry of probation, then protected, then the window, skipping keep
+func (c *Cache[K, V]) weightVictim(keep *entry[K, V]) *entry[K, V] {
+	type cacheEntry = entry[K, V]
+	for _, l := range []*list.List{c.probation, c.protected, c.window} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			if victim := element.Value.(*cacheEntry); victim != keep {
+				return victim
+			}
+		}
+	}
+	return nil
+}
+
+// evictForWeight evicts entries until the total weight fits, never evicting keep
+func (c *Cache[K, V]) evictForWeight(keep *entry[K, V]) (evicted bool) {
+	for c.maxWeight > 0 && c.weight > c.maxWeight {
+		victim := c.weightVictim(keep)
+		if victim == nil {
+			break
+		}
+		c.evictEntry(victim)
+		evicted = true
+	}
+	return evicted
+}
+
+// weigh returns the weight of an entry, 0 when no weigher is configured
+func (c *Cache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 0
+	}
+	if weight := c.weigher(key, value); weight > 0 {
+		return weight
+	}
+	return 0
+}
+
+// Weight returns the total weight of all entries, 0 when no weigher is configured
+func (c *Cache[K, V]) Weight() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.weight
+}
+
+// MaxWeight returns the weight limit, 0 when no weigher is configured
+func (c *Cache[K, V]) MaxWeight() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.maxWeight
+}
+
+// ResizeWeight changes the weight limit, evicting entries until the total fits
+func (c *Cache[K, V]) ResizeWeight(maxWeight int64) error {
+	if maxWeight <= 0 {
+		return fmt.Errorf("max weight must be positive, got %d", maxWeight)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.weigher == nil {
+		return fmt.Errorf("cache has no weigher")
+	}
+
+	c.maxWeight = maxWeight
+	c.evictForWeight(nil)
+	return nil
+}
+
 // hash generates a hash for the key using FNV-1a hash
 func (c *Cache[K, V]) hash(key K) uint32 {
 	// Convert key to string representation
@@ -563,6 +690,17 @@ func (s *countMinSketch) estimate(hash uint32) uint8 {
 	return min
 }
 
+// seed raises the counters of a hash so that its estimate is at least count
+func (s *countMinSketch) seed(hash uint32, count uint64) {
+	value := uint8(min(count, 255)) // #nosec G115 -- clamped to the counter range
+	for i := 0; i < s.depth; i++ {
+		idx := (hash + uint32(i)) & s.mask // #nosec G115 -- i is loop counter 0..3 (depth=4), always safe
+		if s.table[i][idx] < value {
+			s.table[i][idx] = value
+		}
+	}
+}
+
 // clear resets the sketch
 func (s *countMinSketch) clear() {
 	for i := range s.table {
@@ -573,6 +711,78 @@ func (s *countMinSketch) clear() {
 	s.size = 0
 }
 
+// Snapshot returns all entries from least to most valuable:
+// probation, then window, then protected, each from least to most recently used.
+// Frequency holds the sketch estimate of each key.
+func (c *Cache[K, V]) Snapshot() []cache.SnapshotEntry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	type cacheEntry = entry[K, V]
+	entries := make([]cache.SnapshotEntry[K, V], 0, len(c.items))
+	for _, l := range []*list.List{c.probation, c.window, c.protected} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			entry := element.Value.(*cacheEntry)
+			entries = append(entries, cache.SnapshotEntry[K, V]{
+				Key:       entry.key,
+				Value:     entry.value,
+				Frequency: uint64(c.sketch.estimate(c.hash(entry.key))),
+			})
+		}
+	}
+	return entries
+}
+
+// Restore replaces the cache contents with entries ordered from least to most valuable.
+// The most valuable entries fill protected, then window, then probation; the rest, and entries
+// that would exceed the weight limit, are dropped without eviction callbacks.
+// The sketch is rebuilt from each entry's Frequency.
+func (c *Cache[K, V]) Restore(entries []cache.SnapshotEntry[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*entry[K, V], c.capacity)
+	c.window.Init()
+	c.probation.Init()
+	c.protected.Init()
+	c.sketch.clear()
+	c.weight = 0
+
+	tiers := []struct {
+		list     *list.List
+		space    space
+		capacity int
+	}{
+		{c.protected, spaceProtected, c.protectedCap},
+		{c.window, spaceWindow, c.windowSize},
+		{c.probation, spaceProbation, c.probationCap},
+	}
+
+	tier := 0
+	for i := len(entries) - 1; i >= 0 && tier < len(tiers); i-- {
+		e := entries[i]
+		if _, exists := c.items[e.Key]; exists {
+			continue
+		}
+		weight := c.weigh(e.Key, e.Value)
+		if c.maxWeight > 0 && c.weight+weight > c.maxWeight {
+			continue
+		}
+		for tier < len(tiers) && tiers[tier].list.Len() >= tiers[tier].capacity {
+			tier++
+		}
+		if tier == len(tiers) {
+			break
+		}
+
+		entry := &entry[K, V]{key: e.Key, value: e.Value, inSpace: tiers[tier].space, weight: weight}
+		entry.element = tiers[tier].list.PushBack(entry)
+		c.items[e.Key] = entry
+		c.weight += weight
+		c.sketch.seed(c.hash(e.Key), e.Frequency)
+	}
+}
+
 // Stats returns cache statistics
 func (c *Cache[K, V]) Stats() Stats {
 	c.mu.RLock()
@@ -587,19 +797,41 @@ func (c *Cache[K, V]) Stats() Stats {
 		ProbationCap:   c.probationCap,
 		ProtectedSize:  c.protected.Len(),
 		ProtectedCap:   c.protectedCap,
+		Weight:         c.weight,
+		MaxWeight:      c.maxWeight,
 	}
 }
 
+// CacheStats returns hit, miss and eviction counters in the common cache.Stats form
+func (c *Cache[K, V]) CacheStats() cache.Stats {
+	stats := c.stats.Snapshot()
+	stats.Size = c.Len()
+	stats.Capacity = c.Cap()
+	stats.Weight = c.Weight()
+	stats.MaxWeight = c.MaxWeight()
+	return stats
+}
+
 // Stats represents cache statistics
 type Stats struct {
-	Size           int // actual cache size
-	Capacity       int // maximum cache capacity
-	WindowSize     int // current window size
-	WindowCapacity int // window capacity
-	ProbationSize  int // current probation size
-	ProbationCap   int // probation capacity
-	ProtectedSize  int // current protected size
-	ProtectedCap   int // protected capacity
+	Size           int   // actual cache size
+	Capacity       int   // maximum cache capacity
+	WindowSize     int   // current window size
+	WindowCapacity int   // window capacity
+	ProbationSize  int   // current probation size
+	ProbationCap   int   // probation capacity
+	ProtectedSize  int   // current protected size
+	ProtectedCap   int   // protected capacity
+	Weight         int64 // total weight of all entries, 0 without a weigher
+	MaxWeight      int64 // weight limit, 0 without a weigher
+}
+
+// String returns a short summary; weights are printed as byte sizes
+func (s Stats) String() string {
+	if s.MaxWeight <= 0 {
+		return fmt.Sprintf("size=%d/%d", s.Size, s.Capacity)
+	}
+	return fmt.Sprintf("size=%d/%d weight=%s/%s", s.Size, s.Capacity, human.ByteSize(s.Weight), human.ByteSize(s.MaxWeight))
 }
 
 func max(a, b int) int {
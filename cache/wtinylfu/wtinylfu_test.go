@@ -2,8 +2,11 @@
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/lazygophers/utils/cache"
 )
 
 func TestNew(t *testing.T) {
@@ -2748,3 +2751,152 @@ func TestHashDifferentKeys(t *testing.T) {
 		t.Error("Different keys should produce different hashes")
 	}
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	c, _ := New[string, int](100)
+	for i := 0; i < 50; i++ {
+		c.Put(fmt.Sprintf("k%d", i), i)
+	}
+	for i := 0; i < 3; i++ {
+		c.Get("k49")
+	}
+
+	entries := c.Snapshot()
+	if len(entries) != c.Len() {
+		t.Fatalf("Expected %d entries, got %d", c.Len(), len(entries))
+	}
+	if last := entries[len(entries)-1]; last.Key != "k49" {
+		t.Errorf("Expected protected k49 to be the most valuable, got %s", last.Key)
+	}
+
+	restored, _ := New[string, int](100)
+	restored.Restore(entries)
+
+	if restored.Len() != len(entries) {
+		t.Errorf("Expected %d restored entries, got %d", len(entries), restored.Len())
+	}
+	for _, e := range entries {
+		if v, ok := restored.Peek(e.Key); !ok || v != e.Value {
+			t.Errorf("Expected %s=%d to be restored", e.Key, e.Value)
+		}
+		if got := restored.sketch.estimate(restored.hash(e.Key)); uint64(got) < e.Frequency {
+			t.Errorf("Expected sketch estimate of %s >= %d, got %d", e.Key, e.Frequency, got)
+		}
+	}
+}
+
+func TestRestoreFillsSpaces(t *testing.T) {
+	c, _ := New[int, int](100)
+
+	entries := make([]cache.SnapshotEntry[int, int], 150)
+	for i := range entries {
+		entries[i] = cache.SnapshotEntry[int, int]{Key: i, Value: i}
+	}
+	c.Restore(entries)
+
+	stats := c.Stats()
+	if stats.Size != 100 || stats.ProtectedSize != stats.ProtectedCap ||
+		stats.WindowSize != stats.WindowCapacity || stats.ProbationSize != stats.ProbationCap {
+		t.Errorf("Expected every space to be full, got %+v", stats)
+	}
+	if c.Contains(49) || !c.Contains(149) {
+		t.Error("Expected least valuable entries to be dropped")
+	}
+}
+
+func TestNewWithWeigher(t *testing.T) {
+	weigher := func(key string, value []byte) int64 { return int64(len(value)) }
+	if _, err := NewWithWeigher[string, []byte](10, 0, weigher); err == nil {
+		t.Error("Expected error for non-positive max weight")
+	}
+	if _, err := NewWithWeigher[string, []byte](10, 100, nil); err == nil {
+		t.Error("Expected error for nil weigher")
+	}
+	if _, err := NewWithWeigher[string, []byte](0, 100, weigher); err == nil {
+		t.Error("Expected error for non-positive capacity")
+	}
+}
+
+func TestWeigherEviction(t *testing.T) {
+	var evicted []string
+	c, err := NewWithWeigher[string, []byte](100, 10, func(key string, value []byte) int64 { return int64(len(value)) })
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	c.onEvict = func(key string, value []byte) { evicted = append(evicted, key) }
+
+	c.Put("a", make([]byte, 4))
+	c.Put("b", make([]byte, 4))
+	if c.Weight() != 8 {
+		t.Errorf("Expected weight 8, got %d", c.Weight())
+	}
+
+	if !c.Put("c", make([]byte, 4)) {
+		t.Error("Expected Put to report a weight eviction")
+	}
+	if c.Weight() > c.MaxWeight() || !c.Contains("c") || len(evicted) != 1 {
+		t.Errorf("Expected one eviction keeping c, weight %d, evicted %v", c.Weight(), evicted)
+	}
+
+	// 更新为更大的值同样触发按重量淘汰
+	c.Put("c", make([]byte, 9))
+	if c.Weight() != 9 || c.Len() != 1 {
+		t.Errorf("Expected only c with weight 9, got %d entries weighing %d", c.Len(), c.Weight())
+	}
+
+	c.Remove("c")
+	if c.Weight() != 0 {
+		t.Errorf("Expected weight 0 after Remove, got %d", c.Weight())
+	}
+}
+
+func TestWeigherOversizedEntry(t *testing.T) {
+	c, _ := NewWithWeigher[string, []byte](100, 10, func(key string, value []byte) int64 { return int64(len(value)) })
+
+	c.Put("a", make([]byte, 5))
+	if c.Put("big", make([]byte, 11)) || c.Contains("big") {
+		t.Error("Expected entry heavier than max weight not to be cached")
+	}
+	if !c.Put("a", make([]byte, 11)) || c.Contains("a") {
+		t.Error("Expected oversized update to evict the old value")
+	}
+	if c.Weight() != 0 {
+		t.Errorf("Expected weight 0, got %d", c.Weight())
+	}
+}
+
+func TestResizeWeight(t *testing.T) {
+	c, _ := NewWithWeigher[string, []byte](100, 100, func(key string, value []byte) int64 { return int64(len(value)) })
+	for i := 0; i < 10; i++ {
+		c.Put(string(rune('a'+i)), make([]byte, 10))
+	}
+
+	if err := c.ResizeWeight(35); err != nil {
+		t.Fatalf("ResizeWeight failed: %v", err)
+	}
+	if c.Weight() > 35 || c.Len() != 3 {
+		t.Errorf("Expected 3 entries within weight 35, got %d weighing %d", c.Len(), c.Weight())
+	}
+	if err := c.ResizeWeight(0); err == nil {
+		t.Error("Expected error for non-positive max weight")
+	}
+
+	plain, _ := New[string, []byte](10)
+	if err := plain.ResizeWeight(10); err == nil {
+		t.Error("Expected error without weigher")
+	}
+	if plain.Weight() != 0 || plain.MaxWeight() != 0 {
+		t.Error("Expected zero weight without weigher")
+	}
+
+	stats := c.Stats()
+	if stats.Weight != c.Weight() || stats.MaxWeight != 35 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if s := stats.String(); !strings.Contains(s, "weight=30 B/35 B") {
+		t.Errorf("Unexpected stats string %q", s)
+	}
+	if cs := c.CacheStats(); cs.Weight != 30 || cs.MaxWeight != 35 {
+		t.Errorf("Unexpected cache stats %+v", cs)
+	}
+}
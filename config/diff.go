@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FieldChange 描述一次重载中发生变化的字段
+// Path 为 Go 字段路径，如 "Database.Host"、"Labels[env]"；Old/New 为变化前后的值，字段新增或删除时对应一侧为 nil
+type FieldChange struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// Diff 比较同类型的两个配置，返回字段级差异，按 Path 排序
+// 结构体与 map 逐层展开，slice 等其余类型整体比较
+func Diff(old, new any) []FieldChange {
+	var changes []FieldChange
+	diffValue(&changes, "", reflect.ValueOf(old), reflect.ValueOf(new))
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffValue(changes *[]FieldChange, path string, old, new reflect.Value) {
+	for old.IsValid() && (old.Kind() == reflect.Ptr || old.Kind() == reflect.Interface) && !old.IsNil() {
+		old = old.Elem()
+	}
+	for new.IsValid() && (new.Kind() == reflect.Ptr || new.Kind() == reflect.Interface) && !new.IsNil() {
+		new = new.Elem()
+	}
+
+	if !old.IsValid() || !new.IsValid() || old.Type() != new.Type() {
+		if !old.IsValid() && !new.IsValid() {
+			return
+		}
+		*changes = append(*changes, FieldChange{Path: path, Old: valueInterface(old), New: valueInterface(new)})
+		return
+	}
+
+	switch old.Kind() {
+	case reflect.Struct:
+		t := old.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffValue(changes, joinFieldPath(path, field.Name), old.Field(i), new.Field(i))
+		}
+
+	case reflect.Map:
+		seen := make(map[any]bool, old.Len())
+		iter := old.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			seen[key.Interface()] = true
+			diffValue(changes, fmt.Sprintf("%s[%v]", path, key.Interface()), iter.Value(), new.MapIndex(key))
+		}
+		iter = new.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			if !seen[key.Interface()] {
+				diffValue(changes, fmt.Sprintf("%s[%v]", path, key.Interface()), reflect.Value{}, iter.Value())
+			}
+		}
+
+	default:
+		if old.CanInterface() && !reflect.DeepEqual(old.Interface(), new.Interface()) {
+			*changes = append(*changes, FieldChange{Path: path, Old: old.Interface(), New: new.Interface()})
+		}
+	}
+}
+
+// deepCopyValue 深拷贝指针、切片、map、interface 与结构体的导出字段，未导出字段浅拷贝
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(deepCopyValue(v.Elem()))
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopyValue(v.Elem()))
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(deepCopyValue(v.Field(i)))
+			}
+		}
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return out
+
+	default:
+		return v
+	}
+}
+
+func joinFieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func valueInterface(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
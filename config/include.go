@@ -0,0 +1,1010 @@
+package config
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/lazygophers/log"
+	"github.com/lazygophers/utils/json"
+	"github.com/lazygophers/utils/validator"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/yosuke-furukawa/json5/encoding/json5"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// includeKeys 是 LoadConfigWithIncludes / ResolveIncludes 识别的指令键，按顺序处理
+	includeKeys = []string{"include", "$include", "$import"}
+	// importKeys 是 LoadConfig、Watch、LoadLayered 识别的指令键，
+	// 不识别 include 以免与普通字段冲突
+	importKeys = []string{"$include", "$import"}
+)
+
+// SliceMergeMode 决定合并时切片的处理方式
+type SliceMergeMode int
+
+const (
+	// SliceReplace 后者整体替换前者（默认）
+	SliceReplace SliceMergeMode = iota
+	// SliceAppend 后者追加到前者之后
+	SliceAppend
+)
+
+// IncludeOption 配置 include 加载
+type IncludeOption func(*includeOptions)
+
+type includeOptions struct {
+	sliceMerge SliceMergeMode
+	keys       []string
+}
+
+// WithSliceMerge 设置切片合并方式，默认 SliceReplace
+func WithSliceMerge(mode SliceMergeMode) IncludeOption {
+	return func(o *includeOptions) {
+		o.sliceMerge = mode
+	}
+}
+
+// withIncludeKeys 指定识别的指令键，默认 includeKeys
+func withIncludeKeys(keys []string) IncludeOption {
+	return func(o *includeOptions) {
+		o.keys = keys
+	}
+}
+
+// LoadConfigWithIncludes 加载 path 及其通过 include / $include / $import 引入的文件，合并后解码到 c，
+// 再应用 env 覆盖、解析密钥并执行 validator.Struct
+func LoadConfigWithIncludes(c any, path string, opts ...IncludeOption) (err error) {
+	err = LoadConfigWithIncludesSkipValidate(c, path, opts...)
+	if err != nil {
+		return err
+	}
+
+	err = validator.Struct(c)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return err
+	}
+
+	log.Info("load config with includes success")
+	return nil
+}
+
+// LoadConfigWithIncludesSkipValidate 与 LoadConfigWithIncludes 相同但跳过校验
+func LoadConfigWithIncludesSkipValidate(c any, path string, opts ...IncludeOption) error {
+	rv := reflect.ValueOf(c)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("v must be a pointer to struct")
+	}
+
+	_, err := loadConfigFile(c, path, opts...)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return err
+	}
+	configPath = path
+
+	// NOTE: 使用环境变量覆盖配置值
+	err = overrideConfigWithEnv(c)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return err
+	}
+
+	// NOTE: 解析 ${env:...}、${file:...} 引用与 enc:v1: 加密值
+	err = resolveSecrets(c)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return err
+	}
+
+	return nil
+}
+
+// loadConfigFile 把 path 解码到 c，返回参与本次加载的文件（根文件在前）。
+// 文件中没有引入指令时直接用该格式的 Unmarshaler 解码；
+// 有指令时展开合并后按根文件格式重新编码，再交给同一个 Unmarshaler
+func loadConfigFile(c any, path string, opts ...IncludeOption) ([]string, error) {
+	ext := filepath.Ext(path)
+	supported, ok := supportedExtMap[ext]
+	if !ok {
+		return nil, fmt.Errorf("unsupported config file format:%v", ext)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := newIncludeLoader(abs, opts...)
+
+	// 读不成通用结构（如只能解码到结构体的自定义格式）时同样直接解码，由 Unmarshaler 报告错误
+	tree, err := readConfigTree(abs)
+	if err != nil || !hasIncludes(tree, loader.options.keys) {
+		file, err := os.Open(abs)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		return []string{abs}, supported.Unmarshaler(file, c)
+	}
+
+	tree, err = loader.expand(tree, filepath.Dir(abs))
+	if err != nil {
+		return nil, &includeError{err: err}
+	}
+
+	data, err := encodeTree(ext, normalizeTree(tree, reflect.TypeOf(c), ext), reflect.TypeOf(c))
+	if err != nil {
+		return nil, &includeError{err: fmt.Errorf("config: encode merged %s: %w", filepath.Base(path), err)}
+	}
+	if ext == ".hcl" {
+		// 合并结果按 HCL 的 JSON 语法编码
+		return loader.files, hclsimple.Decode(filepath.Base(path)+".json", data, nil, c)
+	}
+	return loader.files, supported.Unmarshaler(bytes.NewReader(data), c)
+}
+
+// includeError 展开引入指令失败（循环、文件缺失、无法合并等）。
+// LoadConfig 对文件本身的解码错误只记录日志，引入错误则直接返回
+type includeError struct {
+	err error
+}
+
+func (e *includeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *includeError) Unwrap() error {
+	return e.err
+}
+
+// hasIncludes 判断 node 及其子对象中是否有 keys 中的指令
+func hasIncludes(node any, keys []string) bool {
+	switch node := node.(type) {
+	case map[string]any:
+		for _, key := range keys {
+			if _, ok := node[key]; ok {
+				return true
+			}
+		}
+		for _, value := range node {
+			if hasIncludes(value, keys) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range node {
+			if hasIncludes(item, keys) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// configFiles 返回 path 及其引入的文件，解析失败时只返回 path
+func configFiles(path string, opts ...IncludeOption) []string {
+	loader := newIncludeLoader(path, opts...)
+	_, err := loader.load(path)
+	if err != nil {
+		return []string{path}
+	}
+	return loader.files
+}
+
+// ResolveIncludes 读取 path 并递归展开 include / $include / $import 指令，返回合并后的通用结构，便于调试。
+//
+// 指令可以出现在任意一层对象中，值为文件路径、逗号分隔的路径或路径列表，支持 glob，
+// 相对路径基于所在文件的目录。被引入的文件按顺序合并为该对象的底层，对象自身的键优先级最高；
+// map 逐层合并（键名忽略大小写），切片按 WithSliceMerge 处理，其余值后者覆盖前者。
+// 不同格式可以混用，如 yaml 根文件引入 toml 片段；循环引入返回包含引入链的错误
+func ResolveIncludes(path string, opts ...IncludeOption) (map[string]any, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return newIncludeLoader(abs, opts...).load(abs)
+}
+
+type includeLoader struct {
+	options includeOptions
+	root    string
+	stack   []string
+	files   []string // 已读取的文件，按读取顺序
+}
+
+func newIncludeLoader(root string, opts ...IncludeOption) *includeLoader {
+	options := includeOptions{keys: includeKeys}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &includeLoader{
+		options: options,
+		root:    filepath.Dir(root),
+		files:   []string{root},
+	}
+}
+
+func (l *includeLoader) load(path string) (map[string]any, error) {
+	for i, p := range l.stack {
+		if p == path {
+			chain := make([]string, 0, len(l.stack)-i+1)
+			for _, p := range append(l.stack[i:], path) {
+				chain = append(chain, l.display(p))
+			}
+			return nil, fmt.Errorf("config: include cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	l.stack = append(l.stack, path)
+	defer func() {
+		l.stack = l.stack[:len(l.stack)-1]
+	}()
+
+	if path != l.files[0] {
+		l.files = append(l.files, path)
+	}
+	tree, err := readConfigTree(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: load %s: %w", l.display(path), err)
+	}
+	return l.expand(tree, filepath.Dir(path))
+}
+
+// display 返回相对根文件目录的路径，用于错误信息
+func (l *includeLoader) display(path string) string {
+	rel, err := filepath.Rel(l.root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// expand 展开 node 及其子对象中的指令
+func (l *includeLoader) expand(node map[string]any, dir string) (map[string]any, error) {
+	var base any
+	for _, key := range l.options.keys {
+		value, ok := node[key]
+		if !ok {
+			continue
+		}
+		delete(node, key)
+
+		paths, err := includePaths(value, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			included, err := l.load(path)
+			if err != nil {
+				return nil, err
+			}
+			base = mergeTree(base, included, l.options.sliceMerge)
+		}
+	}
+
+	for _, key := range sortedKeys(node) {
+		switch value := node[key].(type) {
+		case map[string]any:
+			expanded, err := l.expand(value, dir)
+			if err != nil {
+				return nil, err
+			}
+			node[key] = expanded
+
+		case []any:
+			for i, item := range value {
+				if m, ok := item.(map[string]any); ok {
+					expanded, err := l.expand(m, dir)
+					if err != nil {
+						return nil, err
+					}
+					value[i] = expanded
+				}
+			}
+		}
+	}
+
+	if base == nil {
+		return node, nil
+	}
+	return mergeTree(base, node, l.options.sliceMerge).(map[string]any), nil
+}
+
+// includePaths 解析指令的值，glob 结果按文件名排序
+func includePaths(value any, dir string) ([]string, error) {
+	var patterns []string
+	switch value := value.(type) {
+	case string:
+		for _, p := range strings.Split(value, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				patterns = append(patterns, p)
+			}
+		}
+	case []any:
+		for _, item := range value {
+			p, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("config: include path must be a string, got %T", item)
+			}
+			patterns = append(patterns, p)
+		}
+	default:
+		return nil, fmt.Errorf("config: include must be a path or a list of paths, got %T", value)
+	}
+
+	var paths []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			paths = append(paths, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// mergeTree 把 src 合并到 dst 之上，不修改 dst
+func mergeTree(dst, src any, mode SliceMergeMode) any {
+	if dstMap, ok := dst.(map[string]any); ok {
+		if srcMap, ok := src.(map[string]any); ok {
+			out := make(map[string]any, len(dstMap)+len(srcMap))
+			for k, v := range dstMap {
+				out[k] = v
+			}
+			for _, k := range sortedKeys(srcMap) {
+				key := k
+				if _, ok := out[k]; !ok {
+					// 不同格式的键名大小写可能不同，沿用先出现的写法
+					for _, existing := range sortedKeys(out) {
+						if strings.EqualFold(existing, k) {
+							key = existing
+							break
+						}
+					}
+				}
+				if current, ok := out[key]; ok {
+					out[key] = mergeTree(current, srcMap[k], mode)
+				} else {
+					out[key] = srcMap[k]
+				}
+			}
+			return out
+		}
+	}
+
+	if mode == SliceAppend {
+		if dstSlice, ok := dst.([]any); ok {
+			if srcSlice, ok := src.([]any); ok {
+				out := make([]any, 0, len(dstSlice)+len(srcSlice))
+				return append(append(out, dstSlice...), srcSlice...)
+			}
+		}
+	}
+
+	return src
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readConfigTree 按扩展名把配置文件读成通用结构：对象为 map[string]any，数组为 []any
+func readConfigTree(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(map[string]any)
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".json":
+		err = json.Unmarshal(data, &tree)
+	case ".json5":
+		err = json5.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	case ".ini":
+		tree, err = readINITree(data)
+	case ".properties":
+		var props map[string]string
+		props, err = readProperties(bytes.NewReader(data))
+		tree = nestDottedKeys(props)
+	case ".env":
+		var props map[string]string
+		props, err = readEnvFile(bytes.NewReader(data))
+		tree = nestDottedKeys(props)
+	case ".xml":
+		tree, err = readXMLTree(bytes.NewReader(data))
+	case ".hcl":
+		tree, err = readHCLTree(path, data)
+	default:
+		// RegisterParser 注册的格式需要支持解码到 map
+		supported, ok := supportedExtMap[ext]
+		if !ok {
+			return nil, fmt.Errorf("unsupported config file format:%v", ext)
+		}
+		err = supported.Unmarshaler(bytes.NewReader(data), &tree)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		tree = make(map[string]any)
+	}
+	return tree, nil
+}
+
+// nestDottedKeys 把 db.host=x 形式的扁平键展开为嵌套对象
+func nestDottedKeys(props map[string]string) map[string]any {
+	tree := make(map[string]any)
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		parts := strings.Split(key, ".")
+		node := tree
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = props[key]
+	}
+	return tree
+}
+
+// readINITree 默认分区的键放在顶层，其余分区按名称（. 分隔）嵌套
+func readINITree(data []byte) (map[string]any, error) {
+	cfg, err := ini.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(map[string]any)
+	for _, section := range cfg.Sections() {
+		node := tree
+		if section.Name() != ini.DefaultSection {
+			for _, part := range strings.Split(section.Name(), ".") {
+				child, ok := node[part].(map[string]any)
+				if !ok {
+					child = make(map[string]any)
+					node[part] = child
+				}
+				node = child
+			}
+		}
+		for _, key := range section.Keys() {
+			node[key.Name()] = key.Value()
+		}
+	}
+	return tree, nil
+}
+
+// readXMLTree 根元素的子元素作为顶层键，属性视为子元素，重复的元素合并为数组，叶子元素取文本
+func readXMLTree(reader io.Reader) (map[string]any, error) {
+	dec := xml.NewDecoder(reader)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return map[string]any{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			value, err := readXMLElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			if tree, ok := value.(map[string]any); ok {
+				return tree, nil
+			}
+			return map[string]any{}, nil
+		}
+	}
+}
+
+func readXMLElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	children := make(map[string]any)
+	for _, attr := range start.Attr {
+		children[attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			value, err := readXMLElement(dec, tok)
+			if err != nil {
+				return nil, err
+			}
+			name := tok.Name.Local
+			switch existing := children[name].(type) {
+			case nil:
+				children[name] = value
+			case []any:
+				children[name] = append(existing, value)
+			default:
+				children[name] = []any{existing, value}
+			}
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			if len(children) == 0 {
+				return strings.TrimSpace(text.String()), nil
+			}
+			return children, nil
+		}
+	}
+}
+
+// readHCLTree 属性按值转换，块按类型（及标签）嵌套，同类型的无标签块多于一个时为数组
+func readHCLTree(path string, data []byte) (map[string]any, error) {
+	file, diags := hclsyntax.ParseConfig(data, filepath.Base(path), hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return hclBodyTree(file.Body.(*hclsyntax.Body))
+}
+
+func hclBodyTree(body *hclsyntax.Body) (map[string]any, error) {
+	tree := make(map[string]any)
+	for name, attr := range body.Attributes {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		raw, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		err = json.Unmarshal(raw, &v)
+		if err != nil {
+			return nil, err
+		}
+		tree[name] = v
+	}
+
+	for _, block := range body.Blocks {
+		value, err := hclBodyTree(block.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(block.Labels) == 0 {
+			switch existing := tree[block.Type].(type) {
+			case nil:
+				tree[block.Type] = value
+			case []any:
+				tree[block.Type] = append(existing, value)
+			default:
+				tree[block.Type] = []any{existing, value}
+			}
+			continue
+		}
+
+		node, ok := tree[block.Type].(map[string]any)
+		if !ok {
+			node = make(map[string]any)
+			tree[block.Type] = node
+		}
+		for _, label := range block.Labels[:len(block.Labels)-1] {
+			child, ok := node[label].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[label] = child
+			}
+			node = child
+		}
+		node[block.Labels[len(block.Labels)-1]] = value
+	}
+	return tree, nil
+}
+
+// treeKeyTags 是匹配通用结构键名时参考的 tag
+var treeKeyTags = []string{"json", "yaml", "toml", "ini", "hcl", "xml", "properties", "env"}
+
+// formatTags 是各格式解码时使用的 tag；properties/env 按 getFieldTagName 的优先级
+var formatTags = map[string]string{
+	".json":  "json",
+	".json5": "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".ini":   "ini",
+	".hcl":   "hcl",
+	".xml":   "xml",
+}
+
+// normalizeTree 让合并结果能被根文件格式的 Unmarshaler 解码：按 t 的字段把键名改写为该格式使用的名称
+// （各片段的键名与字段的任一 tag 或字段名忽略大小写匹配），字符串按字段类型转换
+// （ini/properties/env/xml 的值都是字符串），其余值保持原样
+func normalizeTree(src any, t reflect.Type, ext string) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch src := src.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(src))
+		switch {
+		case t.Kind() == reflect.Struct && t != timeType:
+			for k, v := range src {
+				out[k] = v
+			}
+			normalizeStruct(out, t, ext)
+		case t.Kind() == reflect.Map:
+			for k, v := range src {
+				out[k] = normalizeTree(v, t.Elem(), ext)
+			}
+		default:
+			return src
+		}
+		return out
+
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return src
+		}
+		out := make([]any, len(src))
+		for i, item := range src {
+			out[i] = normalizeTree(item, t.Elem(), ext)
+		}
+		return out
+
+	case string:
+		if !convertibleLeaf(t) {
+			return src
+		}
+		value := reflect.New(t).Elem()
+		if setLeafValue(value, src) != nil {
+			// 交给 Unmarshaler 报告类型错误
+			return src
+		}
+		return value.Interface()
+
+	case nil:
+		return nil
+
+	default:
+		if t.Kind() == reflect.String {
+			return fmt.Sprint(src)
+		}
+		return src
+	}
+}
+
+func normalizeStruct(m map[string]any, t reflect.Type, ext string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, ok := matchTreeKey(field, m)
+		if !ok {
+			// 匿名嵌入的结构体展开到当前层
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if field.Anonymous && ft.Kind() == reflect.Struct {
+				normalizeStruct(m, ft, ext)
+			}
+			continue
+		}
+
+		value := normalizeTree(m[key], field.Type, ext)
+		delete(m, key)
+		if name := formatKey(field, ext); name != "" {
+			key = name
+		}
+		m[key] = value
+	}
+}
+
+// convertibleLeaf 判断字符串能否由 setLeafValue 转换为 t
+func convertibleLeaf(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return t == timeType
+}
+
+// formatKey 返回字段在 ext 格式中的键名，返回空字符串表示沿用原键名
+func formatKey(field reflect.StructField, ext string) string {
+	tag, ok := formatTags[ext]
+	if !ok {
+		if ext == ".properties" || ext == ".env" {
+			return getFieldTagName(field)
+		}
+		return ""
+	}
+
+	if name, ok := field.Tag.Lookup(tag); ok {
+		if commaIndex := strings.Index(name, ","); commaIndex != -1 {
+			name = name[:commaIndex]
+		}
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	switch tag {
+	case "yaml":
+		return strings.ToLower(field.Name)
+	case "hcl":
+		// gohcl 只解码带 hcl tag 的字段
+		return ""
+	}
+	return field.Name
+}
+
+// matchTreeKey 查找字段对应的键，tag 名优先于字段名
+func matchTreeKey(field reflect.StructField, m map[string]any) (string, bool) {
+	var names []string
+	for _, tag := range treeKeyTags {
+		tagValue, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		if commaIndex := strings.Index(tagValue, ","); commaIndex != -1 {
+			tagValue = tagValue[:commaIndex]
+		}
+		if tagValue == "-" {
+			return "", false
+		}
+		if tagValue != "" {
+			names = append(names, tagValue)
+		}
+	}
+	names = append(names, field.Name)
+
+	for _, name := range names {
+		if _, ok := m[name]; ok {
+			return name, true
+		}
+	}
+	for _, name := range names {
+		for key := range m {
+			if strings.EqualFold(key, name) {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// encodeTree 把合并结果编码为 ext 格式，t 是解码目标的类型（xml 用于确定根元素名）
+func encodeTree(ext string, tree any, t reflect.Type) ([]byte, error) {
+	switch ext {
+	case ".json", ".json5", ".hcl":
+		return json.Marshal(tree)
+	case ".yaml", ".yml":
+		return yaml.Marshal(tree)
+	case ".toml":
+		return toml.Marshal(tree)
+	case ".ini":
+		return encodeINITree(tree.(map[string]any))
+	case ".properties", ".env":
+		return encodeFlatTree(ext, tree.(map[string]any)), nil
+	case ".xml":
+		return encodeXMLTree(tree.(map[string]any), t)
+	}
+
+	var buf bytes.Buffer
+	err := supportedExtMap[ext].Marshaler(&buf, tree)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// treeText 把叶子值写成文本格式中的字符串，列表以逗号分隔
+func treeText(value any, ext string) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case time.Duration:
+		// ini 按 time.ParseDuration 解析，其余格式按整数解析
+		if ext == ".ini" {
+			return value.String()
+		}
+		return strconv.FormatInt(int64(value), 10)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case nil:
+		return ""
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = treeText(rv.Index(i).Interface(), ext)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// encodeINITree 顶层的值写入默认分区，对象写为分区，嵌套对象的分区名以 . 连接
+func encodeINITree(tree map[string]any) ([]byte, error) {
+	cfg := ini.Empty()
+	err := addINISection(cfg, "", tree)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	_, err = cfg.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func addINISection(cfg *ini.File, name string, node map[string]any) error {
+	section := cfg.Section(name)
+	for _, k := range sortedKeys(node) {
+		if child, ok := node[k].(map[string]any); ok {
+			sub := k
+			if name != "" {
+				sub = name + "." + k
+			}
+			err := addINISection(cfg, sub, child)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := section.NewKey(k, treeText(node[k], ".ini"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeFlatTree 把对象展开为 a.b=value 形式的 properties/env 内容
+func encodeFlatTree(ext string, tree map[string]any) []byte {
+	flat := make(map[string]any)
+	flattenTree(flat, "", tree)
+
+	var buf bytes.Buffer
+	for _, key := range sortedKeys(flat) {
+		value := treeText(flat[key], ext)
+		if ext == ".properties" {
+			value = strings.ReplaceAll(value, "\n", "\\n")
+			value = strings.ReplaceAll(value, "\t", "\\t")
+			value = strings.ReplaceAll(value, "\r", "\\r")
+		} else if strings.ContainsAny(value, " \t#'\"") {
+			value = `"` + value + `"`
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", key, value)
+	}
+	return buf.Bytes()
+}
+
+func flattenTree(flat map[string]any, prefix string, node map[string]any) {
+	for k, v := range node {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		if child, ok := v.(map[string]any); ok {
+			flattenTree(flat, k, child)
+			continue
+		}
+		flat[k] = v
+	}
+}
+
+// encodeXMLTree 对象写为子元素，列表写为重复的元素；根元素名取 t 的 XMLName tag，默认为 config
+func encodeXMLTree(tree map[string]any, t reflect.Type) ([]byte, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	root := "config"
+	if field, ok := t.FieldByName("XMLName"); ok {
+		if name, _, _ := strings.Cut(field.Tag.Get("xml"), ","); name != "" {
+			root = name
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	err := encodeXMLElement(enc, root, tree)
+	if err != nil {
+		return nil, err
+	}
+	err = enc.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(enc *xml.Encoder, name string, value any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch value := value.(type) {
+	case nil:
+		return nil
+
+	case map[string]any:
+		err := enc.EncodeToken(start)
+		if err != nil {
+			return err
+		}
+		for _, k := range sortedKeys(value) {
+			err = encodeXMLElement(enc, k, value[k])
+			if err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+
+	case string:
+		return enc.EncodeElement(value, start)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			err := encodeXMLElement(enc, name, rv.Index(i).Interface())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return enc.EncodeElement(treeText(value, ".xml"), start)
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type includeTestConfig struct {
+	Name    string        `yaml:"name" toml:"name"`
+	Debug   bool          `yaml:"debug" toml:"debug"`
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+	Tags    []string      `yaml:"tags" toml:"tags"`
+	DB      struct {
+		Host string `yaml:"host" toml:"host"`
+		Port int    `yaml:"port" toml:"port"`
+	} `yaml:"db" toml:"db"`
+	Cache struct {
+		Size int               `yaml:"size"`
+		TTL  time.Duration     `yaml:"ttl"`
+		Tags map[string]string `yaml:"tags"`
+	} `yaml:"cache"`
+	Log struct {
+		Level string `yaml:"level"`
+		Path  string `yaml:"path"`
+	} `yaml:"log"`
+}
+
+func writeIncludeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLoadConfigWithIncludes(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"config.yaml": "include:\n" +
+			"  - base.toml\n" +
+			"  - conf.d/*.json\n" +
+			"name: root\n" +
+			"tags: [root]\n" +
+			"db:\n" +
+			"  $import: fragments/db.ini\n" +
+			"  port: 6543\n" +
+			"cache:\n" +
+			"  $import: fragments/cache.hcl\n",
+		"base.toml": "name = \"base\"\n" +
+			"debug = true\n" +
+			"timeout = \"3s\"\n" +
+			"tags = [\"base\"]\n" +
+			"\n" +
+			"[db]\n" +
+			"host = \"base-host\"\n" +
+			"port = 5432\n",
+		"conf.d/10-log.json":    `{"log": {"level": "info", "path": "/var/log/app.log"}}`,
+		"conf.d/20-log.json":    `{"log": {"level": "warn"}}`,
+		"fragments/db.ini":      "host = ini-host\nport = 1234\n",
+		"fragments/cache.hcl":   "size = 128\nttl = \"1m\"\ntags {\n  env = \"prod\"\n}\n",
+		"fragments/unused.json": `{}`,
+	})
+	withConfigPath(t, "")
+
+	var c includeTestConfig
+	require.NoError(t, LoadConfigWithIncludes(&c, filepath.Join(dir, "config.yaml")))
+
+	assert.Equal(t, "root", c.Name)
+	assert.True(t, c.Debug)
+	assert.Equal(t, 3*time.Second, c.Timeout)
+	assert.Equal(t, []string{"root"}, c.Tags)
+	// $import 的文件覆盖上层 include 的同名对象，自身的键优先级最高
+	assert.Equal(t, "ini-host", c.DB.Host)
+	assert.Equal(t, 6543, c.DB.Port)
+	assert.Equal(t, 128, c.Cache.Size)
+	assert.Equal(t, time.Minute, c.Cache.TTL)
+	assert.Equal(t, map[string]string{"env": "prod"}, c.Cache.Tags)
+	// glob 按文件名排序，后者覆盖前者
+	assert.Equal(t, "warn", c.Log.Level)
+	assert.Equal(t, "/var/log/app.log", c.Log.Path)
+	assert.Equal(t, filepath.Join(dir, "config.yaml"), configPath)
+}
+
+func TestResolveIncludesSliceMerge(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"config.json": `{"include": "a.yaml, b.properties", "tags": ["root"]}`,
+		"a.yaml":      "tags: [a]\ndb:\n  host: a\n",
+		"b.properties": "db.port=1\n" +
+			"db.host=b\n",
+	})
+	path := filepath.Join(dir, "config.json")
+
+	tree, err := ResolveIncludes(path)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"root"}, tree["tags"])
+	assert.Equal(t, map[string]any{"host": "b", "port": "1"}, tree["db"])
+	assert.NotContains(t, tree, "include")
+
+	tree, err = ResolveIncludes(path, WithSliceMerge(SliceAppend))
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a", "root"}, tree["tags"])
+}
+
+func TestResolveIncludesFormats(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"config.yaml": "include: [a.env, b.xml, c.json5]\n",
+		"a.env":       "NAME=\"from-env\"\n",
+		"b.xml":       "<config><db host=\"xml-host\"><port>1</port></db><tags>a</tags><tags>b</tags></config>",
+		"c.json5":     "{debug: true}",
+	})
+
+	var c includeTestConfig
+	require.NoError(t, LoadConfigWithIncludesSkipValidate(&c, filepath.Join(dir, "config.yaml")))
+	assert.Equal(t, "from-env", c.Name)
+	assert.Equal(t, "xml-host", c.DB.Host)
+	assert.Equal(t, 1, c.DB.Port)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+	assert.True(t, c.Debug)
+}
+
+func TestResolveIncludesErrors(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		dir := writeIncludeFiles(t, map[string]string{
+			"a.yaml":     "include: sub/b.toml\n",
+			"sub/b.toml": "include = \"../c.json\"\n",
+			"c.json":     `{"db": {"$import": "a.yaml"}}`,
+		})
+		_, err := ResolveIncludes(filepath.Join(dir, "a.yaml"))
+		assert.ErrorContains(t, err, "include cycle: a.yaml -> sub/b.toml -> c.json -> a.yaml")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		dir := writeIncludeFiles(t, map[string]string{
+			"a.yaml": "include: missing.yaml\n",
+		})
+		_, err := ResolveIncludes(filepath.Join(dir, "a.yaml"))
+		assert.ErrorContains(t, err, "missing.yaml")
+	})
+
+	t.Run("bad directive", func(t *testing.T) {
+		dir := writeIncludeFiles(t, map[string]string{
+			"a.yaml": "include: 1\n",
+		})
+		_, err := ResolveIncludes(filepath.Join(dir, "a.yaml"))
+		assert.Error(t, err)
+	})
+
+	t.Run("decode error path", func(t *testing.T) {
+		dir := writeIncludeFiles(t, map[string]string{
+			"a.yaml": "db:\n  port: abc\n",
+		})
+		var c includeTestConfig
+		err := LoadConfigWithIncludesSkipValidate(&c, filepath.Join(dir, "a.yaml"))
+		assert.ErrorContains(t, err, "abc")
+	})
+}
+
+func TestLoadConfigResolvesIncludes(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"config.json": `{"$include": "base.yaml", "port": 8080}`,
+		"base.yaml":   "name: base\nport: 80\n",
+		"plain.yaml":  "include: [\"*.go\"]\nname: plain\n",
+		"cycle.yaml":  "$import: cycle.yaml\n",
+	})
+	withConfigPath(t, "")
+
+	// 合并结果由根文件格式的 Unmarshaler 解码，按 json tag 匹配
+	var c TestConfig
+	require.NoError(t, LoadConfig(&c, filepath.Join(dir, "config.json")))
+	assert.Equal(t, "base", c.Name)
+	assert.Equal(t, 8080, c.Port)
+
+	// LoadConfig 只识别 $include / $import，include 是普通字段
+	type plainConfig struct {
+		Name    string   `yaml:"name"`
+		Include []string `yaml:"include"`
+	}
+	configPath = ""
+	var p plainConfig
+	require.NoError(t, LoadConfig(&p, filepath.Join(dir, "plain.yaml")))
+	assert.Equal(t, []string{"*.go"}, p.Include)
+	assert.Equal(t, "plain", p.Name)
+
+	// 引入错误直接返回
+	configPath = ""
+	err := LoadConfigSkipValidate(&TestConfig{}, filepath.Join(dir, "cycle.yaml"))
+	assert.ErrorContains(t, err, "include cycle: cycle.yaml -> cycle.yaml")
+}
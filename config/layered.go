@@ -0,0 +1,441 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lazygophers/log"
+	"github.com/lazygophers/utils/app"
+	"github.com/lazygophers/utils/defaults"
+	"github.com/lazygophers/utils/osx"
+	"github.com/lazygophers/utils/runtime"
+	"github.com/lazygophers/utils/validator"
+)
+
+// Layer 标识配置值的来源层，按优先级从低到高排列
+type Layer string
+
+const (
+	// LayerDefault 来自 `default` tag（defaults.SetDefaults）
+	LayerDefault Layer = "default"
+	// LayerFile 来自基础配置文件
+	LayerFile Layer = "file"
+	// LayerEnvFile 来自按 app.Env 选择的环境配置文件，如 config.prod.yaml
+	LayerEnvFile Layer = "env-file"
+	// LayerEnv 来自环境变量
+	LayerEnv Layer = "env"
+	// LayerFlag 来自命令行参数
+	LayerFlag Layer = "flag"
+)
+
+// FieldSource 描述最终字段值的来源
+type FieldSource struct {
+	Layer Layer
+	// Origin 为文件路径、环境变量名或 flag 名，默认值层为空
+	Origin string
+}
+
+// Sources 记录每个字段最终值的来源，key 为 Go 字段路径（与 FieldChange.Path 一致）。
+// 没有任何层修改过的字段不在其中
+type Sources map[string]FieldSource
+
+// String 按字段路径排序输出，便于调试
+func (s Sources) String() string {
+	paths := make([]string, 0, len(s))
+	for path := range s {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, path := range paths {
+		source := s[path]
+		b.WriteString(path)
+		b.WriteString(" <- ")
+		b.WriteString(string(source.Layer))
+		if source.Origin != "" {
+			b.WriteString(" (")
+			b.WriteString(source.Origin)
+			b.WriteString(")")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// LayeredOption 配置分层加载
+type LayeredOption func(*layeredOptions)
+
+type layeredOptions struct {
+	paths     []string
+	envFile   string
+	envPrefix string
+	flags     *flag.FlagSet
+}
+
+// WithFiles 指定基础配置文件候选路径，取第一个存在的文件；
+// 未指定或都不存在时与 LoadConfig 相同，依次从 LAZYGOPHERS_CONFIG、当前目录、程序目录查找
+func WithFiles(paths ...string) LayeredOption {
+	return func(o *layeredOptions) {
+		o.paths = append(o.paths, paths...)
+	}
+}
+
+// WithEnvFile 指定环境配置文件，默认按 app.Env 在基础配置文件旁查找 <name>.<env>.<ext>
+func WithEnvFile(path string) LayeredOption {
+	return func(o *layeredOptions) {
+		o.envFile = path
+	}
+}
+
+// WithEnvPrefix 按前缀把环境变量映射到字段：prefix 为 APP 时，db.host 对应 APP_DB_HOST。
+// 字段的 `env` tag 始终生效，且优先于前缀映射
+func WithEnvPrefix(prefix string) LayeredOption {
+	return func(o *layeredOptions) {
+		o.envPrefix = strings.TrimSuffix(prefix, "_")
+	}
+}
+
+// WithFlags 使用已解析的 FlagSet 覆盖配置，只有显式设置过的 flag 生效。
+// flag 名取字段的 `flag` tag，没有时为配置键路径，如 db.host
+func WithFlags(fs *flag.FlagSet) LayeredOption {
+	return func(o *layeredOptions) {
+		o.flags = fs
+	}
+}
+
+// LoadLayered 按 默认值 < 基础文件 < 环境文件 < 环境变量 < 命令行参数 的顺序分层加载并校验，
+// 返回每个字段最终值的来源
+func LoadLayered(c any, opts ...LayeredOption) (Sources, error) {
+	sources, err := LoadLayeredSkipValidate(c, opts...)
+	if err != nil {
+		return sources, err
+	}
+
+	err = validator.Struct(c)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return sources, err
+	}
+
+	return sources, nil
+}
+
+// LoadLayeredSkipValidate 与 LoadLayered 相同但跳过校验
+func LoadLayeredSkipValidate(c any, opts ...LayeredOption) (Sources, error) {
+	rv := reflect.ValueOf(c)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("v must be a pointer to struct")
+	}
+
+	var options layeredOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	sources := make(Sources)
+
+	// NOTE: 默认值
+	err := trackLayer(c, sources, FieldSource{Layer: LayerDefault}, func() error {
+		return defaults.SetDefaultsWithOptions(c, &defaults.Options{ErrorMode: defaults.ErrorModeReturn})
+	})
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return sources, err
+	}
+
+	// NOTE: 基础文件
+	base := findConfigFile(options.paths)
+	if base == "" {
+		log.Warnf("Config file not found, use default config")
+	} else {
+		configPath = base
+		err = trackLayer(c, sources, FieldSource{Layer: LayerFile, Origin: base}, func() error {
+			return decodeFile(c, base)
+		})
+		if err != nil {
+			log.Errorf("err:%v", err)
+			return sources, err
+		}
+	}
+
+	// NOTE: 环境文件
+	envFile := options.envFile
+	if envFile == "" && base != "" {
+		envFile = findEnvFile(base)
+	}
+	if envFile != "" && osx.IsFile(envFile) {
+		err = trackLayer(c, sources, FieldSource{Layer: LayerEnvFile, Origin: envFile}, func() error {
+			return decodeFile(c, envFile)
+		})
+		if err != nil {
+			log.Errorf("err:%v", err)
+			return sources, err
+		}
+	}
+
+	// NOTE: 环境变量
+	err = applyEnvLayer(rv.Elem(), options.envPrefix, sources)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return sources, err
+	}
+
+	// NOTE: 命令行参数
+	if options.flags != nil {
+		err = applyFlagLayer(rv.Elem(), options.flags, sources)
+		if err != nil {
+			log.Errorf("err:%v", err)
+			return sources, err
+		}
+	}
+
+	// NOTE: 解析密钥引用与加密值，来源仍记为提供引用的那一层
+	err = resolveSecrets(c)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return sources, err
+	}
+
+	log.Info("load layered config success")
+	return sources, nil
+}
+
+// trackLayer 执行 apply 并把发生变化的字段记到 source 名下
+func trackLayer(c any, sources Sources, source FieldSource, apply func() error) error {
+	// 深拷贝，避免解码时原地修改 map/指针导致对比不到变化
+	snapshot := deepCopyValue(reflect.ValueOf(c).Elem())
+
+	err := apply()
+	if err != nil {
+		return err
+	}
+
+	for _, change := range Diff(snapshot.Interface(), c) {
+		sources[change.Path] = source
+	}
+	return nil
+}
+
+// findConfigFile 与 LoadConfigSkipValidate 的查找顺序一致，但不读写包级 configPath
+func findConfigFile(paths []string) string {
+	for _, path := range paths {
+		if osx.IsFile(path) {
+			return path
+		}
+	}
+
+	if path := os.Getenv("LAZYGOPHERS_CONFIG"); path != "" && osx.IsFile(path) {
+		return path
+	}
+
+	if path := tryFindConfigPath(runtime.Pwd()); path != "" {
+		return path
+	}
+
+	return tryFindConfigPath(runtime.ExecDir())
+}
+
+// envFileNames 返回 app.Env 对应的环境文件名后缀
+func envFileNames() []string {
+	switch app.Env {
+	case app.Release:
+		return []string{"prod", "release"}
+	case app.Debug:
+		return []string{"dev", "debug"}
+	default:
+		return []string{app.Env.String()}
+	}
+}
+
+// findEnvFile 在基础文件旁查找 <name>.<env>.<ext>，同扩展名优先
+func findEnvFile(base string) string {
+	dir := filepath.Dir(base)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(filepath.Base(base), ext)
+
+	for _, env := range envFileNames() {
+		path := filepath.Join(dir, stem+"."+env+ext)
+		if osx.IsFile(path) {
+			return path
+		}
+	}
+
+	// 允许环境文件使用与基础文件不同的格式
+	exts := make([]string, 0, len(supportedExtMap))
+	for e := range supportedExtMap {
+		exts = append(exts, e)
+	}
+	sort.Strings(exts)
+
+	for _, env := range envFileNames() {
+		for _, e := range exts {
+			path := filepath.Join(dir, stem+"."+env+e)
+			if osx.IsFile(path) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// decodeFile 把文件解码到 c 上，文件中未出现的字段保持原值；与 LoadConfig 一致展开 $include / $import 指令
+func decodeFile(c any, path string) error {
+	log.Infof("Config file found, use config from %s", path)
+	_, err := loadConfigFile(c, path, withIncludeKeys(importKeys))
+	return err
+}
+
+// leafField 是配置结构体中的一个叶子字段
+type leafField struct {
+	path  string   // Go 字段路径，如 DB.Host
+	keys  []string // 配置键路径，如 db, host
+	field reflect.StructField
+	value reflect.Value
+}
+
+// walkLeaves 遍历结构体的叶子字段，嵌套结构体递归展开，time.Time 视为叶子
+func walkLeaves(v reflect.Value, path string, keys []string, fn func(leaf leafField) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+		if !field.IsExported() || !value.CanSet() {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, field.Name)
+		fieldKeys := append(append([]string(nil), keys...), configKey(field))
+
+		if value.Kind() == reflect.Struct && value.Type() != reflect.TypeOf(time.Time{}) {
+			err := walkLeaves(value, fieldPath, fieldKeys, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := fn(leafField{path: fieldPath, keys: fieldKeys, field: field, value: value})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configKey 返回字段在配置文件中的键名
+func configKey(field reflect.StructField) string {
+	for _, tag := range []string{"json", "yaml", "toml", "ini"} {
+		if tagValue := field.Tag.Get(tag); tagValue != "" && tagValue != "-" {
+			if commaIndex := strings.Index(tagValue, ","); commaIndex != -1 {
+				tagValue = tagValue[:commaIndex]
+			}
+			if tagValue != "" {
+				return tagValue
+			}
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+// envName 把配置键路径转换为带前缀的环境变量名
+func envName(prefix string, keys []string) string {
+	name := strings.ToUpper(strings.Join(keys, "_"))
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	return prefix + "_" + name
+}
+
+func applyEnvLayer(v reflect.Value, prefix string, sources Sources) error {
+	return walkLeaves(v, "", nil, func(leaf leafField) error {
+		var names []string
+		if prefix != "" {
+			names = append(names, envName(prefix, leaf.keys))
+		}
+		// `env` tag 优先于前缀映射，后应用
+		if name := leaf.field.Tag.Get("env"); name != "" && name != "-" {
+			if commaIndex := strings.Index(name, ","); commaIndex != -1 {
+				name = name[:commaIndex]
+			}
+			names = append(names, name)
+		}
+
+		for _, name := range names {
+			value, ok := os.LookupEnv(name)
+			if !ok || value == "" {
+				continue
+			}
+			err := setLeafValue(leaf.value, value)
+			if err != nil {
+				return fmt.Errorf("env %s: %w", name, err)
+			}
+			sources[leaf.path] = FieldSource{Layer: LayerEnv, Origin: name}
+		}
+		return nil
+	})
+}
+
+func applyFlagLayer(v reflect.Value, fs *flag.FlagSet, sources Sources) error {
+	set := make(map[string]*flag.Flag)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = f
+	})
+	if len(set) == 0 {
+		return nil
+	}
+
+	return walkLeaves(v, "", nil, func(leaf leafField) error {
+		name := leaf.field.Tag.Get("flag")
+		if name == "" {
+			name = strings.Join(leaf.keys, ".")
+		}
+
+		f, ok := set[name]
+		if !ok {
+			return nil
+		}
+		err := setLeafValue(leaf.value, f.Value.String())
+		if err != nil {
+			return fmt.Errorf("flag -%s: %w", name, err)
+		}
+		sources[leaf.path] = FieldSource{Layer: LayerFlag, Origin: name}
+		return nil
+	})
+}
+
+// setLeafValue 在 setFieldValue 的基础上支持 time.Duration、time.Time 与逗号分隔的字符串切片
+func setLeafValue(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+
+	case value.Type() == reflect.TypeOf(time.Time{}):
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(t))
+		return nil
+
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			slice.Index(i).SetString(strings.TrimSpace(part))
+		}
+		value.Set(slice)
+		return nil
+	}
+
+	return setFieldValue(value, raw)
+}
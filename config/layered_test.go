@@ -0,0 +1,159 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type layeredTestConfig struct {
+	Name    string        `yaml:"name" default:"demo"`
+	Port    int           `yaml:"port" default:"8080" validate:"min=1,max=65535"`
+	Debug   bool          `yaml:"debug"`
+	Timeout time.Duration `yaml:"timeout"`
+	Token   string        `yaml:"token" env:"LAYERED_TEST_TOKEN"`
+	DB      struct {
+		Host string `yaml:"host" default:"localhost"`
+		User string `yaml:"user" flag:"db-user"`
+	} `yaml:"db"`
+}
+
+func withReleaseEnv(t *testing.T, env app.ReleaseType) {
+	t.Helper()
+	old := app.Env
+	app.Env = env
+	t.Cleanup(func() {
+		app.Env = old
+	})
+}
+
+func TestLoadLayered(t *testing.T) {
+	withReleaseEnv(t, app.Release)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("name: base\nport: 9000\ndb:\n  host: db.local\n"), 0644))
+	prod := filepath.Join(dir, "config.prod.yaml")
+	require.NoError(t, os.WriteFile(prod, []byte("port: 9443\n"), 0644))
+
+	t.Setenv("LAYERED_DEBUG", "true")
+	t.Setenv("LAYERED_DB_HOST", "db.env")
+	t.Setenv("LAYERED_TEST_TOKEN", "secret")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db-user", "", "")
+	fs.String("db.host", "", "")
+	fs.Duration("timeout", 0, "")
+	require.NoError(t, fs.Parse([]string{"-db-user=admin", "-timeout=30s"}))
+
+	var c layeredTestConfig
+	sources, err := LoadLayered(&c,
+		WithFiles(base),
+		WithEnvPrefix("LAYERED"),
+		WithFlags(fs),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "base", c.Name)
+	assert.Equal(t, 9443, c.Port)
+	assert.True(t, c.Debug)
+	assert.Equal(t, 30*time.Second, c.Timeout)
+	assert.Equal(t, "secret", c.Token)
+	assert.Equal(t, "db.env", c.DB.Host)
+	assert.Equal(t, "admin", c.DB.User)
+
+	assert.Equal(t, FieldSource{Layer: LayerFile, Origin: base}, sources["Name"])
+	assert.Equal(t, FieldSource{Layer: LayerEnvFile, Origin: prod}, sources["Port"])
+	assert.Equal(t, FieldSource{Layer: LayerEnv, Origin: "LAYERED_DEBUG"}, sources["Debug"])
+	assert.Equal(t, FieldSource{Layer: LayerEnv, Origin: "LAYERED_TEST_TOKEN"}, sources["Token"])
+	assert.Equal(t, FieldSource{Layer: LayerEnv, Origin: "LAYERED_DB_HOST"}, sources["DB.Host"])
+	assert.Equal(t, FieldSource{Layer: LayerFlag, Origin: "db-user"}, sources["DB.User"])
+	assert.Equal(t, FieldSource{Layer: LayerFlag, Origin: "timeout"}, sources["Timeout"])
+	assert.Contains(t, sources.String(), "Port <- env-file ("+prod+")")
+}
+
+func TestLoadLayeredIncludes(t *testing.T) {
+	withReleaseEnv(t, app.Release)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("$include: common.toml\nname: base\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.toml"), []byte("name = \"common\"\nport = 9000\n"), 0644))
+	prod := filepath.Join(dir, "config.prod.yaml")
+	require.NoError(t, os.WriteFile(prod, []byte("db:\n  $import: db.json\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db.json"), []byte(`{"host": "db.prod"}`), 0644))
+
+	var c layeredTestConfig
+	sources, err := LoadLayered(&c, WithFiles(base))
+	require.NoError(t, err)
+
+	assert.Equal(t, "base", c.Name)
+	assert.Equal(t, 9000, c.Port)
+	assert.Equal(t, "db.prod", c.DB.Host)
+	assert.Equal(t, FieldSource{Layer: LayerFile, Origin: base}, sources["Port"])
+	assert.Equal(t, FieldSource{Layer: LayerEnvFile, Origin: prod}, sources["DB.Host"])
+
+	// 引入错误直接返回
+	require.NoError(t, os.WriteFile(prod, []byte("$import: missing.yaml\n"), 0644))
+	_, err = LoadLayered(&layeredTestConfig{}, WithFiles(base))
+	assert.ErrorContains(t, err, "missing.yaml")
+}
+
+func TestLoadLayeredDefaults(t *testing.T) {
+	withReleaseEnv(t, app.Debug)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("name: base\n"), 0644))
+	// 非 Release 环境不应读取 prod 文件
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.yaml"), []byte("port: 9443\n"), 0644))
+	dev := filepath.Join(dir, "config.dev.json")
+	require.NoError(t, os.WriteFile(dev, []byte(`{"debug": true}`), 0644))
+
+	var c layeredTestConfig
+	sources, err := LoadLayered(&c, WithFiles(base))
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, c.Port)
+	assert.True(t, c.Debug)
+	assert.Equal(t, FieldSource{Layer: LayerDefault}, sources["Port"])
+	assert.Equal(t, FieldSource{Layer: LayerDefault}, sources["DB.Host"])
+	assert.Equal(t, FieldSource{Layer: LayerEnvFile, Origin: dev}, sources["Debug"])
+	_, ok := sources["DB.User"]
+	assert.False(t, ok, "untouched fields have no source")
+}
+
+func TestLoadLayeredErrors(t *testing.T) {
+	withReleaseEnv(t, app.Test)
+
+	t.Run("not pointer", func(t *testing.T) {
+		_, err := LoadLayered(layeredTestConfig{})
+		assert.Error(t, err)
+	})
+
+	t.Run("bad env value", func(t *testing.T) {
+		t.Setenv("LAYERED_PORT", "abc")
+		var c layeredTestConfig
+		_, err := LoadLayeredSkipValidate(&c, WithFiles(filepath.Join(t.TempDir(), "missing.yaml")), WithEnvPrefix("LAYERED_"))
+		assert.ErrorContains(t, err, "LAYERED_PORT")
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(base, []byte("port: 70000\n"), 0644))
+
+		var c layeredTestConfig
+		_, err := LoadLayered(&c, WithFiles(base))
+		assert.Error(t, err)
+
+		_, err = LoadLayeredSkipValidate(&c, WithFiles(base))
+		assert.NoError(t, err)
+	})
+}
@@ -3,6 +3,7 @@
 import (
 	"bufio"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/hcl/v2/hclsimple"
 	"github.com/lazygophers/log"
@@ -217,7 +218,7 @@ func LoadConfigSkipValidate(c any, paths ...string) error {
 		configPath = tryFindConfigPath(runtime.ExecDir())
 	}
 
-	file, err := os.Open(configPath)
+	_, err := os.Stat(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			log.Warnf("Config file not found, use default config")
@@ -227,14 +228,18 @@ func LoadConfigSkipValidate(c any, paths ...string) error {
 			log.Errorf("err:%v", err)
 		}
 	} else {
-		defer file.Close()
 		log.Infof("Config file found, use config from %s", configPath)
 
 		ext := filepath.Ext(configPath)
-		if supported, ok := supportedExtMap[ext]; ok {
-			err = supported.Unmarshaler(file, c)
+		if _, ok := supportedExtMap[ext]; ok {
+			// 文件中的 $include / $import 指令在这里展开
+			_, err = loadConfigFile(c, configPath, withIncludeKeys(importKeys))
 			if err != nil {
 				log.Errorf("err:%v", err)
+				var includeErr *includeError
+				if errors.As(err, &includeErr) {
+					return err
+				}
 			}
 		} else {
 			log.Errorf("unsupported config file format:%v", ext)
@@ -248,6 +253,13 @@ func LoadConfigSkipValidate(c any, paths ...string) error {
 		log.Errorf("err:%v", err)
 	}
 
+	// NOTE: 解析 ${env:...}、${file:...} 引用与 enc:v1: 加密值
+	err = resolveSecrets(c)
+	if err != nil {
+		log.Errorf("err:%v", err)
+		return err
+	}
+
 	log.Info("load config success")
 
 	return nil
@@ -263,7 +275,8 @@ func SetConfig(c any) error {
 		}
 		defer file.Close()
 
-		err = supported.Marshaler(file, c)
+		// 已解析的密钥写回为原始引用/密文，不落盘明文
+		err = supported.Marshaler(file, unresolveSecrets(c))
 		if err != nil {
 			log.Errorf("err:%v", err)
 			return nil
@@ -278,6 +291,16 @@ func SetConfig(c any) error {
 
 // parseProperties 解析 .properties 文件格式
 func parseProperties(reader io.Reader, v interface{}) error {
+	props, err := readProperties(reader)
+	if err != nil {
+		return err
+	}
+
+	return mapToStruct(props, v)
+}
+
+// readProperties 读取 .properties 文件中的键值对
+func readProperties(reader io.Reader) (map[string]string, error) {
 	props := make(map[string]string)
 	scanner := bufio.NewScanner(reader)
 
@@ -313,10 +336,10 @@ func parseProperties(reader io.Reader, v interface{}) error {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return err
+		return nil, err
 	}
 
-	return mapToStruct(props, v)
+	return props, nil
 }
 
 // writeProperties 写入 .properties 文件格式
@@ -344,6 +367,16 @@ func writeProperties(writer io.Writer, v interface{}) error {
 
 // parseEnvFile 解析 .env 文件格式
 func parseEnvFile(reader io.Reader, v interface{}) error {
+	props, err := readEnvFile(reader)
+	if err != nil {
+		return err
+	}
+
+	return mapToStruct(props, v)
+}
+
+// readEnvFile 读取 .env 文件中的键值对
+func readEnvFile(reader io.Reader) (map[string]string, error) {
 	props := make(map[string]string)
 	scanner := bufio.NewScanner(reader)
 
@@ -374,10 +407,10 @@ func parseEnvFile(reader io.Reader, v interface{}) error {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return err
+		return nil, err
 	}
 
-	return mapToStruct(props, v)
+	return props, nil
 }
 
 // writeEnvFile 写入 .env 文件格式
@@ -876,6 +909,13 @@ func LoadConfigWithInheritance(c any, paths ...string) (err error) {
 		return err
 	}
 
+	// 解析密钥引用与加密值
+	err = resolveSecrets(c)
+	if err != nil {
+		log.Errorf("failed to resolve secrets: %v", err)
+		return err
+	}
+
 	// 验证最终配置
 	err = validator.Struct(c)
 	if err != nil {
@@ -910,6 +950,13 @@ func LoadConfigWithInheritanceSkipValidate(c any, paths ...string) error {
 		return err
 	}
 
+	// 解析密钥引用与加密值
+	err = resolveSecrets(c)
+	if err != nil {
+		log.Errorf("failed to resolve secrets: %v", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lazygophers/utils/defaults"
+	"github.com/lazygophers/utils/validator"
+)
+
+// SchemaDraft 是生成的 JSON Schema 使用的草案版本
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema 是 JSON Schema (draft 2020-12)，与 validator 导出 OpenAPI 3.1 Schema 使用同一类型
+type JSONSchema = validator.Schema
+
+// SchemaType 是 JSON Schema 的 type，只有一个类型时序列化为字符串
+type SchemaType = validator.SchemaType
+
+// SchemaOption 配置 JSON Schema 生成
+type SchemaOption func(*schemaOptions)
+
+type schemaOptions struct {
+	id     string
+	title  string
+	tag    string
+	strict bool
+}
+
+// WithSchemaID 设置 $id
+func WithSchemaID(id string) SchemaOption {
+	return func(o *schemaOptions) {
+		o.id = id
+	}
+}
+
+// WithSchemaTitle 设置 title
+func WithSchemaTitle(title string) SchemaOption {
+	return func(o *schemaOptions) {
+		o.title = title
+	}
+}
+
+// WithSchemaTag 指定属性名取自哪个 tag（如 yaml、toml），缺省与分层加载一致：json → yaml → toml → ini → 字段名小写
+func WithSchemaTag(tag string) SchemaOption {
+	return func(o *schemaOptions) {
+		o.tag = tag
+	}
+}
+
+// WithSchemaStrict 禁止结构体中未声明的属性（additionalProperties 为 {"not": {}}），用于发现拼写错误
+func WithSchemaStrict() SchemaOption {
+	return func(o *schemaOptions) {
+		o.strict = true
+	}
+}
+
+// GenerateSchema 由配置结构体生成 JSON Schema：
+// 类型与 validate tag 约束的映射复用 validator.OpenAPISchema，
+// 在其上按配置文件的写法替换属性名与 time.Duration 的类型，default 取 defaults.SetDefaults 实际设置的值
+func GenerateSchema(v any, opts ...SchemaOption) (*JSONSchema, error) {
+	options := schemaOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("v must be a struct or pointer to struct")
+	}
+
+	g := &schemaGenerator{
+		options:  options,
+		defaults: make(map[reflect.Type]reflect.Value),
+	}
+	schema, err := validator.OpenAPISchema(v,
+		validator.WithSchemaPropertyName(g.propertyName),
+		validator.WithSchemaTypeFunc(schemaForType),
+		validator.WithSchemaFieldFunc(g.fieldDefault),
+		validator.WithSchemaStructFunc(g.finishStruct),
+	)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = SchemaDraft
+	schema.ID = options.id
+	schema.Title = options.title
+	return schema, nil
+}
+
+// GenerateSchemaJSON 生成格式化后的 JSON Schema 文本
+func GenerateSchemaJSON(v any, opts ...SchemaOption) ([]byte, error) {
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type schemaGenerator struct {
+	options schemaOptions
+	// defaults 各结构体类型已应用默认值的实例
+	defaults map[reflect.Type]reflect.Value
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// schemaForType 替换配置文件中写法不同于 encoding/json 的类型
+func schemaForType(t reflect.Type) *JSONSchema {
+	if t == durationType {
+		// yaml/toml 写作 "5s"，json 通常是纳秒整数
+		return &JSONSchema{Type: SchemaType{"string", "integer"}, Pattern: `^(-?\d+|(-?\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+)$`}
+	}
+	return nil
+}
+
+// fieldDefault 为带 default tag 的字段写入 default
+func (g *schemaGenerator) fieldDefault(prop *JSONSchema, parent reflect.Type, field reflect.StructField) {
+	if _, ok := field.Tag.Lookup("default"); !ok {
+		return
+	}
+
+	value, ok := g.defaults[parent]
+	if !ok {
+		value = reflect.New(parent)
+		_ = defaults.SetDefaultsWithOptions(value.Interface(), &defaults.Options{ErrorMode: defaults.ErrorModeIgnore})
+		value = value.Elem()
+		g.defaults[parent] = value
+	}
+	prop.Default = schemaDefault(value.FieldByIndex(field.Index))
+}
+
+// finishStruct 排序 required，严格模式下禁止未声明的属性
+func (g *schemaGenerator) finishStruct(schema *JSONSchema, t reflect.Type) {
+	sort.Strings(schema.Required)
+	if g.options.strict {
+		schema.AdditionalProperties = &JSONSchema{Not: &JSONSchema{}}
+	}
+}
+
+// propertyName 返回属性名及是否在 tag 中显式指定
+func (g *schemaGenerator) propertyName(field reflect.StructField) (string, bool) {
+	if g.options.tag != "" {
+		if tagValue, ok := field.Tag.Lookup(g.options.tag); ok {
+			if commaIndex := strings.Index(tagValue, ","); commaIndex != -1 {
+				tagValue = tagValue[:commaIndex]
+			}
+			if tagValue != "" {
+				return tagValue, true
+			}
+		}
+		return strings.ToLower(field.Name), false
+	}
+
+	for _, tag := range []string{"json", "yaml", "toml", "ini"} {
+		tagValue, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		if commaIndex := strings.Index(tagValue, ","); commaIndex != -1 {
+			tagValue = tagValue[:commaIndex]
+		}
+		if tagValue != "" {
+			return tagValue, true
+		}
+	}
+	return configKey(field), false
+}
+
+// schemaDefault 把 defaults 设置后的字段值转换为 JSON 值
+func schemaDefault(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case durationType:
+		return time.Duration(v.Int()).String()
+	case timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return nil
+	}
+
+	buf, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil
+	}
+	var out any
+	if json.Unmarshal(buf, &out) != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestConfig struct {
+	Name    string            `yaml:"name" validate:"required,min=3,max=32"`
+	Port    int               `yaml:"port" default:"8080" validate:"min=1,max=65535"`
+	Mode    string            `yaml:"mode" default:"prod" validate:"oneof=prod"`
+	Admin   string            `yaml:"admin" validate:"omitempty,email"`
+	Site    string            `yaml:"site" validate:"url"`
+	Timeout time.Duration     `yaml:"timeout"`
+	Ratio   float64           `yaml:"ratio" validate:"gt=0,lte=1"`
+	Tags    []string          `yaml:"tags" default:"a,b" validate:"max=3,dive,alpha"`
+	Labels  map[string]string `yaml:"labels"`
+	Workers uint              `yaml:"workers"`
+	DB      struct {
+		Host string `yaml:"host" default:"localhost" validate:"required"`
+		Port int    `yaml:"port" default:"5432"`
+	} `yaml:"db"`
+	Servers []struct {
+		Host string `yaml:"host" validate:"required,hostname"`
+	} `yaml:"servers"`
+	internal string
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema(&schemaTestConfig{}, WithSchemaID("https://example.com/app.json"), WithSchemaTag("yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, SchemaDraft, schema.Schema)
+	assert.Equal(t, "https://example.com/app.json", schema.ID)
+	assert.Equal(t, []string{"name"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "internal")
+
+	name := schema.Properties["name"]
+	assert.Equal(t, SchemaType{"string"}, name.Type)
+	assert.Equal(t, 3, *name.MinLength)
+	assert.Equal(t, 32, *name.MaxLength)
+
+	port := schema.Properties["port"]
+	assert.Equal(t, SchemaType{"integer"}, port.Type)
+	assert.EqualValues(t, 8080, port.Default)
+	assert.EqualValues(t, 1, *port.Minimum)
+	assert.EqualValues(t, 65535, *port.Maximum)
+
+	// oneof 与 validator 的运行时规则一致
+	assert.Equal(t, []any{"prod"}, schema.Properties["mode"].Enum)
+	assert.Equal(t, "prod", schema.Properties["mode"].Default)
+	assert.Equal(t, "email", schema.Properties["admin"].Format)
+	assert.Equal(t, "uri", schema.Properties["site"].Format)
+	assert.Equal(t, SchemaType{"string", "integer"}, schema.Properties["timeout"].Type)
+	assert.EqualValues(t, 0, *schema.Properties["ratio"].ExclusiveMinimum)
+	assert.EqualValues(t, 1, *schema.Properties["ratio"].Maximum)
+	assert.EqualValues(t, 0, *schema.Properties["workers"].Minimum)
+
+	tags := schema.Properties["tags"]
+	assert.Equal(t, 3, *tags.MaxItems)
+	assert.Equal(t, []any{"a", "b"}, tags.Default)
+	assert.Equal(t, `^[a-zA-Z]+$`, tags.Items.Pattern)
+
+	assert.Equal(t, SchemaType{"string"}, schema.Properties["labels"].AdditionalProperties.Type)
+
+	db := schema.Properties["db"]
+	assert.Equal(t, []string{"host"}, db.Required)
+	assert.Equal(t, "localhost", db.Properties["host"].Default)
+	assert.EqualValues(t, 5432, db.Properties["port"].Default)
+
+	servers := schema.Properties["servers"]
+	assert.Equal(t, "hostname", servers.Items.Properties["host"].Format)
+
+	_, err = GenerateSchema(1)
+	assert.Error(t, err)
+}
+
+func TestGenerateSchemaJSON(t *testing.T) {
+	data, err := GenerateSchemaJSON(schemaTestConfig{}, WithSchemaTag("yaml"), WithSchemaStrict())
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, SchemaDraft, raw["$schema"])
+	assert.Equal(t, "object", raw["type"])
+	assert.Equal(t, map[string]any{"not": map[string]any{}}, raw["additionalProperties"])
+	assert.Equal(t, []any{"string", "integer"}, raw["properties"].(map[string]any)["timeout"].(map[string]any)["type"])
+
+	var decoded JSONSchema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, SchemaType{"object"}, decoded.Type)
+	assert.Equal(t, SchemaType{"string", "integer"}, decoded.Properties["timeout"].Type)
+}
+
+func TestValidateDocument(t *testing.T) {
+	schema, err := GenerateSchema(&schemaTestConfig{}, WithSchemaTag("yaml"), WithSchemaStrict())
+	require.NoError(t, err)
+
+	t.Run("yaml valid", func(t *testing.T) {
+		doc := "name: demo\nport: 8080\ntimeout: 5s\nratio: 0.5\nservers:\n  - host: a.example.com\n"
+		assert.NoError(t, ValidateDocument(schema, "config.yaml", []byte(doc)))
+	})
+
+	t.Run("yaml errors", func(t *testing.T) {
+		doc := "name: demo\n" +
+			"port: 70000\n" +
+			"mode: staging\n" +
+			"db:\n" +
+			"  port: '5432'\n" +
+			"servers:\n" +
+			"  - host: ok.example.com\n" +
+			"  - host: bad_host!\n" +
+			"typo: 1\n"
+		err := ValidateDocument(schema, "config.yaml", []byte(doc))
+		require.Error(t, err)
+
+		var errs SchemaErrors
+		require.ErrorAs(t, err, &errs)
+		messages := make([]string, 0, len(errs))
+		for _, e := range errs {
+			messages = append(messages, e.Error())
+		}
+		assert.Equal(t, []string{
+			`config.yaml:2:1: port: must be <= 65535`,
+			`config.yaml:3:1: mode: must be one of [prod]`,
+			`config.yaml:4:1: db: missing required property "host"`,
+			`config.yaml:5:3: db.port: expected integer, got string`,
+			`config.yaml:8:5: servers[1].host: must be a valid hostname`,
+			`config.yaml:9:1: typo: unknown property "typo"`,
+		}, messages)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		doc := "{\n  \"name\": \"ab\",\n  \"tags\": [\"ok\", \"no-dash\"]\n}\n"
+		err := ValidateDocument(schema, "config.json", []byte(doc))
+		require.Error(t, err)
+		assert.Equal(t, "config.json:2:3: name: length must be >= 3\n"+
+			`config.json:3:18: tags[1]: must match pattern "^[a-zA-Z]+$"`, err.Error())
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		doc := "name = \"demo\"\n" +
+			"ratio = 0\n" +
+			"\n" +
+			"[db]\n" +
+			"host = \"h\"\n" +
+			"port = 1.5\n" +
+			"\n" +
+			"[[servers]]\n" +
+			"host = \"a.example.com\"\n" +
+			"\n" +
+			"[[servers]]\n" +
+			"host = \"-bad\"\n"
+		err := ValidateDocument(schema, "config.toml", []byte(doc))
+		require.Error(t, err)
+		assert.Equal(t, "config.toml:2:1: ratio: must be > 0\n"+
+			"config.toml:6:1: db.port: expected integer, got number\n"+
+			"config.toml:12:1: servers[1].host: must be a valid hostname", err.Error())
+	})
+
+	t.Run("json5 without positions", func(t *testing.T) {
+		err := ValidateDocument(schema, "config.json5", []byte(`{name: "x", port: 0}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config.json5: name: length must be >= 3")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Error(t, ValidateDocument(schema, "config.ini", []byte("name=x")))
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		assert.Error(t, ValidateDocument(schema, "config.yaml", []byte("name: [")))
+	})
+}
+
+func TestValidateDocumentValidatorRules(t *testing.T) {
+	type cfg struct {
+		Version int               `yaml:"version" validate:"eq=2"`
+		Prefix  string            `yaml:"prefix" validate:"startswith=app,endswith=_"`
+		Hosts   map[string]string `yaml:"hosts" validate:"dive,keys,alpha,endkeys,hostname"`
+	}
+	schema, err := GenerateSchema(&cfg{}, WithSchemaTag("yaml"))
+	require.NoError(t, err)
+
+	assert.NoError(t, ValidateDocument(schema, "config.yaml", []byte("version: 2\nprefix: app_\nhosts:\n  db: db.local\n")))
+
+	err = ValidateDocument(schema, "config.yaml", []byte("version: 1\nprefix: api\nhosts:\n  db1: db.local\n"))
+	require.Error(t, err)
+	assert.Equal(t, "config.yaml:1:1: version: must be 2\n"+
+		`config.yaml:2:1: prefix: must match pattern "_$"`+"\n"+
+		`config.yaml:2:1: prefix: must match pattern "^app"`+"\n"+
+		`config.yaml:4:3: hosts.db1: must match pattern "^[a-zA-Z]+$"`, err.Error())
+}
@@ -0,0 +1,633 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"github.com/yosuke-furukawa/json5/encoding/json5"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaError 描述配置文档中的一处 schema 校验错误
+type SchemaError struct {
+	File string
+	// Line/Column 从 1 开始，格式不支持定位时为 0
+	Line   int
+	Column int
+	// Path 为文档中的键路径，如 db.port、servers[0].host，根为空
+	Path    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.File)
+	if e.Line > 0 {
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(e.Line))
+		if e.Column > 0 {
+			b.WriteString(":")
+			b.WriteString(strconv.Itoa(e.Column))
+		}
+	}
+	b.WriteString(": ")
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// SchemaErrors 是一次校验发现的全部错误，按出现位置排序
+type SchemaErrors []*SchemaError
+
+func (e SchemaErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// ValidateFile 读取配置文件并用 ValidateDocument 校验
+func ValidateFile(schema *JSONSchema, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return ValidateDocument(schema, path, data)
+}
+
+// ValidateDocument 在解码到结构体之前按 schema 校验原始配置文档，格式由 path 的扩展名决定。
+// 校验失败返回 SchemaErrors，.yaml/.yml/.json/.toml 的错误带行列号，.json5 只有键路径；
+// 其余格式没有通用的文档结构，返回错误
+func ValidateDocument(schema *JSONSchema, path string, data []byte) error {
+	doc, positions, err := parseSchemaDocument(path, data)
+	if err != nil {
+		return err
+	}
+
+	v := &schemaValidator{file: path, positions: positions}
+	v.validate(schema, "", doc)
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		if v.errs[i].Line != v.errs[j].Line {
+			return v.errs[i].Line < v.errs[j].Line
+		}
+		return v.errs[i].Column < v.errs[j].Column
+	})
+	return v.errs
+}
+
+// docPosition 是键在文档中的位置
+type docPosition struct {
+	line   int
+	column int
+}
+
+func parseSchemaDocument(path string, data []byte) (any, map[string]docPosition, error) {
+	var doc any
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		var root yaml.Node
+		err := yaml.Unmarshal(data, &root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(root.Content) == 0 {
+			return map[string]any{}, nil, nil
+		}
+		err = root.Content[0].Decode(&doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		positions := make(map[string]docPosition)
+		yamlPositions(root.Content[0], "", positions)
+		return doc, positions, nil
+
+	case ".json":
+		err := json.Unmarshal(data, &doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return doc, jsonPositions(data), nil
+
+	case ".toml":
+		err := toml.Unmarshal(data, &doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return doc, tomlPositions(data), nil
+
+	case ".json5":
+		err := json5.Unmarshal(data, &doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return doc, nil, nil
+
+	default:
+		return nil, nil, fmt.Errorf("schema validation does not support config file format:%v", ext)
+	}
+}
+
+func joinDocKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func joinDocIndex(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func yamlPositions(node *yaml.Node, path string, positions map[string]docPosition) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			p := joinDocKey(path, key.Value)
+			positions[p] = docPosition{line: key.Line, column: key.Column}
+			yamlPositions(value, p, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			p := joinDocIndex(path, i)
+			positions[p] = docPosition{line: item.Line, column: item.Column}
+			yamlPositions(item, p, positions)
+		}
+	}
+}
+
+// jsonPositions 用 json.Decoder 的 token 流记录每个键与数组元素的起始位置
+func jsonPositions(data []byte) map[string]docPosition {
+	positions := make(map[string]docPosition)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	// 跳过空白与分隔符，定位到下一个 token 的起点
+	positionAt := func(offset int64) docPosition {
+		i := int(offset)
+		for i < len(data) && strings.IndexByte(" \t\r\n,:", data[i]) >= 0 {
+			i++
+		}
+		lead := data[:i]
+		return docPosition{
+			line:   bytes.Count(lead, []byte{'\n'}) + 1,
+			column: i - bytes.LastIndexByte(lead, '\n'),
+		}
+	}
+
+	var walk func(path string) bool
+	walk = func(path string) bool {
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				pos := positionAt(dec.InputOffset())
+				key, err := dec.Token()
+				if err != nil {
+					return false
+				}
+				p := joinDocKey(path, fmt.Sprint(key))
+				positions[p] = pos
+				if !walk(p) {
+					return false
+				}
+			}
+			_, err = dec.Token()
+			return err == nil
+
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				p := joinDocIndex(path, i)
+				positions[p] = positionAt(dec.InputOffset())
+				if !walk(p) {
+					return false
+				}
+			}
+			_, err = dec.Token()
+			return err == nil
+		}
+		return true
+	}
+	walk("")
+
+	return positions
+}
+
+// tomlPositions 用 go-toml 的解析器记录键的位置，[[array]] 表按出现顺序编号
+func tomlPositions(data []byte) map[string]docPosition {
+	positions := make(map[string]docPosition)
+
+	var p unstable.Parser
+	p.Reset(data)
+
+	position := func(node *unstable.Node) (docPosition, bool) {
+		if node == nil || node.Raw.Length == 0 {
+			return docPosition{}, false
+		}
+		shape := p.Shape(node.Raw)
+		return docPosition{line: shape.Start.Line, column: shape.Start.Column}, true
+	}
+
+	// arrays 记录 [[表]] 已出现的次数，key 为带下标的父路径 + 表名
+	arrays := make(map[string]int)
+	resolve := func(base string, it unstable.Iterator, arrayTable bool) (string, docPosition) {
+		path := base
+		var first docPosition
+		var firstSet bool
+		for it.Next() {
+			node := it.Node()
+			if !firstSet {
+				first, firstSet = position(node)
+			}
+			path = joinDocKey(path, string(node.Data))
+			if it.IsLast() {
+				break
+			}
+			if n := arrays[path]; n > 0 {
+				path = joinDocIndex(path, n-1)
+			}
+		}
+		if arrayTable {
+			n := arrays[path]
+			arrays[path] = n + 1
+			path = joinDocIndex(path, n)
+		}
+		return path, first
+	}
+
+	var keyValue func(base string, node *unstable.Node)
+	var value func(path string, pos docPosition, node *unstable.Node)
+
+	keyValue = func(base string, node *unstable.Node) {
+		path, pos := resolve(base, node.Key(), false)
+		positions[path] = pos
+		value(path, pos, node.Value())
+	}
+	value = func(path string, pos docPosition, node *unstable.Node) {
+		switch node.Kind {
+		case unstable.InlineTable:
+			children := node.Children()
+			for children.Next() {
+				keyValue(path, children.Node())
+			}
+		case unstable.Array:
+			children := node.Children()
+			for i := 0; children.Next(); i++ {
+				child := children.Node()
+				p := joinDocIndex(path, i)
+				childPos, ok := position(child)
+				if !ok {
+					childPos = pos
+				}
+				positions[p] = childPos
+				value(p, childPos, child)
+			}
+		}
+	}
+
+	var table string
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			path, pos := resolve("", expr.Key(), expr.Kind == unstable.ArrayTable)
+			table = path
+			if _, ok := positions[path]; !ok {
+				positions[path] = pos
+			}
+		case unstable.KeyValue:
+			keyValue(table, expr)
+		}
+	}
+
+	return positions
+}
+
+type schemaValidator struct {
+	file      string
+	positions map[string]docPosition
+	errs      SchemaErrors
+}
+
+func (v *schemaValidator) report(path, format string, args ...any) {
+	err := &SchemaError{
+		File:    v.file,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	// 找不到自身位置时（如缺少必填项）使用最近的上级位置
+	for p := path; p != ""; p = parentDocPath(p) {
+		if pos, ok := v.positions[p]; ok {
+			err.Line, err.Column = pos.line, pos.column
+			break
+		}
+	}
+	if err.Line == 0 && v.positions != nil {
+		err.Line = 1
+	}
+	v.errs = append(v.errs, err)
+}
+
+func parentDocPath(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i <= 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+func (v *schemaValidator) validate(schema *JSONSchema, path string, value any) {
+	if schema == nil {
+		return
+	}
+
+	value = normalizeDocValue(value)
+
+	if schema.Not != nil {
+		sub := &schemaValidator{file: v.file}
+		sub.validate(schema.Not, path, value)
+		if len(sub.errs) == 0 {
+			v.report(path, "value is not allowed")
+			return
+		}
+	}
+
+	kind := docKind(value)
+	if len(schema.Type) > 0 && !schemaTypeMatches(schema.Type, kind) {
+		v.report(path, "expected %s, got %s", strings.Join(schema.Type, " or "), kind)
+		return
+	}
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		v.report(path, "must be one of %v", schema.Enum)
+	}
+	if schema.Const != nil && !enumContains([]any{schema.Const}, value) {
+		v.report(path, "must be %v", schema.Const)
+	}
+	for _, sub := range schema.AllOf {
+		v.validate(sub, path, value)
+	}
+
+	switch value := value.(type) {
+	case string:
+		v.validateString(schema, path, value)
+	case float64:
+		v.validateNumber(schema, path, value)
+	case []any:
+		v.validateArray(schema, path, value)
+	case map[string]any:
+		v.validateObject(schema, path, value)
+	}
+}
+
+func (v *schemaValidator) validateString(schema *JSONSchema, path, value string) {
+	length := utf8.RuneCountInString(value)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.report(path, "length must be >= %d", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.report(path, "length must be <= %d", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := compileSchemaPattern(schema.Pattern)
+		if err == nil && !re.MatchString(value) {
+			v.report(path, "must match pattern %q", schema.Pattern)
+		}
+	}
+	if schema.Format != "" && !formatMatches(schema.Format, value) {
+		v.report(path, "must be a valid %s", schema.Format)
+	}
+}
+
+func (v *schemaValidator) validateNumber(schema *JSONSchema, path string, value float64) {
+	if schema.Minimum != nil && value < *schema.Minimum {
+		v.report(path, "must be >= %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		v.report(path, "must be <= %v", *schema.Maximum)
+	}
+	if schema.ExclusiveMinimum != nil && value <= *schema.ExclusiveMinimum {
+		v.report(path, "must be > %v", *schema.ExclusiveMinimum)
+	}
+	if schema.ExclusiveMaximum != nil && value >= *schema.ExclusiveMaximum {
+		v.report(path, "must be < %v", *schema.ExclusiveMaximum)
+	}
+}
+
+func (v *schemaValidator) validateArray(schema *JSONSchema, path string, value []any) {
+	if schema.MinItems != nil && len(value) < *schema.MinItems {
+		v.report(path, "must have at least %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+		v.report(path, "must have at most %d items", *schema.MaxItems)
+	}
+	for i, item := range value {
+		v.validate(schema.Items, joinDocIndex(path, i), item)
+	}
+}
+
+func (v *schemaValidator) validateObject(schema *JSONSchema, path string, value map[string]any) {
+	if schema.MinProperties != nil && len(value) < *schema.MinProperties {
+		v.report(path, "must have at least %d properties", *schema.MinProperties)
+	}
+	if schema.MaxProperties != nil && len(value) > *schema.MaxProperties {
+		v.report(path, "must have at most %d properties", *schema.MaxProperties)
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.report(path, "missing required property %q", name)
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		p := joinDocKey(path, key)
+		if schema.PropertyNames != nil {
+			v.validate(schema.PropertyNames, p, key)
+		}
+		if prop, ok := schema.Properties[key]; ok {
+			v.validate(prop, p, value[key])
+			continue
+		}
+		if additional := schema.AdditionalProperties; additional != nil {
+			if additional.Not != nil && reflect.DeepEqual(additional.Not, &JSONSchema{}) {
+				v.report(p, "unknown property %q", key)
+				continue
+			}
+			v.validate(additional, p, value[key])
+		}
+	}
+}
+
+// normalizeDocValue 把各解析器的值统一为 JSON 数据模型：数字为 float64，map 键为 string，时间为 RFC3339 字符串
+func normalizeDocValue(value any) any {
+	switch value := value.(type) {
+	case nil, bool, string, float64, []any, map[string]any:
+		return value
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	case json.Number:
+		f, _ := value.Float64()
+		return f
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		// toml.LocalDate 等本地时间
+		return value.String()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = rv.Index(i).Interface()
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		return out
+	}
+	return value
+}
+
+func docKind(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == math.Trunc(value) && !math.IsInf(value, 0) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func schemaTypeMatches(types SchemaType, kind string) bool {
+	if types.Has(kind) {
+		return true
+	}
+	return kind == "integer" && types.Has("number")
+}
+
+func enumContains(enum []any, value any) bool {
+	for _, candidate := range enum {
+		if reflect.DeepEqual(normalizeDocValue(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	schemaPatternMu    sync.Mutex
+	schemaPatternCache = make(map[string]*regexp.Regexp)
+
+	hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+func compileSchemaPattern(pattern string) (*regexp.Regexp, error) {
+	schemaPatternMu.Lock()
+	defer schemaPatternMu.Unlock()
+
+	if re, ok := schemaPatternCache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	schemaPatternCache[pattern] = re
+	return re, nil
+}
+
+// formatMatches 校验常用 format，未知 format 视为通过
+func formatMatches(format, value string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != ""
+	case "hostname":
+		return len(value) <= 253 && hostnamePattern.MatchString(value)
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && strings.Contains(value, ":")
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	}
+	return true
+}
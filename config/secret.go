@@ -0,0 +1,417 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lazygophers/utils/cryptox"
+	"github.com/lazygophers/utils/pgp"
+)
+
+const (
+	// secretEncPrefix 是加密值的前缀，其后为 base64 编码的密文
+	secretEncPrefix = "enc:v1:"
+	// secretMask 替换日志与导出内容中的明文
+	secretMask = "******"
+	// secretKeyFileEnv 未调用 SetSecretOptions 时，从该环境变量读取 AES 密钥文件路径
+	secretKeyFileEnv = "LAZYGOPHERS_CONFIG_KEYFILE"
+)
+
+// secretRefPattern 匹配 ${env:NAME} 与 ${file:/path}，可以嵌在普通字符串中
+var secretRefPattern = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// SecretOption 配置 enc:v1 值的解密密钥
+type SecretOption func(*secretOptions)
+
+type secretOptions struct {
+	key           []byte
+	keyFile       string
+	pgpPrivateKey string
+	pgpPassphrase string
+}
+
+// WithSecretKey 使用 32 字节的 AES-256 密钥解密
+func WithSecretKey(key []byte) SecretOption {
+	return func(o *secretOptions) {
+		o.key = key
+	}
+}
+
+// WithSecretKeyFile 从文件读取 AES-256 密钥，文件内容可以是 32 字节原始密钥，或其 base64/hex 文本
+func WithSecretKeyFile(path string) SecretOption {
+	return func(o *secretOptions) {
+		o.keyFile = path
+	}
+}
+
+// WithSecretPGPKey 使用 PGP 私钥解密，未加密的私钥 passphrase 传空字符串
+func WithSecretPGPKey(privateKeyPEM, passphrase string) SecretOption {
+	return func(o *secretOptions) {
+		o.pgpPrivateKey = privateKeyPEM
+		o.pgpPassphrase = passphrase
+	}
+}
+
+// secretField 是一个字段解析后的值与配置文件中的原始写法
+type secretField struct {
+	resolved string
+	original string
+}
+
+// secretState 是一次加载中解析出的密钥：按字段路径记录原始写法，用于 SetConfig 写回；
+// plains 为单个密文/引用解析出的明文，按长度降序，用于在任意字符串中打码
+type secretState struct {
+	fields map[string]secretField
+	plains []string
+}
+
+var (
+	secretMu   sync.RWMutex
+	secretOpts = secretOptions{keyFile: os.Getenv(secretKeyFileEnv)}
+	// secrets 是最近一次加载的配置中的密钥，每次加载整体替换
+	secrets = &secretState{}
+)
+
+// SetSecretOptions 设置 enc:v1 值的解密密钥，替换之前的设置。
+// 同时配置 AES 与 PGP 时先尝试 AES；未设置时使用环境变量 LAZYGOPHERS_CONFIG_KEYFILE 指向的 AES 密钥文件
+func SetSecretOptions(opts ...SecretOption) {
+	var options secretOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	secretMu.Lock()
+	defer secretMu.Unlock()
+
+	secretOpts = options
+}
+
+// EncryptSecret 用 AES-256-GCM 加密明文，返回可以直接写入配置文件的 enc:v1:<base64> 值
+func EncryptSecret(key []byte, plaintext string) (string, error) {
+	ciphertext, err := cryptox.Encrypt(key, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return secretEncPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// EncryptSecretWithKeyFile 与 EncryptSecret 相同，密钥从文件读取
+func EncryptSecretWithKeyFile(path, plaintext string) (string, error) {
+	key, err := readSecretKeyFile(path)
+	if err != nil {
+		return "", err
+	}
+	return EncryptSecret(key, plaintext)
+}
+
+// EncryptSecretPGP 用 PGP 公钥加密明文，返回 enc:v1:<base64> 值
+func EncryptSecretPGP(publicKeyPEM, plaintext string) (string, error) {
+	ciphertext, err := pgp.Encrypt([]byte(plaintext), publicKeyPEM)
+	if err != nil {
+		return "", err
+	}
+	return secretEncPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Redact 返回 c 的深拷贝，其中解析自密钥引用或密文的字符串替换为 ******，用于打印日志。
+// c 为指针时返回同类型的新指针
+func Redact(c any) any {
+	rv := reflect.ValueOf(c)
+	if !rv.IsValid() {
+		return c
+	}
+
+	state := loadedSecrets()
+	out := reflect.New(rv.Type()).Elem()
+	out.Set(deepCopyValue(rv))
+	_ = walkStrings(out, "", func(path, s string) (string, error) {
+		if field, ok := state.fields[path]; ok && field.resolved == s {
+			return secretMask, nil
+		}
+		return state.redact(s), nil
+	})
+	return out.Interface()
+}
+
+// RedactString 把 s 中出现的已解析明文替换为 ******，用于错误信息等自由文本
+func RedactString(s string) string {
+	return loadedSecrets().redact(s)
+}
+
+func (st *secretState) redact(s string) string {
+	for _, plain := range st.plains {
+		if strings.Contains(s, plain) {
+			s = strings.ReplaceAll(s, plain, secretMask)
+		}
+	}
+	return s
+}
+
+func loadedSecrets() *secretState {
+	secretMu.RLock()
+	defer secretMu.RUnlock()
+
+	return secrets
+}
+
+// resolveSecrets 原地解析 c 中所有字符串里的 ${env:...}、${file:...} 与 enc:v1: 值，
+// 成功后替换之前加载登记的密钥
+func resolveSecrets(c any) error {
+	rv := reflect.ValueOf(c)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("v must be a pointer")
+	}
+
+	state := &secretState{fields: make(map[string]secretField)}
+	plains := make(map[string]struct{})
+	err := walkStrings(rv.Elem(), "", func(path, s string) (string, error) {
+		resolved, found, err := resolveSecretString(s)
+		if err != nil || len(found) == 0 {
+			return resolved, err
+		}
+
+		state.fields[path] = secretField{resolved: resolved, original: s}
+		for _, plain := range found {
+			if _, ok := plains[plain]; ok || plain == "" {
+				continue
+			}
+			plains[plain] = struct{}{}
+			state.plains = append(state.plains, plain)
+		}
+		return resolved, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 长的先替换
+	sort.SliceStable(state.plains, func(i, j int) bool {
+		return len(state.plains[i]) > len(state.plains[j])
+	})
+
+	secretMu.Lock()
+	secrets = state
+	secretMu.Unlock()
+	return nil
+}
+
+// resolveSecretString 解析 s 中的密文与引用，返回解析结果与其中的明文，没有可解析内容时原样返回
+func resolveSecretString(s string) (string, []string, error) {
+	if strings.HasPrefix(s, secretEncPrefix) {
+		plain, err := decryptSecret(strings.TrimPrefix(s, secretEncPrefix))
+		if err != nil {
+			return "", nil, err
+		}
+		return plain, []string{plain}, nil
+	}
+
+	if !strings.Contains(s, "${") {
+		return s, nil, nil
+	}
+
+	var (
+		plains []string
+		errs   []error
+	)
+	resolved := secretRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		match := secretRefPattern.FindStringSubmatch(ref)
+		plain, err := resolveSecretRef(match[1], strings.TrimSpace(match[2]))
+		if err != nil {
+			errs = append(errs, err)
+			return ref
+		}
+		plains = append(plains, plain)
+		return plain
+	})
+	if len(errs) > 0 {
+		return "", nil, errors.Join(errs...)
+	}
+	return resolved, plains, nil
+}
+
+func resolveSecretRef(kind, name string) (string, error) {
+	switch kind {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("config: secret ${env:%s}: environment variable not set", name)
+		}
+		return value, nil
+
+	case "file":
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("config: secret ${file:%s}: %w", name, err)
+		}
+		// 兼容以换行结尾的 secret 文件
+		return strings.TrimRight(string(content), "\r\n"), nil
+
+	default:
+		return "", fmt.Errorf("config: unsupported secret reference %q", kind)
+	}
+}
+
+func decryptSecret(encoded string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", fmt.Errorf("config: decode %s value: %w", secretEncPrefix, err)
+	}
+
+	secretMu.RLock()
+	options := secretOpts
+	secretMu.RUnlock()
+
+	key := options.key
+	if key == nil && options.keyFile != "" {
+		key, err = readSecretKeyFile(options.keyFile)
+		if err != nil {
+			return "", err
+		}
+	}
+	if key == nil && options.pgpPrivateKey == "" {
+		return "", fmt.Errorf("config: no key configured to decrypt %s value", secretEncPrefix)
+	}
+
+	var errs []error
+	if key != nil {
+		plain, err := cryptox.Decrypt(key, ciphertext)
+		if err == nil {
+			return string(plain), nil
+		}
+		errs = append(errs, err)
+	}
+
+	if options.pgpPrivateKey != "" {
+		plain, err := pgp.Decrypt(ciphertext, options.pgpPrivateKey, options.pgpPassphrase)
+		if err == nil {
+			return string(plain), nil
+		}
+		errs = append(errs, err)
+	}
+
+	return "", fmt.Errorf("config: decrypt %s value: %w", secretEncPrefix, errors.Join(errs...))
+}
+
+// readSecretKeyFile 读取 32 字节密钥，支持原始字节、base64 与 hex 文本
+func readSecretKeyFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read secret key file: %w", err)
+	}
+	if len(content) == 32 {
+		return content, nil
+	}
+
+	text := strings.TrimSpace(string(content))
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, fmt.Errorf("config: secret key file %s must hold a 32-byte key, raw or base64/hex encoded", path)
+}
+
+// unresolveSecrets 返回 c 的深拷贝，值未被修改的密钥字段按字段路径还原为配置文件中的引用或密文写法，
+// 其余字符串原样保留，用于 SetConfig 写回文件
+func unresolveSecrets(c any) any {
+	rv := reflect.ValueOf(c)
+	if !rv.IsValid() {
+		return c
+	}
+
+	state := loadedSecrets()
+	out := reflect.New(rv.Type()).Elem()
+	out.Set(deepCopyValue(rv))
+	_ = walkStrings(out, "", func(path, s string) (string, error) {
+		if field, ok := state.fields[path]; ok && field.resolved == s {
+			return field.original, nil
+		}
+		return s, nil
+	})
+	return out.Interface()
+}
+
+// walkStrings 遍历 v 中可以修改的字符串（结构体导出字段、指针、切片、数组、map 值与 interface），
+// 用 fn 的返回值替换；path 为字段路径，形如 Database.Hosts[0]、Extra[api]
+func walkStrings(v reflect.Value, path string, fn func(path, s string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		s, err := fn(path, v.String())
+		if err != nil {
+			return err
+		}
+		if s != v.String() {
+			v.SetString(s)
+		}
+
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return walkStrings(v.Elem(), path, fn)
+		}
+
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return nil
+		}
+		// interface 中的值不可寻址，拷贝后处理再写回
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		err := walkStrings(elem, path, fn)
+		if err != nil {
+			return err
+		}
+		v.Set(elem)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name := t.Field(i).Name
+			if path != "" {
+				name = path + "." + name
+			}
+			err := walkStrings(v.Field(i), name, fn)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := walkStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			err := walkStrings(elem, fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), fn)
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
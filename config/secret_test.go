@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lazygophers/utils/pgp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type secretTestConfig struct {
+	Name     string            `yaml:"name"`
+	Password string            `yaml:"password"`
+	DSN      string            `yaml:"dsn"`
+	Token    string            `yaml:"token"`
+	Extra    map[string]string `yaml:"extra"`
+}
+
+func withSecretOptions(t *testing.T, opts ...SecretOption) {
+	t.Helper()
+	SetSecretOptions(opts...)
+	t.Cleanup(func() {
+		SetSecretOptions()
+	})
+}
+
+func withConfigPath(t *testing.T, path string) {
+	t.Helper()
+	old := configPath
+	configPath = path
+	t.Cleanup(func() {
+		configPath = old
+	})
+}
+
+func TestLoadConfigSecrets(t *testing.T) {
+	dir := t.TempDir()
+
+	key := bytes.Repeat([]byte{7}, 32)
+	keyFile := filepath.Join(dir, "config.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(fmt.Sprintf("%x\n", key)), 0600))
+	withSecretOptions(t, WithSecretKeyFile(keyFile))
+
+	enc, err := EncryptSecretWithKeyFile(keyFile, "tok-123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:"))
+
+	secretFile := filepath.Join(dir, "db_pass")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-pass\n"), 0600))
+	t.Setenv("SECRET_TEST_DB_PASS", "env-pass")
+
+	path := filepath.Join(dir, "config.yaml")
+	content := "name: demo\n" +
+		"password: ${env:SECRET_TEST_DB_PASS}\n" +
+		"dsn: postgres://app:${file:" + secretFile + "}@db/app\n" +
+		"token: " + enc + "\n" +
+		"extra:\n  api: ${env:SECRET_TEST_DB_PASS}\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	withConfigPath(t, "")
+
+	var c secretTestConfig
+	require.NoError(t, LoadConfig(&c, path))
+	assert.Equal(t, "demo", c.Name)
+	assert.Equal(t, "env-pass", c.Password)
+	assert.Equal(t, "postgres://app:file-pass@db/app", c.DSN)
+	assert.Equal(t, "tok-123", c.Token)
+	assert.Equal(t, "env-pass", c.Extra["api"])
+
+	t.Run("redact", func(t *testing.T) {
+		redacted := Redact(&c).(*secretTestConfig)
+		assert.Equal(t, "demo", redacted.Name)
+		assert.Equal(t, "******", redacted.Password)
+		assert.Equal(t, "******", redacted.DSN)
+		assert.Equal(t, "******", redacted.Token)
+		assert.Equal(t, "******", redacted.Extra["api"])
+		// 原配置不受影响
+		assert.Equal(t, "env-pass", c.Extra["api"])
+
+		assert.Equal(t, "dial app@db: password ****** rejected", RedactString("dial app@db: password file-pass rejected"))
+		assert.NotContains(t, fmt.Sprintf("%+v", Redact(c)), "tok-123")
+	})
+
+	t.Run("set config keeps references", func(t *testing.T) {
+		c.Name = "changed"
+		require.NoError(t, SetConfig(&c))
+
+		written, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(written), "env-pass")
+		assert.NotContains(t, string(written), "file-pass")
+		assert.NotContains(t, string(written), "tok-123")
+		assert.Contains(t, string(written), "${env:SECRET_TEST_DB_PASS}")
+		assert.Contains(t, string(written), enc)
+
+		var reloaded secretTestConfig
+		require.NoError(t, LoadConfig(&reloaded, path))
+		assert.Equal(t, "changed", reloaded.Name)
+		assert.Equal(t, "tok-123", reloaded.Token)
+	})
+}
+
+func TestSetConfigSecretsByField(t *testing.T) {
+	dir := t.TempDir()
+	withConfigPath(t, "")
+	t.Setenv("SECRET_TEST_SHORT", "abc")
+
+	path := filepath.Join(dir, "config.yaml")
+	content := "name: abc-service\n" +
+		"password: ${env:SECRET_TEST_SHORT}\n" +
+		"token: ${env:SECRET_TEST_SHORT}\n" +
+		"extra:\n  note: uses abc\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	var c secretTestConfig
+	require.NoError(t, LoadConfigSkipValidate(&c, path))
+	assert.Equal(t, "abc", c.Password)
+
+	// 只还原解析过且未修改的字段，其他字段即使包含明文也原样写回
+	c.Token = "new-token"
+	require.NoError(t, SetConfig(&c))
+
+	var written secretTestConfig
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), secretMask)
+	require.NoError(t, yaml.Unmarshal(data, &written))
+	assert.Equal(t, "abc-service", written.Name)
+	assert.Equal(t, "${env:SECRET_TEST_SHORT}", written.Password)
+	assert.Equal(t, "new-token", written.Token)
+	assert.Equal(t, "uses abc", written.Extra["note"])
+
+	// 重新加载后只保留本次解析的密钥
+	plain := filepath.Join(dir, "plain.yaml")
+	require.NoError(t, os.WriteFile(plain, []byte("name: demo\n"), 0644))
+	configPath = ""
+	require.NoError(t, LoadConfigSkipValidate(&secretTestConfig{}, plain))
+	assert.Equal(t, "uses abc", RedactString("uses abc"))
+	assert.Empty(t, loadedSecrets().fields)
+}
+
+func TestLoadConfigSecretErrors(t *testing.T) {
+	dir := t.TempDir()
+	withConfigPath(t, "")
+
+	load := func(t *testing.T, value string) error {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("password: "+value+"\n"), 0644))
+		configPath = ""
+		var c secretTestConfig
+		return LoadConfigSkipValidate(&c, path)
+	}
+
+	t.Run("missing env", func(t *testing.T) {
+		err := load(t, "${env:SECRET_TEST_MISSING}")
+		assert.ErrorContains(t, err, "SECRET_TEST_MISSING")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := load(t, "${file:"+filepath.Join(dir, "missing")+"}")
+		assert.Error(t, err)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		withSecretOptions(t)
+		enc, err := EncryptSecret(bytes.Repeat([]byte{1}, 32), "x")
+		require.NoError(t, err)
+		assert.ErrorContains(t, load(t, enc), "no key")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		withSecretOptions(t, WithSecretKey(bytes.Repeat([]byte{2}, 32)))
+		enc, err := EncryptSecret(bytes.Repeat([]byte{1}, 32), "x")
+		require.NoError(t, err)
+		assert.Error(t, load(t, enc))
+	})
+
+	t.Run("bad key file", func(t *testing.T) {
+		keyFile := filepath.Join(dir, "short.key")
+		require.NoError(t, os.WriteFile(keyFile, []byte("short"), 0600))
+		_, err := EncryptSecretWithKeyFile(keyFile, "x")
+		assert.Error(t, err)
+	})
+
+	t.Run("plain values untouched", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("password: ${HOME}\n"), 0644))
+		configPath = ""
+		var c secretTestConfig
+		require.NoError(t, LoadConfigSkipValidate(&c, path))
+		assert.Equal(t, "${HOME}", c.Password)
+	})
+}
+
+func TestSecretPGP(t *testing.T) {
+	keyPair, err := pgp.GenerateKeyPair(&pgp.GenerateOptions{Name: "config", Email: "config@example.com", KeyLength: 1024})
+	require.NoError(t, err)
+
+	enc, err := EncryptSecretPGP(keyPair.PublicKey, "pgp-pass")
+	require.NoError(t, err)
+
+	// AES 密钥解密失败时回退到 PGP
+	withSecretOptions(t,
+		WithSecretKey(bytes.Repeat([]byte{3}, 32)),
+		WithSecretPGPKey(keyPair.PrivateKey, ""),
+	)
+
+	c := secretTestConfig{Password: enc}
+	require.NoError(t, resolveSecrets(&c))
+	assert.Equal(t, "pgp-pass", c.Password)
+}
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lazygophers/log"
+	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/validator"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	// watchDebounce 合并编辑器保存时产生的多次文件事件
+	watchDebounce = 50 * time.Millisecond
+)
+
+// ChangeEvent 是配置重载成功后通知给订阅者的内容
+type ChangeEvent[T any] struct {
+	Old     *T
+	New     *T
+	Changes []FieldChange
+}
+
+// WatchOption 配置 Watcher
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	path     string
+	interval time.Duration
+	polling  bool
+	onError  func(err error)
+}
+
+// WithWatchPath 指定监听的配置文件，默认使用 LoadConfig 解析出的配置文件
+func WithWatchPath(path string) WatchOption {
+	return func(o *watchOptions) {
+		o.path = path
+	}
+}
+
+// WithWatchInterval 设置轮询间隔，默认 1 秒；使用 inotify 时作为兜底检查间隔
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithPolling 强制使用轮询，不使用 inotify
+func WithPolling() WatchOption {
+	return func(o *watchOptions) {
+		o.polling = true
+	}
+}
+
+// WithWatchErrorHandler 设置重载失败时的回调，失败时旧配置保持不变
+func WithWatchErrorHandler(handler func(err error)) WatchOption {
+	return func(o *watchOptions) {
+		o.onError = handler
+	}
+}
+
+// Watcher 监听配置文件，变化时用同一套解析器重新解析、应用 env 覆盖、解析密钥并执行 validator.Struct，
+// 校验通过才原子替换当前配置并通知订阅者；失败时保留旧配置并通过 Err 与错误回调暴露错误
+type Watcher[T any] struct {
+	path    string
+	options watchOptions
+
+	current atomic.Pointer[T]
+
+	mu       sync.Mutex // 串行化重载
+	files    []string   // 根文件及其引入的文件
+	stamps   map[string]fileStamp
+	content  []byte // 所有文件的内容
+	lastErr  error
+	handlers map[uint64]func(ChangeEvent[T])
+	nextID   uint64
+
+	notifier notifier
+	quit     chan struct{}
+	done     chan struct{}
+	closed   sync.Once
+}
+
+// fileStamp 记录文件的修改时间与大小，轮询时用来跳过未变化的文件
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch 监听配置文件（包括 include / $import 引入的文件）并返回 Watcher，initial 为当前生效的配置（通常是 LoadConfig 的结果），可以为 nil。
+// initial 之后不应再被修改，读取最新配置请使用 Current
+func Watch[T any](initial *T, opts ...WatchOption) (*Watcher[T], error) {
+	options := watchOptions{
+		path:     configPath,
+		interval: defaultWatchInterval,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.path == "" {
+		return nil, errors.New("config: no config file to watch")
+	}
+
+	path, err := filepath.Abs(options.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := supportedExtMap[filepath.Ext(path)]; !ok {
+		return nil, fmt.Errorf("unsupported config file format:%v", filepath.Ext(path))
+	}
+
+	w := &Watcher[T]{
+		path:     path,
+		options:  options,
+		handlers: make(map[uint64]func(ChangeEvent[T])),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if initial == nil {
+		initial = new(T)
+	}
+	w.current.Store(initial)
+
+	// 记录当前文件内容，之后只有内容变化才重载
+	w.files = configFiles(path, withIncludeKeys(importKeys))
+	if content, stamps, err := readConfigFiles(w.files); err == nil {
+		w.content, w.stamps = content, stamps
+	}
+
+	if !options.polling {
+		w.notifier, err = newNotifier(w.files)
+		if err != nil {
+			log.Warnf("config: watch %s with polling: %v", path, err)
+			w.notifier = nil
+		}
+	}
+
+	routine.GoWithRecover(func() error {
+		w.loop()
+		return nil
+	})
+
+	return w, nil
+}
+
+// Path 返回监听的配置文件绝对路径
+func (w *Watcher[T]) Path() string {
+	return w.path
+}
+
+// Current 返回当前生效的配置，调用方不应修改返回值
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Err 返回最近一次重载的错误，重载成功后清空
+func (w *Watcher[T]) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.lastErr
+}
+
+// OnChange 注册变更回调，返回取消注册的函数
+// 回调在 Watcher 的协程中执行（调用 Reload 时在调用方协程），多个回调之间的顺序不确定，应避免阻塞
+func (w *Watcher[T]) OnChange(handler func(event ChangeEvent[T])) (cancel func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.nextID++
+	id := w.nextID
+	w.handlers[id] = handler
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.handlers, id)
+	}
+}
+
+// Reload 立即检查并重载配置文件，文件内容未变化时不做任何事
+func (w *Watcher[T]) Reload() error {
+	return w.reload(false)
+}
+
+// Close 停止监听，可重复调用
+func (w *Watcher[T]) Close() error {
+	w.closed.Do(func() {
+		close(w.quit)
+		if w.notifier != nil {
+			w.notifier.Close()
+		}
+		<-w.done
+	})
+	return nil
+}
+
+func (w *Watcher[T]) loop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.options.interval)
+	defer ticker.Stop()
+
+	var events <-chan struct{}
+	if w.notifier != nil {
+		events = w.notifier.Events()
+	}
+
+	for {
+		select {
+		case <-w.quit:
+			return
+
+		case <-ticker.C:
+			_ = w.reload(true)
+
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			// 等待写入完成，合并同一次保存产生的多个事件
+			timer := time.NewTimer(watchDebounce)
+		debounce:
+			for {
+				select {
+				case <-events:
+				case <-timer.C:
+					break debounce
+				case <-w.quit:
+					timer.Stop()
+					return
+				}
+			}
+			_ = w.reload(false)
+		}
+	}
+}
+
+// reload 读取并解析配置文件，statOnly 为 true 时先用修改时间跳过未变化的文件
+func (w *Watcher[T]) reload(statOnly bool) error {
+	w.mu.Lock()
+
+	if statOnly && !w.changedLocked() {
+		w.mu.Unlock()
+		return nil
+	}
+
+	// 引入的文件可能随根文件变化，每次重新解析
+	files := configFiles(w.path, withIncludeKeys(importKeys))
+	content, stamps, err := readConfigFiles(files)
+	if err != nil {
+		return w.failLocked(err)
+	}
+	w.stamps = stamps
+	if bytes.Equal(content, w.content) {
+		// 文件恢复为当前生效的内容
+		w.lastErr = nil
+		w.mu.Unlock()
+		return nil
+	}
+
+	next := new(T)
+	files, err = loadConfigFile(next, w.path, withIncludeKeys(importKeys))
+	if err != nil {
+		return w.failLocked(err)
+	}
+
+	// NOTE: 与 LoadConfig 一致，使用环境变量覆盖配置值
+	err = overrideConfigWithEnv(next)
+	if err != nil {
+		return w.failLocked(err)
+	}
+
+	err = resolveSecrets(next)
+	if err != nil {
+		return w.failLocked(err)
+	}
+
+	err = validator.Struct(next)
+	if err != nil {
+		return w.failLocked(err)
+	}
+
+	w.content = content
+	w.lastErr = nil
+	if !slices.Equal(files, w.files) {
+		w.files = files
+		if w.notifier != nil {
+			err = w.notifier.Watch(files)
+			if err != nil {
+				log.Warnf("config: watch %v: %v", files, err)
+			}
+		}
+	}
+
+	old := w.current.Swap(next)
+	event := ChangeEvent[T]{
+		Old:     old,
+		New:     next,
+		Changes: Diff(old, next),
+	}
+	handlers := make([]func(ChangeEvent[T]), 0, len(w.handlers))
+	for _, handler := range w.handlers {
+		handlers = append(handlers, handler)
+	}
+	w.mu.Unlock()
+
+	log.Infof("config reloaded from %s, %d field(s) changed", w.path, len(event.Changes))
+	for _, handler := range handlers {
+		handler(event)
+	}
+	return nil
+}
+
+// changedLocked 判断是否有文件的修改时间或大小发生变化
+func (w *Watcher[T]) changedLocked() bool {
+	if len(w.stamps) != len(w.files) {
+		return true
+	}
+	for _, file := range w.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return true
+		}
+		stamp, ok := w.stamps[file]
+		if !ok || !info.ModTime().Equal(stamp.modTime) || info.Size() != stamp.size {
+			return true
+		}
+	}
+	return false
+}
+
+// readConfigFiles 读取 files 的内容与修改时间，内容按文件顺序拼接，用于判断配置是否变化
+func readConfigFiles(files []string) ([]byte, map[string]fileStamp, error) {
+	var content bytes.Buffer
+	stamps := make(map[string]fileStamp, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stamps[file] = fileStamp{modTime: info.ModTime(), size: int64(len(data))}
+		fmt.Fprintf(&content, "%s %d\n", file, len(data))
+		content.Write(data)
+	}
+	return content.Bytes(), stamps, nil
+}
+
+// failLocked 记录重载错误并释放锁，旧配置保持不变
+func (w *Watcher[T]) failLocked(err error) error {
+	err = fmt.Errorf("config: reload %s: %w", w.path, err)
+	// 轮询时同一个错误只报告一次
+	repeated := w.lastErr != nil && w.lastErr.Error() == err.Error()
+	w.lastErr = err
+	onError := w.options.onError
+	w.mu.Unlock()
+
+	if repeated {
+		return err
+	}
+
+	log.Errorf("err:%v", err)
+	if onError != nil {
+		onError(err)
+	}
+	return err
+}
+
+// notifier 是文件系统事件源，Events 在关注的文件可能发生变化时发出信号
+type notifier interface {
+	Events() <-chan struct{}
+	// Watch 把关注的文件替换为 paths
+	Watch(paths []string) error
+	Close() error
+}
@@ -0,0 +1,141 @@
+//go:build linux
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyNotifier 监听配置文件所在目录，编辑器以 rename 方式保存时文件本身的 watch 会失效
+type inotifyNotifier struct {
+	fd     int
+	file   *os.File
+	events chan struct{}
+
+	mu    sync.Mutex
+	dirs  map[int32]string    // watch descriptor -> 目录
+	wds   map[string]int32    // 目录 -> watch descriptor
+	files map[string]struct{} // 关注的文件
+}
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_CREATE |
+	syscall.IN_MOVED_TO | syscall.IN_DELETE | syscall.IN_ATTRIB
+
+func newNotifier(paths []string) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	// 非阻塞 fd 交给 runtime poller，Close 可以唤醒阻塞中的 Read
+	n := &inotifyNotifier{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan struct{}, 1),
+		dirs:   make(map[int32]string),
+		wds:    make(map[string]int32),
+	}
+	err = n.Watch(paths)
+	if err != nil {
+		n.file.Close()
+		return nil, err
+	}
+
+	go n.read()
+	return n, nil
+}
+
+// Watch 把关注的文件替换为 paths，按需增删目录的 watch
+func (n *inotifyNotifier) Watch(paths []string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	files := make(map[string]struct{}, len(paths))
+	dirs := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		files[path] = struct{}{}
+		dirs[filepath.Dir(path)] = struct{}{}
+	}
+
+	for dir := range dirs {
+		if _, ok := n.wds[dir]; ok {
+			continue
+		}
+		wd, err := syscall.InotifyAddWatch(n.fd, dir, inotifyMask)
+		if err != nil {
+			return err
+		}
+		n.wds[dir] = int32(wd)
+		n.dirs[int32(wd)] = dir
+	}
+	for dir, wd := range n.wds {
+		if _, ok := dirs[dir]; !ok {
+			_, _ = syscall.InotifyRmWatch(n.fd, uint32(wd))
+			delete(n.wds, dir)
+			delete(n.dirs, wd)
+		}
+	}
+
+	n.files = files
+	return nil
+}
+
+func (n *inotifyNotifier) read() {
+	defer close(n.events)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		size, err := n.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= size; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			nameEnd := nameStart + int(event.Len)
+			offset = nameEnd
+
+			name := string(buf[nameStart:nameEnd])
+			for i := 0; i < len(name); i++ {
+				if name[i] == 0 {
+					name = name[:i]
+					break
+				}
+			}
+			if !n.watching(event.Wd, name) {
+				continue
+			}
+
+			select {
+			case n.events <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// watching 判断目录 wd 下的 name 是否是关注的文件
+func (n *inotifyNotifier) watching(wd int32, name string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	dir, ok := n.dirs[wd]
+	if !ok {
+		return false
+	}
+	_, ok = n.files[filepath.Join(dir, name)]
+	return ok
+}
+
+func (n *inotifyNotifier) Events() <-chan struct{} {
+	return n.events
+}
+
+func (n *inotifyNotifier) Close() error {
+	return n.file.Close()
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package config
+
+import "errors"
+
+// newNotifier 在非 Linux 平台不可用，Watcher 退化为轮询
+func newNotifier(paths []string) (notifier, error) {
+	return nil, errors.New("file notification is not supported on this platform")
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchTestConfig struct {
+	Name   string            `yaml:"name" validate:"required"`
+	Port   int               `yaml:"port" validate:"min=1,max=65535"`
+	Labels map[string]string `yaml:"labels"`
+	DB     struct {
+		Host string `yaml:"host"`
+	} `yaml:"db"`
+}
+
+func writeWatchFile(t *testing.T, path, content string) {
+	t.Helper()
+	// 先写临时文件再 rename，模拟编辑器保存
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0644))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func testWatcher(t *testing.T, opts ...WatchOption) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeWatchFile(t, path, "name: app\nport: 80\ndb:\n  host: a\n")
+
+	var initial watchTestConfig
+	require.NoError(t, LoadConfig(&initial, path))
+
+	var mu sync.Mutex
+	var events []ChangeEvent[watchTestConfig]
+	var errs []error
+	opts = append(opts,
+		WithWatchPath(path),
+		WithWatchInterval(20*time.Millisecond),
+		WithWatchErrorHandler(func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}),
+	)
+
+	w, err := Watch(&initial, opts...)
+	require.NoError(t, err)
+	defer w.Close()
+	assert.Equal(t, path, w.Path())
+
+	w.OnChange(func(event ChangeEvent[watchTestConfig]) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	})
+
+	snapshot := func() ([]ChangeEvent[watchTestConfig], []error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]ChangeEvent[watchTestConfig](nil), events...), append([]error(nil), errs...)
+	}
+
+	writeWatchFile(t, path, "name: app\nport: 81\nlabels:\n  env: prod\ndb:\n  host: b\n")
+	// 回调在替换配置之后执行，等待回调而不是 Current
+	require.Eventually(t, func() bool {
+		got, _ := snapshot()
+		return len(got) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 81, w.Current().Port)
+
+	got, _ := snapshot()
+	event := got[0]
+	assert.Equal(t, 80, event.Old.Port)
+	assert.Equal(t, 81, event.New.Port)
+	assert.Equal(t, []FieldChange{
+		{Path: "DB.Host", Old: "a", New: "b"},
+		{Path: "Labels[env]", Old: nil, New: "prod"},
+		{Path: "Port", Old: 80, New: 81},
+	}, event.Changes)
+
+	// 校验失败时保留旧配置并报告错误
+	writeWatchFile(t, path, "name: app\nport: 0\n")
+	require.Eventually(t, func() bool {
+		_, gotErrs := snapshot()
+		return len(gotErrs) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Error(t, w.Err())
+	assert.Equal(t, 81, w.Current().Port)
+
+	got, _ = snapshot()
+	assert.Len(t, got, 1)
+
+	// 修复后恢复
+	writeWatchFile(t, path, "name: app\nport: 82\n")
+	require.Eventually(t, func() bool {
+		return w.Current().Port == 82
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.NoError(t, w.Err())
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("notify", func(t *testing.T) {
+		testWatcher(t)
+	})
+	t.Run("polling", func(t *testing.T) {
+		testWatcher(t, WithPolling())
+	})
+}
+
+func TestWatchReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"name":"app","port":1}`), 0644))
+
+	w, err := Watch[TestConfig](nil, WithWatchPath(path), WithPolling(), WithWatchInterval(time.Hour))
+	require.NoError(t, err)
+	defer w.Close()
+
+	// 初始内容视为已生效
+	require.NoError(t, w.Reload())
+	assert.Empty(t, w.Current().Name)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"name":"app","port":2}`), 0644))
+	require.NoError(t, w.Reload())
+	assert.Equal(t, 2, w.Current().Port)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{broken`), 0644))
+	assert.Error(t, w.Reload())
+	assert.Equal(t, 2, w.Current().Port)
+
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, w.Reload())
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+}
+
+func TestWatchIncludes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	db := filepath.Join(dir, "db.yaml")
+	writeWatchFile(t, db, "host: a\n")
+	writeWatchFile(t, path, "name: app\nport: 80\ndb:\n  $import: db.yaml\n")
+
+	for _, polling := range []bool{false, true} {
+		var initial watchTestConfig
+		require.NoError(t, LoadConfigWithIncludes(&initial, path))
+		assert.Equal(t, "a", initial.DB.Host)
+
+		opts := []WatchOption{WithWatchPath(path), WithWatchInterval(20 * time.Millisecond)}
+		if polling {
+			opts = append(opts, WithPolling())
+		}
+		w, err := Watch(&initial, opts...)
+		require.NoError(t, err)
+
+		// 修改引入的文件同样触发重载；轮询按修改时间与大小判断，内容长度不同以免落在同一时间片
+		writeWatchFile(t, db, "host: bb\n")
+		require.Eventually(t, func() bool {
+			return w.Current().DB.Host == "bb"
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, 80, w.Current().Port)
+
+		// 根文件新引入的文件也加入监听
+		writeWatchFile(t, filepath.Join(dir, "port.yaml"), "port: 81\n")
+		writeWatchFile(t, path, "$include: port.yaml\nname: app\ndb:\n  $import: db.yaml\n")
+		require.Eventually(t, func() bool {
+			return w.Current().Port == 81
+		}, 2*time.Second, 10*time.Millisecond)
+		writeWatchFile(t, filepath.Join(dir, "port.yaml"), "port: 8200\n")
+		require.Eventually(t, func() bool {
+			return w.Current().Port == 8200
+		}, 2*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, w.Close())
+		writeWatchFile(t, db, "host: a\n")
+		writeWatchFile(t, path, "name: app\nport: 80\ndb:\n  $import: db.yaml\n")
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	original := configPath
+	defer func() { configPath = original }()
+
+	configPath = ""
+	_, err := Watch[TestConfig](nil)
+	assert.Error(t, err)
+
+	_, err = Watch[TestConfig](nil, WithWatchPath("config.unknown"))
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	type inner struct {
+		Value int
+	}
+	type sample struct {
+		Name   string
+		Tags   []string
+		Labels map[string]int
+		Inner  inner
+		Ptr    *inner
+		hidden int
+	}
+
+	old := sample{
+		Name:   "a",
+		Tags:   []string{"x"},
+		Labels: map[string]int{"keep": 1, "drop": 2, "change": 3},
+		Inner:  inner{Value: 1},
+		Ptr:    &inner{Value: 1},
+		hidden: 1,
+	}
+	new := sample{
+		Name:   "a",
+		Tags:   []string{"x", "y"},
+		Labels: map[string]int{"keep": 1, "change": 4, "add": 5},
+		Inner:  inner{Value: 2},
+		Ptr:    &inner{Value: 1},
+		hidden: 2,
+	}
+
+	assert.Equal(t, []FieldChange{
+		{Path: "Inner.Value", Old: 1, New: 2},
+		{Path: "Labels[add]", Old: nil, New: 5},
+		{Path: "Labels[change]", Old: 3, New: 4},
+		{Path: "Labels[drop]", Old: 2, New: nil},
+		{Path: "Tags", Old: []string{"x"}, New: []string{"x", "y"}},
+	}, Diff(&old, &new))
+
+	assert.Empty(t, Diff(old, old))
+
+	new.Ptr = nil
+	changes := Diff(old, new)
+	require.NotEmpty(t, changes)
+	assert.Equal(t, "Inner.Value", changes[0].Path)
+	assert.Contains(t, changes, FieldChange{Path: "Ptr", Old: inner{Value: 1}, New: (*inner)(nil)})
+}
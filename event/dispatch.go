@@ -0,0 +1,360 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lazygophers/utils/atexit"
+	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/runtime"
+	"github.com/lazygophers/utils/xerror"
+)
+
+const (
+	defaultQueueSize = 10
+	defaultWorkers   = 1
+
+	// exitDrainTimeout 是进程退出时等待异步事件处理完成的上限
+	exitDrainTimeout = 5 * time.Second
+)
+
+var (
+	// liveManagers 记录尚未关闭的 Manager，进程退出时由同一个 atexit 回调统一关闭，
+	// Close 之后移除，避免每个 Manager 常驻一个 atexit 回调
+	liveMux      sync.Mutex
+	liveManagers = make(map[*Manager]struct{})
+	atExitOnce   sync.Once
+)
+
+// OverflowPolicy 决定异步队列已满时 Emit 的行为
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞 Emit 直到队列有空位（默认）
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop 丢弃事件并计数，可通过 Dropped 查询
+	OverflowDrop
+	// OverflowSpill 溢出到处理器自己的队列，由该处理器专属的协程串行消费，
+	// 慢处理器只会积压自己的队列，不会阻塞 Emit 与其他处理器；
+	// 溢出队列与共享队列并行消费，溢出后同一处理器的事件不再保证顺序
+	OverflowSpill
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDrop:
+		return "drop"
+	case OverflowSpill:
+		return "spill"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// ErrorHook 接收无人接收的处理器错误：异步处理器 panic 转换出的 *routine.PanicError，
+// 以及 Topic 处理器在异步执行或经 Emit 触发时返回的错误
+type ErrorHook func(eventName string, args any, err error)
+
+// Option 配置 Manager
+type Option func(*Manager)
+
+// WithWorkers 设置消费异步队列的协程数量，默认 1；大于 1 时异步处理器之间不再保证顺序
+func WithWorkers(n int) Option {
+	return func(p *Manager) {
+		if n > 0 {
+			p.workers = n
+		}
+	}
+}
+
+// WithQueueSize 设置异步队列容量，默认 10
+func WithQueueSize(n int) Option {
+	return func(p *Manager) {
+		if n >= 0 {
+			p.queueSize = n
+		}
+	}
+}
+
+// WithOverflow 设置异步队列已满时的策略，默认 OverflowBlock
+func WithOverflow(policy OverflowPolicy) Option {
+	return func(p *Manager) {
+		p.overflow = policy
+	}
+}
+
+// WithErrorHook 设置异步处理器 panic 时的回调
+func WithErrorHook(hook ErrorHook) Option {
+	return func(p *Manager) {
+		p.SetErrorHook(hook)
+	}
+}
+
+// spillQueue 是 OverflowSpill 下单个处理器的溢出队列，不限长度
+type spillQueue struct {
+	mu      sync.Mutex
+	items   []*emitItem
+	running bool
+}
+
+// SetErrorHook 设置异步处理器 panic 时的回调，nil 表示不回调
+func SetErrorHook(hook ErrorHook) {
+	defaultManager.SetErrorHook(hook)
+}
+
+func (p *Manager) SetErrorHook(hook ErrorHook) {
+	p.errorHook.Store(&hook)
+}
+
+func (p *Manager) loadErrorHook() ErrorHook {
+	hook, _ := p.errorHook.Load().(*ErrorHook)
+	if hook == nil {
+		return nil
+	}
+	return *hook
+}
+
+// Dropped 返回因 OverflowDrop 或 Close 之后 Emit 而被丢弃的异步事件数量
+func Dropped() uint64 {
+	return defaultManager.Dropped()
+}
+
+func (p *Manager) Dropped() uint64 {
+	return atomic.LoadUint64(&p.dropped)
+}
+
+// dispatch 把异步事件投递到共享队列，队列已满时按 OverflowPolicy 处理
+func (p *Manager) dispatch(ctx context.Context, event *eventItem, eventName string, args any) {
+	item := &emitItem{
+		// 异步执行时发布方可能已经返回，不继承其取消信号
+		ctx:        context.WithoutCancel(ctx),
+		eventName:  eventName,
+		handler:    event.handler,
+		ctxHandler: event.ctxHandler,
+		args:       args,
+		onError:    p.loadErrorHook(),
+	}
+
+	p.closeMux.RLock()
+	defer p.closeMux.RUnlock()
+
+	if p.closed {
+		atomic.AddUint64(&p.dropped, 1)
+		return
+	}
+
+	p.addPending(1)
+
+	switch p.overflow {
+	case OverflowDrop:
+		select {
+		case p.c <- item:
+		default:
+			atomic.AddUint64(&p.dropped, 1)
+			p.addPending(-1)
+		}
+
+	case OverflowSpill:
+		// 溢出队列非空时继续溢出，避免后来的事件越过已积压的事件
+		if event.spill.len() == 0 {
+			select {
+			case p.c <- item:
+				return
+			default:
+			}
+		}
+		p.spill(event.spill, item)
+
+	default:
+		p.c <- item
+	}
+}
+
+func (q *spillQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
+
+func (p *Manager) spill(q *spillQueue, item *emitItem) {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	if q.running {
+		q.mu.Unlock()
+		return
+	}
+	q.running = true
+	q.mu.Unlock()
+
+	routine.GoWithRecover(func() error {
+		for {
+			q.mu.Lock()
+			if len(q.items) == 0 {
+				q.running = false
+				q.items = nil
+				q.mu.Unlock()
+				return nil
+			}
+			item := q.items[0]
+			q.items[0] = nil
+			q.items = q.items[1:]
+			q.mu.Unlock()
+
+			item.do()
+			p.addPending(-1)
+		}
+	})
+}
+
+// addPending 维护尚未执行完的异步事件数量，归零时唤醒 Drain
+func (p *Manager) addPending(delta int) {
+	p.pendingMux.Lock()
+	defer p.pendingMux.Unlock()
+
+	if p.pending == 0 && delta > 0 {
+		p.idle = make(chan struct{})
+	}
+	p.pending += delta
+	if p.pending == 0 {
+		close(p.idle)
+	}
+}
+
+func (p *Manager) startWorkers() {
+	for i := 0; i < p.workers; i++ {
+		routine.GoWithRecover(func() (err error) {
+			for {
+				select {
+				case item, ok := <-p.c:
+					if !ok {
+						return nil
+					}
+					item.do()
+					p.addPending(-1)
+
+				case <-p.quit:
+					return nil
+				}
+			}
+		})
+	}
+}
+
+// Drain 等待已投递的异步事件（含溢出队列中的事件）全部执行完，不影响后续 Emit。
+// ctx 先结束时返回 xerror 超时/取消错误
+func Drain(ctx context.Context) error {
+	return defaultManager.Drain(ctx)
+}
+
+func (p *Manager) Drain(ctx context.Context) error {
+	p.pendingMux.Lock()
+	if p.pending == 0 {
+		p.pendingMux.Unlock()
+		return nil
+	}
+	idle := p.idle
+	p.pendingMux.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return xerror.FromContext(ctx)
+	}
+}
+
+// Close 停止接收异步事件，等待已投递的事件执行完后退出消费协程，可重复调用。
+// Close 之后同步处理器照常执行，异步事件被丢弃并计入 Dropped
+func Close() error {
+	return defaultManager.Close()
+}
+
+func (p *Manager) Close() error {
+	return p.CloseCtx(context.Background())
+}
+
+// CloseCtx 与 Close 相同，但最多等待到 ctx 结束；超时返回 xerror 错误，剩余事件在后台继续执行
+func (p *Manager) CloseCtx(ctx context.Context) error {
+	p.closeMux.Lock()
+	p.closed = true
+	p.closeMux.Unlock()
+
+	err := p.Drain(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.quitOnce.Do(func() {
+		close(p.quit)
+		p.unregisterAtExit()
+	})
+	return nil
+}
+
+// registerAtExit 登记 Manager，进程退出前尽量处理完异步事件
+func (p *Manager) registerAtExit() {
+	liveMux.Lock()
+	liveManagers[p] = struct{}{}
+	liveMux.Unlock()
+
+	atExitOnce.Do(func() {
+		atexit.Register(closeLiveManagers)
+	})
+}
+
+// unregisterAtExit 在 Manager 关闭后移除登记
+func (p *Manager) unregisterAtExit() {
+	liveMux.Lock()
+	delete(liveManagers, p)
+	liveMux.Unlock()
+}
+
+// closeLiveManagers 并发关闭所有未关闭的 Manager，总等待时间不超过 exitDrainTimeout
+func closeLiveManagers() {
+	liveMux.Lock()
+	managers := make([]*Manager, 0, len(liveManagers))
+	for p := range liveManagers {
+		managers = append(managers, p)
+	}
+	liveMux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), exitDrainTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, p := range managers {
+		wg.Add(1)
+		go func(p *Manager) {
+			defer wg.Done()
+			_ = p.CloseCtx(ctx)
+		}(p)
+	}
+	wg.Wait()
+}
+
+func (p *emitItem) do() {
+	defer runtime.CachePanicWithHandle(func(r interface{}) {
+		if p.onError != nil {
+			p.onError(p.eventName, p.args, &routine.PanicError{Value: r, Stack: runtime.GetStack()})
+		}
+	})
+
+	if p.ctxHandler == nil {
+		p.handler(p.args)
+		return
+	}
+
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := p.ctxHandler(ctx, p.args); err != nil && p.onError != nil {
+		p.onError(p.eventName, p.args, err)
+	}
+}
@@ -0,0 +1,246 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/xerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerOptions(t *testing.T) {
+	manager := NewManager(WithWorkers(4), WithQueueSize(32), WithOverflow(OverflowDrop))
+	defer manager.Close()
+
+	assert.Equal(t, 4, manager.workers)
+	assert.Equal(t, 32, cap(manager.c))
+	assert.Equal(t, OverflowDrop, manager.overflow)
+
+	assert.Equal(t, "block", OverflowBlock.String())
+	assert.Equal(t, "drop", OverflowDrop.String())
+	assert.Equal(t, "spill", OverflowSpill.String())
+	assert.Equal(t, "OverflowPolicy(9)", OverflowPolicy(9).String())
+}
+
+func TestManagerWorkers(t *testing.T) {
+	manager := NewManager(WithWorkers(3))
+	defer manager.Close()
+
+	var running, peak int32
+	release := make(chan struct{})
+	manager.RegisterAsync("parallel", func(args any) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+	})
+
+	for i := 0; i < 3; i++ {
+		manager.Emit("parallel", i)
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&peak) == 3
+	}, time.Second, 5*time.Millisecond)
+	close(release)
+
+	require.NoError(t, manager.Drain(context.Background()))
+}
+
+func TestOverflowDrop(t *testing.T) {
+	manager := NewManager(WithQueueSize(1), WithOverflow(OverflowDrop))
+	defer manager.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var handled int32
+	manager.RegisterAsync("drop", func(args any) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		atomic.AddInt32(&handled, 1)
+	})
+
+	// 第一个事件占住 worker，第二个填满队列，其余被丢弃
+	manager.Emit("drop", 0)
+	<-started
+	for i := 1; i < 5; i++ {
+		manager.Emit("drop", i)
+	}
+	assert.Equal(t, uint64(3), manager.Dropped())
+
+	close(release)
+	require.NoError(t, manager.Drain(context.Background()))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&handled))
+}
+
+func TestOverflowSpill(t *testing.T) {
+	manager := NewManager(WithQueueSize(1), WithOverflow(OverflowSpill))
+	defer manager.Close()
+
+	release := make(chan struct{})
+	manager.RegisterAsync("spill", func(args any) {
+		<-release
+	})
+
+	var mu sync.Mutex
+	var fast []int
+	manager.RegisterAsync("spill.fast", func(args any) {
+		mu.Lock()
+		fast = append(fast, args.(int))
+		mu.Unlock()
+	})
+
+	// 慢处理器的事件溢出到自己的队列，Emit 不阻塞
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			manager.Emit("spill", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Emit should not block with OverflowSpill")
+	}
+	assert.Zero(t, manager.Dropped())
+
+	close(release)
+	require.NoError(t, manager.Drain(context.Background()))
+
+	for i := 0; i < 5; i++ {
+		manager.Emit("spill.fast", i)
+	}
+	require.NoError(t, manager.Drain(context.Background()))
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, fast)
+}
+
+func TestManagerDrain(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	// 没有待处理事件时立即返回
+	require.NoError(t, manager.Drain(context.Background()))
+
+	release := make(chan struct{})
+	var handled int32
+	manager.RegisterAsync("drain", func(args any) {
+		<-release
+		atomic.AddInt32(&handled, 1)
+	})
+	for i := 0; i < 3; i++ {
+		manager.Emit("drain", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := manager.Drain(ctx)
+	assert.Equal(t, xerror.CodeTimeout, xerror.Code(err))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	close(release)
+	require.NoError(t, manager.Drain(context.Background()))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&handled))
+}
+
+func TestManagerClose(t *testing.T) {
+	manager := NewManager()
+
+	var asyncCalled, syncCalled int32
+	manager.RegisterAsync("close", func(args any) {
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&asyncCalled, 1)
+	})
+	manager.Register("close", func(args any) {
+		atomic.AddInt32(&syncCalled, 1)
+	})
+
+	for i := 0; i < 5; i++ {
+		manager.Emit("close", i)
+	}
+	require.NoError(t, manager.Close())
+	assert.Equal(t, int32(5), atomic.LoadInt32(&asyncCalled), "Close should flush pending events")
+
+	// Close 之后异步事件被丢弃，同步处理器照常执行
+	manager.Emit("close", 5)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&asyncCalled))
+	assert.Equal(t, int32(6), atomic.LoadInt32(&syncCalled))
+	assert.Equal(t, uint64(1), manager.Dropped())
+
+	require.NoError(t, manager.Close())
+}
+
+func TestManagerCloseUnregistersAtExit(t *testing.T) {
+	countLive := func() int {
+		liveMux.Lock()
+		defer liveMux.Unlock()
+		return len(liveManagers)
+	}
+
+	before := countLive()
+	for i := 0; i < 10; i++ {
+		manager := NewManager()
+		assert.Equal(t, before+1, countLive())
+		require.NoError(t, manager.Close())
+		assert.Equal(t, before, countLive(), "closed managers should not stay registered for atexit")
+	}
+}
+
+func TestManagerErrorHook(t *testing.T) {
+	errs := make(chan error, 1)
+	var gotName string
+	var gotArgs any
+	manager := NewManager(WithErrorHook(func(eventName string, args any, err error) {
+		gotName, gotArgs = eventName, args
+		errs <- err
+	}))
+	defer manager.Close()
+
+	boom := errors.New("boom")
+	manager.RegisterAsync("panic.#", func(args any) {
+		panic(boom)
+	})
+	var after int32
+	manager.RegisterAsync("after", func(args any) {
+		atomic.AddInt32(&after, 1)
+	})
+
+	manager.Emit("panic.event", 1)
+
+	select {
+	case err := <-errs:
+		var panicErr *routine.PanicError
+		require.True(t, errors.As(err, &panicErr))
+		assert.Equal(t, boom, panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack)
+		assert.True(t, errors.Is(err, boom))
+	case <-time.After(time.Second):
+		t.Fatal("error hook should be called")
+	}
+	assert.Equal(t, "panic.event", gotName)
+	assert.Equal(t, 1, gotArgs)
+
+	// worker 在 panic 后继续工作
+	manager.Emit("after", nil)
+	require.NoError(t, manager.Drain(context.Background()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&after))
+
+	// 移除 hook 后不再回调
+	manager.SetErrorHook(nil)
+	manager.Emit("panic.event", 2)
+	require.NoError(t, manager.Drain(context.Background()))
+	assert.Empty(t, errs)
+}
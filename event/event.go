@@ -1,53 +1,201 @@
 package event
 
 import (
-	"github.com/lazygophers/utils/routine"
-	"github.com/lazygophers/utils/runtime"
+	"context"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+
+	"github.com/lazygophers/utils/xerror"
 )
 
 var defaultManager = NewManager()
 
 type EventHandler func(args any)
 
+// StoppableHandler 是可以阻止事件继续传播的同步处理器，返回 true 时后续处理器不再执行
+type StoppableHandler func(args any) (stop bool)
+
+// HandlerOption 配置单个处理器
+type HandlerOption func(*eventItem)
+
+// WithPriority 设置处理器优先级，数值越大越先执行，默认 0；同优先级按注册顺序执行
+func WithPriority(priority int) HandlerOption {
+	return func(item *eventItem) {
+		item.priority = priority
+	}
+}
+
+// Once 使处理器只执行一次，首次触发后自动注销
+func Once() HandlerOption {
+	return func(item *eventItem) {
+		item.once = true
+	}
+}
+
 type eventItem struct {
-	handler EventHandler
+	eventName  string
+	handler    EventHandler
+	stoppable  StoppableHandler
+	ctxHandler func(ctx context.Context, args any) error
+
+	async    bool
+	priority int
+	once     bool
+
+	// spill 为 OverflowSpill 下该异步处理器的溢出队列
+	spill *spillQueue
 
-	async bool
+	seq     uint64
+	fired   int32
+	removed int32
+}
+
+// call 执行同步处理器，返回是否阻止后续处理器及处理器返回的错误
+func (p *eventItem) call(ctx context.Context, args any) (bool, error) {
+	switch {
+	case p.ctxHandler != nil:
+		return false, p.ctxHandler(ctx, args)
+	case p.stoppable != nil:
+		return p.stoppable(args), nil
+	default:
+		p.handler(args)
+		return false, nil
+	}
 }
 
 type Manager struct {
 	eventMux sync.RWMutex
 	events   map[string][]*eventItem
+	// patterns 记录含通配段的事件名及其分段，Emit 时逐一匹配
+	patterns map[string][]string
+	seq      uint64
+
+	workers   int
+	queueSize int
+	overflow  OverflowPolicy
+	errorHook atomic.Value // *ErrorHook
+	dropped   uint64
+
 	c        chan *emitItem
+	quit     chan struct{}
+	quitOnce sync.Once
+
+	closeMux sync.RWMutex // 保护 closed，投递异步事件时持读锁
+	closed   bool
+
+	pendingMux sync.Mutex
+	pending    int
+	idle       chan struct{} // pending 归零时关闭
+}
+
+// Subscription 是 Register 系列返回的订阅句柄
+type Subscription struct {
+	manager *Manager
+	item    *eventItem
+}
+
+// Unsubscribe 注销处理器，可重复调用；正在进行中的 Emit 也不会再执行该处理器
+func (s *Subscription) Unsubscribe() {
+	s.manager.unregister(s.item)
+}
+
+// EventName 返回注册时使用的事件名（可能包含通配段）
+func (s *Subscription) EventName() string {
+	return s.item.eventName
 }
 
-func (p *Manager) register(eventName string, item *eventItem) {
+func (p *Manager) register(eventName string, item *eventItem, opts []HandlerOption) *Subscription {
+	for _, opt := range opts {
+		opt(item)
+	}
+
 	p.eventMux.Lock()
 	defer p.eventMux.Unlock()
 
-	p.events[eventName] = append(p.events[eventName], item)
+	p.seq++
+	item.seq = p.seq
+	item.eventName = eventName
+	if item.async && p.overflow == OverflowSpill {
+		item.spill = &spillQueue{}
+	}
+
+	// 按优先级插入并复制切片，保持每个事件名下的处理器有序，Emit 可直接遍历
+	items := p.events[eventName]
+	idx := sort.Search(len(items), func(i int) bool {
+		return items[i].priority < item.priority
+	})
+	sorted := make([]*eventItem, 0, len(items)+1)
+	sorted = append(sorted, items[:idx]...)
+	sorted = append(sorted, item)
+	sorted = append(sorted, items[idx:]...)
+	p.events[eventName] = sorted
+
+	if isPattern(eventName) {
+		p.patterns[eventName] = strings.Split(eventName, topicSeparator)
+	}
+
+	return &Subscription{manager: p, item: item}
+}
+
+func (p *Manager) unregister(item *eventItem) {
+	if !atomic.CompareAndSwapInt32(&item.removed, 0, 1) {
+		return
+	}
+
+	p.eventMux.Lock()
+	defer p.eventMux.Unlock()
+
+	eventName := item.eventName
+
+	// 复制而非原地删除：Emit 可能正在锁外遍历旧切片
+	items := p.events[eventName]
+	kept := make([]*eventItem, 0, len(items))
+	for _, v := range items {
+		if v != item {
+			kept = append(kept, v)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(p.events, eventName)
+		delete(p.patterns, eventName)
+		return
+	}
+	p.events[eventName] = kept
 }
 
-func Register(eventName string, handler EventHandler) {
-	defaultManager.Register(eventName, handler)
+func Register(eventName string, handler EventHandler, opts ...HandlerOption) *Subscription {
+	return defaultManager.Register(eventName, handler, opts...)
 }
 
-func (p *Manager) Register(eventName string, handler EventHandler) {
-	p.register(eventName, &eventItem{
+func (p *Manager) Register(eventName string, handler EventHandler, opts ...HandlerOption) *Subscription {
+	return p.register(eventName, &eventItem{
 		handler: handler,
-	})
+	}, opts)
 }
 
-func RegisterAsync(eventName string, handler EventHandler) {
-	defaultManager.RegisterAsync(eventName, handler)
+func RegisterAsync(eventName string, handler EventHandler, opts ...HandlerOption) *Subscription {
+	return defaultManager.RegisterAsync(eventName, handler, opts...)
 }
 
-func (p *Manager) RegisterAsync(eventName string, handler EventHandler) {
-	p.register(eventName, &eventItem{
+func (p *Manager) RegisterAsync(eventName string, handler EventHandler, opts ...HandlerOption) *Subscription {
+	return p.register(eventName, &eventItem{
 		handler: handler,
 		async:   true,
-	})
+	}, opts)
+}
+
+// RegisterStoppable 注册可阻止传播的同步处理器，handler 返回 true 时优先级更低的处理器不再执行
+func RegisterStoppable(eventName string, handler StoppableHandler, opts ...HandlerOption) *Subscription {
+	return defaultManager.RegisterStoppable(eventName, handler, opts...)
+}
+
+func (p *Manager) RegisterStoppable(eventName string, handler StoppableHandler, opts ...HandlerOption) *Subscription {
+	return p.register(eventName, &eventItem{
+		stoppable: handler,
+	}, opts)
 }
 
 func (p *Manager) getItems(eventName string) []*eventItem {
@@ -57,48 +205,118 @@ func (p *Manager) getItems(eventName string) []*eventItem {
 	return p.events[eventName]
 }
 
+// matchItems 返回匹配 eventName 的全部处理器（精确名 + 通配），按优先级与注册顺序排序
+func (p *Manager) matchItems(eventName string) []*eventItem {
+	p.eventMux.RLock()
+	defer p.eventMux.RUnlock()
+
+	items := p.events[eventName]
+	if len(p.patterns) == 0 {
+		// 同一事件名下的处理器在注册时已排好序
+		return items
+	}
+
+	var topic []string
+	matched := false
+	for pattern, segments := range p.patterns {
+		if pattern == eventName {
+			continue
+		}
+		if topic == nil {
+			topic = strings.Split(eventName, topicSeparator)
+		}
+		if matchTopic(segments, topic) {
+			if !matched {
+				items = append([]*eventItem(nil), items...)
+				matched = true
+			}
+			items = append(items, p.events[pattern]...)
+		}
+	}
+
+	if matched {
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].priority != items[j].priority {
+				return items[i].priority > items[j].priority
+			}
+			return items[i].seq < items[j].seq
+		})
+	}
+	return items
+}
+
 func Emit(eventName string, args any) {
 	defaultManager.Emit(eventName, args)
 }
 
 type emitItem struct {
-	handler EventHandler
-	args    any
+	ctx        context.Context
+	eventName  string
+	handler    EventHandler
+	ctxHandler func(ctx context.Context, args any) error
+	args       any
+	onError    ErrorHook
 }
 
-func (p *emitItem) do() {
-	defer runtime.CachePanic()
-
-	p.handler(p.args)
+func (p *Manager) Emit(eventName string, args any) {
+	err := p.emit(context.Background(), eventName, args)
+	if err == nil {
+		return
+	}
+	if hook := p.loadErrorHook(); hook != nil {
+		hook(eventName, args, err)
+	}
 }
 
-func (p *Manager) Emit(eventName string, args any) {
-	for _, event := range p.getItems(eventName) {
-		if event.async {
-			p.c <- &emitItem{
-				handler: event.handler,
-				args:    args,
+// emit 分发事件，返回同步处理器的错误（经 xerror.Join 合并）
+func (p *Manager) emit(ctx context.Context, eventName string, args any) error {
+	var errs []error
+	for _, event := range p.matchItems(eventName) {
+		if atomic.LoadInt32(&event.removed) == 1 {
+			continue
+		}
+
+		if event.once {
+			if !atomic.CompareAndSwapInt32(&event.fired, 0, 1) {
+				continue
 			}
+			p.unregister(event)
+		}
+
+		if event.async {
+			p.dispatch(ctx, event, eventName, args)
 			continue
 		}
 
-		event.handler(args)
+		stop, err := event.call(ctx, args)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if stop {
+			break
+		}
 	}
+	return xerror.Join(errs...)
 }
 
-func NewManager() *Manager {
+// NewManager 创建 Manager 并启动异步消费协程，进程退出时（atexit）会尽量处理完已投递的异步事件
+func NewManager(opts ...Option) *Manager {
 	p := &Manager{
-		events: make(map[string][]*eventItem),
+		events:   make(map[string][]*eventItem),
+		patterns: make(map[string][]string),
+
+		workers:   defaultWorkers,
+		queueSize: defaultQueueSize,
 
-		c: make(chan *emitItem, 10),
+		quit: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	p.c = make(chan *emitItem, p.queueSize)
 
-	routine.GoWithRecover(func() (err error) {
-		for item := range p.c {
-			item.do()
-		}
-		return nil
-	})
+	p.startWorkers()
+	p.registerAtExit()
 
 	return p
 }
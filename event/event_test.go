@@ -498,3 +498,163 @@ func TestManagerStructFields(t *testing.T) {
 	items := manager.getItems("any-event")
 	assert.Nil(t, items, "getItems should return nil for unregistered events")
 }
+
+func TestSubscriptionUnsubscribe(t *testing.T) {
+	manager := NewManager()
+	defer close(manager.c)
+
+	var count int
+	sub := manager.Register("unsub-event", func(args any) {
+		count++
+	})
+	assert.Equal(t, "unsub-event", sub.EventName())
+
+	manager.Emit("unsub-event", nil)
+	sub.Unsubscribe()
+	manager.Emit("unsub-event", nil)
+
+	assert.Equal(t, 1, count)
+	assert.Nil(t, manager.getItems("unsub-event"), "Empty event should be removed")
+
+	// 重复注销无副作用
+	sub.Unsubscribe()
+}
+
+func TestUnsubscribeDuringEmit(t *testing.T) {
+	manager := NewManager()
+	defer close(manager.c)
+
+	var calls []string
+	var second *Subscription
+	manager.Register("during", func(args any) {
+		calls = append(calls, "first")
+		second.Unsubscribe()
+	})
+	second = manager.Register("during", func(args any) {
+		calls = append(calls, "second")
+	})
+
+	manager.Emit("during", nil)
+	assert.Equal(t, []string{"first"}, calls)
+	assert.Len(t, manager.getItems("during"), 1)
+}
+
+func TestWildcardTopics(t *testing.T) {
+	manager := NewManager()
+	defer close(manager.c)
+
+	var calls []string
+	record := func(name string) EventHandler {
+		return func(args any) {
+			calls = append(calls, name+":"+args.(string))
+		}
+	}
+
+	manager.Register("order.created", record("exact"))
+	manager.Register("order.*", record("one"))
+	sub := manager.Register("order.#", record("multi"))
+
+	manager.Emit("order.created", "a")
+	manager.Emit("order.item.added", "b")
+	manager.Emit("order", "c")
+	manager.Emit("user.created", "d")
+
+	assert.Equal(t, []string{
+		"exact:a", "one:a", "multi:a",
+		"multi:b",
+		"multi:c",
+	}, calls)
+
+	sub.Unsubscribe()
+	calls = nil
+	manager.Emit("order.item.added", "e")
+	assert.Empty(t, calls)
+	assert.NotContains(t, manager.patterns, "order.#")
+}
+
+func TestHandlerPriority(t *testing.T) {
+	manager := NewManager()
+	defer close(manager.c)
+
+	var calls []string
+	record := func(name string) EventHandler {
+		return func(args any) {
+			calls = append(calls, name)
+		}
+	}
+
+	manager.Register("prio", record("default-1"))
+	manager.Register("prio", record("low"), WithPriority(-1))
+	manager.Register("prio.#", record("high-wildcard"), WithPriority(10))
+	manager.Register("prio", record("high"), WithPriority(10))
+	manager.Register("prio", record("default-2"))
+
+	manager.Emit("prio", nil)
+	assert.Equal(t, []string{"high-wildcard", "high", "default-1", "default-2", "low"}, calls)
+}
+
+func TestOnceHandler(t *testing.T) {
+	manager := NewManager()
+	defer close(manager.c)
+
+	var onceCalls, wildcardCalls int32
+	manager.Register("once", func(args any) {
+		atomic.AddInt32(&onceCalls, 1)
+	}, Once())
+	manager.Register("once.#", func(args any) {
+		atomic.AddInt32(&wildcardCalls, 1)
+	}, Once())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.Emit("once", nil)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&onceCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&wildcardCalls))
+	assert.Nil(t, manager.getItems("once"))
+	assert.Nil(t, manager.getItems("once.#"))
+}
+
+func TestStoppableHandler(t *testing.T) {
+	manager := NewManager()
+	defer close(manager.c)
+
+	var calls []string
+	manager.Register("stop", func(args any) {
+		calls = append(calls, "after")
+	})
+	manager.RegisterStoppable("stop", func(args any) bool {
+		calls = append(calls, "guard")
+		return args.(bool)
+	}, WithPriority(1))
+
+	manager.Emit("stop", false)
+	assert.Equal(t, []string{"guard", "after"}, calls)
+
+	calls = nil
+	manager.Emit("stop", true)
+	assert.Equal(t, []string{"guard"}, calls)
+}
+
+func TestDefaultManagerSubscription(t *testing.T) {
+	var count int32
+	sub := Register("default-subscription", func(args any) {
+		atomic.AddInt32(&count, 1)
+	}, Once())
+	stop := RegisterStoppable("default-subscription", func(args any) bool {
+		return true
+	}, WithPriority(-1))
+	defer stop.Unsubscribe()
+
+	Emit("default-subscription", nil)
+	Emit("default-subscription", nil)
+	sub.Unsubscribe()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
@@ -0,0 +1,734 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lazygophers/utils/json"
+	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/runtime"
+	"github.com/lazygophers/utils/xerror"
+)
+
+const (
+	segmentExt  = ".log"
+	offsetsFile = "offsets.json"
+
+	defaultSegmentBytes   = 16 << 20
+	defaultRetryInterval  = time.Second
+	defaultCommitInterval = time.Second
+)
+
+var (
+	// ErrOutboxClosed 在 Outbox 关闭后写入或订阅时返回
+	ErrOutboxClosed = errors.New("event: outbox closed")
+	// ErrSubscriberExists 在同名持久订阅者已在运行时返回
+	ErrSubscriberExists = errors.New("event: durable subscriber already exists")
+)
+
+// OutboxConfig 是 Outbox 的配置
+type OutboxConfig struct {
+	// Dir 日志目录，不存在时自动创建
+	Dir string
+	// SegmentBytes 单个分段文件的大小上限，超过后切换到新分段，默认 16MB
+	SegmentBytes int64
+	// SyncWrites 每次写入后 fsync，进程崩溃与断电都不丢事件；关闭时仅保证进程崩溃不丢
+	SyncWrites bool
+	// RetryInterval 持久处理器返回错误后的重试间隔，默认 1 秒
+	RetryInterval time.Duration
+	// CommitInterval 已确认 offset 写入 offsets 文件的最长延迟，间隔内的多次确认合并为一次写入，默认 1 秒。
+	// 进程崩溃时最多重放这段时间内已确认的事件；Unsubscribe 与 Close 会立即写入
+	CommitInterval time.Duration
+}
+
+// Record 是写入日志的一条事件
+type Record struct {
+	Offset  uint64             `json:"offset"`
+	Event   string             `json:"event"`
+	Payload stdjson.RawMessage `json:"payload,omitempty"`
+	Time    time.Time          `json:"time"`
+}
+
+// Decode 把事件载荷解码到 v
+func (r *Record) Decode(v any) error {
+	return json.Unmarshal(r.Payload, v)
+}
+
+// DurableHandler 是持久订阅者的处理器，返回 nil 表示确认（推进 offset），返回错误则按 RetryInterval 重试
+type DurableHandler func(ctx context.Context, record *Record) error
+
+type durableSub struct {
+	name      string
+	eventName string
+	pattern   []string
+	handler   DurableHandler
+
+	notify chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// 读取位置：下一条未读记录所在分段的起始 offset 与文件内字节位置，只由投递协程访问
+	segment uint64
+	pos     int64
+}
+
+// Outbox 是基于本地分段日志的持久事件模式：Emit 先把事件追加到日志再分发给 Manager，
+// 持久订阅者按名称记录已确认的 offset，重启后从未确认的位置重放，投递语义为至少一次。
+// 所有已知订阅者都确认过的旧分段会被压缩删除。
+type Outbox struct {
+	manager *Manager
+	config  OutboxConfig
+
+	mu         sync.Mutex
+	segments   []uint64 // 各分段的起始 offset，升序
+	active     *os.File
+	activeSize int64
+	next       uint64
+	offsets    map[string]uint64 // 订阅者下一条待确认的 offset
+	subs       map[string]*durableSub
+	closed     bool
+	flushing   bool // 已安排延迟写入 offsets 文件
+
+	offsetMu sync.Mutex // 串行化 offsets 文件写入
+}
+
+// OpenOutbox 打开（或创建）config.Dir 下的日志，截断崩溃时写了一半的尾部记录，
+// Emit 的事件在持久化后同时分发给 manager 上的普通处理器
+func OpenOutbox(manager *Manager, config OutboxConfig) (*Outbox, error) {
+	if config.Dir == "" {
+		return nil, errors.New("event: outbox dir is required")
+	}
+	if config.SegmentBytes <= 0 {
+		config.SegmentBytes = defaultSegmentBytes
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultRetryInterval
+	}
+	if config.CommitInterval <= 0 {
+		config.CommitInterval = defaultCommitInterval
+	}
+	if manager == nil {
+		manager = defaultManager
+	}
+
+	err := os.MkdirAll(config.Dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Outbox{
+		manager: manager,
+		config:  config,
+		offsets: make(map[string]uint64),
+		subs:    make(map[string]*durableSub),
+	}
+
+	err = o.loadSegments()
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.loadOffsets()
+	if err != nil {
+		o.active.Close()
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (o *Outbox) segmentPath(base uint64) string {
+	return filepath.Join(o.config.Dir, fmt.Sprintf("%020d%s", base, segmentExt))
+}
+
+func (o *Outbox) loadSegments() error {
+	entries, err := os.ReadDir(o.config.Dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		o.segments = append(o.segments, base)
+	}
+	sort.Slice(o.segments, func(i, j int) bool {
+		return o.segments[i] < o.segments[j]
+	})
+
+	if len(o.segments) == 0 {
+		return o.openSegment(0)
+	}
+
+	last := o.segments[len(o.segments)-1]
+	size, next, err := recoverSegment(o.segmentPath(last), last)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(o.segmentPath(last), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	o.active, o.activeSize, o.next = file, size, next
+	return nil
+}
+
+// recoverSegment 扫描分段，截断最后一条完整记录之后的残留数据，返回有效长度与下一个 offset
+func recoverSegment(path string, base uint64) (int64, uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	var size int64
+	next := base
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		var record Record
+		if json.Unmarshal(line, &record) != nil {
+			break
+		}
+		size += int64(len(line))
+		next = record.Offset + 1
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	if info.Size() > size {
+		err = os.Truncate(path, size)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return size, next, nil
+}
+
+func (o *Outbox) openSegment(base uint64) error {
+	file, err := os.OpenFile(o.segmentPath(base), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	o.segments = append(o.segments, base)
+	o.active, o.activeSize, o.next = file, 0, base
+	return nil
+}
+
+func (o *Outbox) loadOffsets() error {
+	err := json.UnmarshalFromFile(filepath.Join(o.config.Dir, offsetsFile), &o.offsets)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if o.offsets == nil {
+		o.offsets = make(map[string]uint64)
+	}
+	return nil
+}
+
+// saveOffsets 先写临时文件再 rename，避免崩溃时留下不完整的 offsets 文件
+func (o *Outbox) saveOffsets() error {
+	o.offsetMu.Lock()
+	defer o.offsetMu.Unlock()
+
+	o.mu.Lock()
+	offsets := make(map[string]uint64, len(o.offsets))
+	for name, offset := range o.offsets {
+		offsets[name] = offset
+	}
+	o.mu.Unlock()
+
+	path := filepath.Join(o.config.Dir, offsetsFile)
+	err := json.MarshalToFile(path+".tmp", offsets)
+	if err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Emit 把事件追加到日志（payload 经 json 编码），成功后唤醒持久订阅者并分发给 Manager 上的普通处理器
+func (o *Outbox) Emit(eventName string, args any) error {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return ErrOutboxClosed
+	}
+
+	line, err := json.Marshal(&Record{
+		Offset:  o.next,
+		Event:   eventName,
+		Payload: payload,
+		Time:    time.Now(),
+	})
+	if err != nil {
+		o.mu.Unlock()
+		return err
+	}
+	line = append(line, '\n')
+
+	err = o.append(line)
+	if err != nil {
+		o.mu.Unlock()
+		return err
+	}
+
+	subs := make([]*durableSub, 0, len(o.subs))
+	for _, sub := range o.subs {
+		subs = append(subs, sub)
+	}
+	o.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.wake()
+	}
+
+	o.manager.Emit(eventName, args)
+	return nil
+}
+
+// append 在持有 mu 时写入一行，写满后切换分段
+func (o *Outbox) append(line []byte) error {
+	_, err := o.active.Write(line)
+	if err == nil && o.config.SyncWrites {
+		err = o.active.Sync()
+	}
+	if err != nil {
+		// 丢弃写了一半的数据，避免与下一条记录粘连
+		_ = o.active.Truncate(o.activeSize)
+		return err
+	}
+	o.activeSize += int64(len(line))
+	o.next++
+
+	if o.activeSize < o.config.SegmentBytes {
+		return nil
+	}
+
+	err = o.active.Close()
+	if err != nil {
+		return err
+	}
+	err = o.openSegment(o.next)
+	if err != nil {
+		return err
+	}
+
+	// 压缩失败不影响本次写入，下次切换分段或调用 Compact 时重试
+	_ = o.compactLocked()
+	return nil
+}
+
+// Subscribe 注册名为 name 的持久订阅者，eventName 可以是通配事件名。
+// 从该名称上次确认的 offset 开始投递；新名称从保留的最早事件开始，
+// 处理器在单独的协程中按 offset 顺序执行
+func (o *Outbox) Subscribe(name, eventName string, handler DurableHandler) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return ErrOutboxClosed
+	}
+	if _, ok := o.subs[name]; ok {
+		return ErrSubscriberExists
+	}
+
+	start, ok := o.offsets[name]
+	if !ok {
+		start = o.segments[0]
+		o.offsets[name] = start
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &durableSub{
+		name:      name,
+		eventName: eventName,
+		pattern:   strings.Split(eventName, topicSeparator),
+		handler:   handler,
+		notify:    make(chan struct{}, 1),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	sub.segment = o.segmentOf(start)
+	o.subs[name] = sub
+
+	routine.GoWithRecover(func() error {
+		defer close(sub.done)
+		o.deliver(ctx, sub, start)
+		return nil
+	})
+	return nil
+}
+
+// Unsubscribe 停止投递并等待执行中的处理器返回，已确认的 offset 立即写入并保留，
+// 旧分段仍会为该订阅者保留，不再需要时用 RemoveSubscriber
+func (o *Outbox) Unsubscribe(name string) {
+	if !o.stop(name) {
+		return
+	}
+
+	err := o.saveOffsets()
+	if err != nil {
+		o.report(name, nil, err)
+	}
+}
+
+// RemoveSubscriber 停止投递并删除该订阅者的 offset，不再为其保留分段
+func (o *Outbox) RemoveSubscriber(name string) error {
+	o.stop(name)
+
+	o.mu.Lock()
+	delete(o.offsets, name)
+	o.mu.Unlock()
+
+	return o.saveOffsets()
+}
+
+// stop 停止订阅者的投递协程并等待其退出，订阅者不存在时返回 false
+func (o *Outbox) stop(name string) bool {
+	o.mu.Lock()
+	sub := o.subs[name]
+	delete(o.subs, name)
+	o.mu.Unlock()
+
+	if sub == nil {
+		return false
+	}
+	sub.cancel()
+	<-sub.done
+	return true
+}
+
+// Offset 返回订阅者下一条待确认事件的 offset
+func (o *Outbox) Offset(name string) (uint64, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	offset, ok := o.offsets[name]
+	return offset, ok
+}
+
+// call 执行处理器，panic 转换为 *routine.PanicError 按失败重试
+func (s *durableSub) call(ctx context.Context, record *Record) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &routine.PanicError{Value: r, Stack: runtime.GetStack()}
+		}
+	}()
+	return s.handler(ctx, record)
+}
+
+func (s *durableSub) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// deliver 是订阅者的投递循环：从读取位置继续读出新记录，匹配的逐条执行直到成功，
+// 每条成功后推进 offset，由 commit 合并写入 offsets 文件
+func (o *Outbox) deliver(ctx context.Context, sub *durableSub, offset uint64) {
+	for {
+		records, err := o.read(sub, offset)
+		if err != nil {
+			o.report(sub.eventName, nil, err)
+			if !o.sleep(ctx) {
+				return
+			}
+			continue
+		}
+
+		for _, record := range records {
+			if !matchTopic(sub.pattern, strings.Split(record.Event, topicSeparator)) {
+				offset = record.Offset + 1
+				continue
+			}
+
+			for {
+				if ctx.Err() != nil {
+					o.commit(sub.name, offset)
+					return
+				}
+				err = sub.call(ctx, record)
+				if err == nil {
+					break
+				}
+				o.report(record.Event, record, err)
+				if !o.sleep(ctx) {
+					o.commit(sub.name, offset)
+					return
+				}
+			}
+
+			offset = record.Offset + 1
+			o.commit(sub.name, offset)
+		}
+
+		if len(records) > 0 {
+			// 跳过的记录也要推进 offset，以便压缩
+			o.commit(sub.name, offset)
+			continue
+		}
+
+		select {
+		case <-sub.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (o *Outbox) sleep(ctx context.Context) bool {
+	timer := time.NewTimer(o.config.RetryInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (o *Outbox) report(eventName string, record *Record, err error) {
+	if hook := o.manager.loadErrorHook(); hook != nil {
+		hook(eventName, record, err)
+	}
+}
+
+// commit 推进订阅者的 offset，并在 CommitInterval 后合并写入 offsets 文件
+func (o *Outbox) commit(name string, offset uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.offsets[name] >= offset {
+		return
+	}
+	o.offsets[name] = offset
+
+	if o.flushing || o.closed {
+		return
+	}
+	o.flushing = true
+	time.AfterFunc(o.config.CommitInterval, o.flush)
+}
+
+// flush 写入延迟的 offset；Close 之后由 Close 负责写入
+func (o *Outbox) flush() {
+	o.mu.Lock()
+	o.flushing = false
+	closed := o.closed
+	o.mu.Unlock()
+	if closed {
+		return
+	}
+
+	err := o.saveOffsets()
+	if err != nil {
+		o.report(offsetsFile, nil, err)
+	}
+}
+
+// segmentOf 返回 offset 所在分段的起始 offset，已被压缩时返回最早的分段；调用方持有 mu
+func (o *Outbox) segmentOf(offset uint64) uint64 {
+	idx := sort.Search(len(o.segments), func(i int) bool {
+		return o.segments[i] > offset
+	}) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return o.segments[idx]
+}
+
+// read 从订阅者的读取位置读出 offset 及之后的已写入记录，并推进读取位置。
+// 当前分段已读完且不是写入中的分段时切换到下一个分段；
+// 读取位置所在分段已被压缩时按 offset 重新定位
+func (o *Outbox) read(sub *durableSub, offset uint64) ([]*Record, error) {
+	for {
+		o.mu.Lock()
+		if offset >= o.next {
+			o.mu.Unlock()
+			return nil, nil
+		}
+		idx := sort.Search(len(o.segments), func(i int) bool {
+			return o.segments[i] >= sub.segment
+		})
+		if idx == len(o.segments) || o.segments[idx] != sub.segment {
+			sub.segment, sub.pos = o.segmentOf(offset), 0
+			o.mu.Unlock()
+			continue
+		}
+		limit := int64(-1)
+		next := uint64(0)
+		if idx == len(o.segments)-1 {
+			limit = o.activeSize
+		} else {
+			next = o.segments[idx+1]
+		}
+		o.mu.Unlock()
+
+		records, pos, err := readSegment(o.segmentPath(sub.segment), sub.segment, sub.pos, limit, offset)
+		if os.IsNotExist(err) && o.relocate(sub, offset) {
+			// 读取期间被压缩
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sub.pos = pos
+
+		if len(records) > 0 || limit >= 0 {
+			return records, nil
+		}
+		sub.segment, sub.pos = next, 0
+	}
+}
+
+// relocate 按 offset 重新定位读取位置，位置没有变化时返回 false
+func (o *Outbox) relocate(sub *durableSub, offset uint64) bool {
+	o.mu.Lock()
+	segment := o.segmentOf(offset)
+	o.mu.Unlock()
+
+	if segment == sub.segment {
+		return false
+	}
+	sub.segment, sub.pos = segment, 0
+	return true
+}
+
+// readSegment 从分段文件的 pos 处读到 limit（-1 表示读到文件末尾），返回 offset 及之后的记录与读完后的位置
+func readSegment(path string, base uint64, pos, limit int64, offset uint64) ([]*Record, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	if pos > 0 {
+		_, err = file.Seek(pos, io.SeekStart)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var reader io.Reader = file
+	if limit >= 0 {
+		reader = io.LimitReader(file, limit-pos)
+	}
+
+	var records []*Record
+	buf := bufio.NewReader(reader)
+	for {
+		line, err := buf.ReadBytes('\n')
+		if err == io.EOF {
+			// 不完整的尾部记录留到下次读取
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		pos += int64(len(line))
+
+		record := &Record{}
+		err = json.Unmarshal(bytes.TrimSpace(line), record)
+		if err != nil {
+			return nil, 0, fmt.Errorf("event: corrupt record in segment %d: %w", base, err)
+		}
+		if record.Offset >= offset {
+			records = append(records, record)
+		}
+	}
+	return records, pos, nil
+}
+
+// Compact 删除所有已知订阅者都已确认的旧分段，返回删除的分段数量。
+// 没有任何订阅者时不删除，避免之后注册的订阅者丢失事件
+func (o *Outbox) Compact() (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	before := len(o.segments)
+	err := o.compactLocked()
+	return before - len(o.segments), err
+}
+
+func (o *Outbox) compactLocked() error {
+	if len(o.offsets) == 0 {
+		return nil
+	}
+
+	oldest := o.next
+	for _, offset := range o.offsets {
+		if offset < oldest {
+			oldest = offset
+		}
+	}
+
+	// 当前写入的分段永不删除；分段 i 的记录都小于 segments[i+1]
+	removed := 0
+	for removed < len(o.segments)-1 && o.segments[removed+1] <= oldest {
+		err := os.Remove(o.segmentPath(o.segments[removed]))
+		if err != nil && !os.IsNotExist(err) {
+			o.segments = o.segments[removed:]
+			return err
+		}
+		removed++
+	}
+	o.segments = o.segments[removed:]
+	return nil
+}
+
+// Close 停止所有持久订阅者、保存 offset 并关闭日志文件，可重复调用
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	subs := o.subs
+	o.subs = make(map[string]*durableSub)
+	o.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.cancel()
+	}
+	for _, sub := range subs {
+		<-sub.done
+	}
+
+	return xerror.Join(o.saveOffsets(), o.active.Close())
+}
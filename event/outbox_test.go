@@ -0,0 +1,330 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/routine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditEvent struct {
+	User   string `json:"user"`
+	Action string `json:"action"`
+}
+
+// collector 记录持久处理器收到的事件
+type collector struct {
+	mu      sync.Mutex
+	records []*Record
+}
+
+func (c *collector) handle(ctx context.Context, record *Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records = append(c.records, record)
+	return nil
+}
+
+func (c *collector) offsets() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	offsets := make([]uint64, 0, len(c.records))
+	for _, record := range c.records {
+		offsets = append(offsets, record.Offset)
+	}
+	return offsets
+}
+
+func (c *collector) waitFor(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return len(c.offsets()) >= n
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func openTestOutbox(t *testing.T, dir string, config OutboxConfig) (*Outbox, *Manager) {
+	t.Helper()
+
+	manager := NewManager()
+	t.Cleanup(func() { manager.Close() })
+
+	config.Dir = dir
+	outbox, err := OpenOutbox(manager, config)
+	require.NoError(t, err)
+	return outbox, manager
+}
+
+func TestOutboxEmitAndDeliver(t *testing.T) {
+	outbox, manager := openTestOutbox(t, t.TempDir(), OutboxConfig{SyncWrites: true})
+	defer outbox.Close()
+
+	var live any
+	manager.Register("audit.login", func(args any) {
+		live = args
+	})
+
+	var c collector
+	require.NoError(t, outbox.Subscribe("auditor", "audit.#", c.handle))
+	assert.ErrorIs(t, outbox.Subscribe("auditor", "audit.#", c.handle), ErrSubscriberExists)
+
+	event := auditEvent{User: "alice", Action: "login"}
+	require.NoError(t, outbox.Emit("audit.login", event))
+	require.NoError(t, outbox.Emit("other.event", 1))
+	require.NoError(t, outbox.Emit("audit.logout", auditEvent{User: "alice", Action: "logout"}))
+
+	assert.Equal(t, event, live, "Regular handlers should receive the original args")
+
+	c.waitFor(t, 2)
+	assert.Equal(t, []uint64{0, 2}, c.offsets())
+
+	var decoded auditEvent
+	require.NoError(t, c.records[0].Decode(&decoded))
+	assert.Equal(t, event, decoded)
+	assert.Equal(t, "audit.login", c.records[0].Event)
+
+	require.Eventually(t, func() bool {
+		offset, _ := outbox.Offset("auditor")
+		return offset == 3
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestOutboxReplayAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	outbox, _ := openTestOutbox(t, dir, OutboxConfig{})
+	for i := 0; i < 3; i++ {
+		require.NoError(t, outbox.Emit("audit.write", i))
+	}
+	require.NoError(t, outbox.Close())
+	assert.ErrorIs(t, outbox.Emit("audit.write", 3), ErrOutboxClosed)
+
+	// 新订阅者从保留的最早事件开始
+	outbox, _ = openTestOutbox(t, dir, OutboxConfig{})
+	var first collector
+	require.NoError(t, outbox.Subscribe("auditor", "audit.*", first.handle))
+	first.waitFor(t, 3)
+	assert.Equal(t, []uint64{0, 1, 2}, first.offsets())
+	require.NoError(t, outbox.Close())
+
+	// 已确认的事件不再重放，新事件的 offset 接续
+	outbox, _ = openTestOutbox(t, dir, OutboxConfig{})
+	defer outbox.Close()
+	require.NoError(t, outbox.Emit("audit.write", 3))
+
+	var second collector
+	require.NoError(t, outbox.Subscribe("auditor", "audit.*", second.handle))
+	second.waitFor(t, 1)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, []uint64{3}, second.offsets())
+}
+
+func TestOutboxReadResumesFromPosition(t *testing.T) {
+	dir := t.TempDir()
+	outbox, _ := openTestOutbox(t, dir, OutboxConfig{})
+	defer outbox.Close()
+
+	var c collector
+	require.NoError(t, outbox.Subscribe("auditor", "audit.*", c.handle))
+	require.NoError(t, outbox.Emit("audit.write", 0))
+	c.waitFor(t, 1)
+
+	// 已读过的记录被破坏也不影响后续投递：每次唤醒只读新写入的部分
+	path := outbox.segmentPath(0)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for i := range data[:len(data)-1] {
+		data[i] = 'x'
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, outbox.Emit("audit.write", i))
+	}
+	c.waitFor(t, 4)
+	assert.Equal(t, []uint64{0, 1, 2, 3}, c.offsets())
+}
+
+func TestOutboxCommitInterval(t *testing.T) {
+	dir := t.TempDir()
+	outbox, _ := openTestOutbox(t, dir, OutboxConfig{CommitInterval: time.Hour})
+	defer outbox.Close()
+
+	var c collector
+	require.NoError(t, outbox.Subscribe("auditor", "audit.*", c.handle))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, outbox.Emit("audit.write", i))
+	}
+	c.waitFor(t, 10)
+	require.Eventually(t, func() bool {
+		offset, _ := outbox.Offset("auditor")
+		return offset == 10
+	}, time.Second, 5*time.Millisecond)
+
+	// 间隔内的确认只更新内存，Unsubscribe 时立即写入
+	path := filepath.Join(dir, offsetsFile)
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "offsets should not be written before the commit interval")
+
+	outbox.Unsubscribe("auditor")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"auditor":10}`, string(data))
+}
+
+func TestOutboxRetryUntilAcked(t *testing.T) {
+	errs := make(chan error, 10)
+	manager := NewManager(WithErrorHook(func(eventName string, args any, err error) {
+		errs <- err
+	}))
+	defer manager.Close()
+
+	outbox, err := OpenOutbox(manager, OutboxConfig{Dir: t.TempDir(), RetryInterval: 5 * time.Millisecond})
+	require.NoError(t, err)
+	defer outbox.Close()
+
+	boom := errors.New("boom")
+	var attempts int32
+	var c collector
+	require.NoError(t, outbox.Subscribe("flaky", "job", func(ctx context.Context, record *Record) error {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			return boom
+		case 2:
+			panic("crash")
+		}
+		return c.handle(ctx, record)
+	}))
+
+	require.NoError(t, outbox.Emit("job", "payload"))
+	c.waitFor(t, 1)
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.ErrorIs(t, <-errs, boom)
+	var panicErr *routine.PanicError
+	assert.ErrorAs(t, <-errs, &panicErr)
+}
+
+func TestOutboxUnsubscribeKeepsOffset(t *testing.T) {
+	outbox, _ := openTestOutbox(t, t.TempDir(), OutboxConfig{})
+	defer outbox.Close()
+
+	var c collector
+	require.NoError(t, outbox.Subscribe("auditor", "a", c.handle))
+	require.NoError(t, outbox.Emit("a", 1))
+	c.waitFor(t, 1)
+
+	outbox.Unsubscribe("auditor")
+	require.NoError(t, outbox.Emit("a", 2))
+
+	offset, ok := outbox.Offset("auditor")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), offset)
+
+	// 重新订阅后从确认位置继续
+	require.NoError(t, outbox.Subscribe("auditor", "a", c.handle))
+	c.waitFor(t, 2)
+	assert.Equal(t, []uint64{0, 1}, c.offsets())
+
+	require.NoError(t, outbox.RemoveSubscriber("auditor"))
+	_, ok = outbox.Offset("auditor")
+	assert.False(t, ok)
+}
+
+func TestOutboxTruncatesTornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	outbox, _ := openTestOutbox(t, dir, OutboxConfig{})
+	require.NoError(t, outbox.Emit("a", 1))
+	require.NoError(t, outbox.Close())
+
+	// 模拟崩溃时写了一半的记录
+	segment := filepath.Join(dir, "00000000000000000000.log")
+	file, err := os.OpenFile(segment, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"offset":1,"event":"a","pay`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	outbox, _ = openTestOutbox(t, dir, OutboxConfig{})
+	defer outbox.Close()
+	require.NoError(t, outbox.Emit("a", 2))
+
+	var c collector
+	require.NoError(t, outbox.Subscribe("reader", "a", c.handle))
+	c.waitFor(t, 2)
+
+	var payload int
+	require.NoError(t, c.records[1].Decode(&payload))
+	assert.Equal(t, []uint64{0, 1}, c.offsets())
+	assert.Equal(t, 2, payload)
+}
+
+func TestOutboxCompact(t *testing.T) {
+	dir := t.TempDir()
+
+	// 每条记录单独一个分段
+	outbox, _ := openTestOutbox(t, dir, OutboxConfig{SegmentBytes: 1})
+	defer outbox.Close()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, outbox.Emit("a", i))
+	}
+
+	// 没有订阅者时不压缩
+	removed, err := outbox.Compact()
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+
+	var fast, slow collector
+	require.NoError(t, outbox.Subscribe("fast", "a", fast.handle))
+	fast.waitFor(t, 5)
+
+	release := make(chan struct{})
+	require.NoError(t, outbox.Subscribe("slow", "a", func(ctx context.Context, record *Record) error {
+		if record.Offset >= 2 {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return slow.handle(ctx, record)
+	}))
+	slow.waitFor(t, 2)
+
+	// slow 只确认了 0、1
+	removed, err = outbox.Compact()
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	segments, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.NoError(t, err)
+	assert.Len(t, segments, 4, "segments 2..4 plus the empty active segment")
+
+	close(release)
+	slow.waitFor(t, 5)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, slow.offsets())
+
+	require.Eventually(t, func() bool {
+		offset, _ := outbox.Offset("slow")
+		return offset == 5
+	}, time.Second, 5*time.Millisecond)
+	removed, err = outbox.Compact()
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+}
+
+func TestOpenOutboxRequiresDir(t *testing.T) {
+	_, err := OpenOutbox(nil, OutboxConfig{})
+	assert.Error(t, err)
+}
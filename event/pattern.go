@@ -0,0 +1,50 @@
+package event
+
+import "strings"
+
+// 事件名按 "." 分段，注册时可使用通配段：
+//   - "*" 匹配恰好一段，如 "order.*" 匹配 "order.created"，不匹配 "order.item.added"
+//   - "#" 匹配零或多段，如 "order.#" 匹配 "order"、"order.created"、"order.item.added"
+const (
+	topicSeparator = "."
+	wildcardOne    = "*"
+	wildcardMulti  = "#"
+)
+
+// isPattern 判断事件名是否包含通配段
+func isPattern(eventName string) bool {
+	for _, segment := range strings.Split(eventName, topicSeparator) {
+		if segment == wildcardOne || segment == wildcardMulti {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTopic 判断分段后的 topic 是否匹配 pattern
+func matchTopic(pattern, topic []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case wildcardMulti:
+			// "#" 尝试吞掉 0..n 段
+			for i := 0; i <= len(topic); i++ {
+				if matchTopic(pattern[1:], topic[i:]) {
+					return true
+				}
+			}
+			return false
+
+		case wildcardOne:
+			if len(topic) == 0 {
+				return false
+			}
+
+		default:
+			if len(topic) == 0 || pattern[0] != topic[0] {
+				return false
+			}
+		}
+		pattern, topic = pattern[1:], topic[1:]
+	}
+	return len(topic) == 0
+}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPattern(t *testing.T) {
+	assert.True(t, isPattern("order.*"))
+	assert.True(t, isPattern("order.#"))
+	assert.True(t, isPattern("#"))
+	assert.False(t, isPattern("order.created"))
+	assert.False(t, isPattern("order.*x"))
+	assert.False(t, isPattern(""))
+}
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"order.*", "order.created", true},
+		{"order.*", "order.item.added", false},
+		{"order.*", "order", false},
+		{"order.#", "order", true},
+		{"order.#", "order.created", true},
+		{"order.#", "order.item.added", true},
+		{"order.#", "user.created", false},
+		{"*.created", "user.created", true},
+		{"*.created", "user.deleted", false},
+		{"#.added", "order.item.added", true},
+		{"#.added", "added", true},
+		{"order.#.added", "order.added", true},
+		{"order.#.added", "order.item.sku.added", true},
+		{"order.#.added", "order.item.removed", false},
+		{"#", "anything.at.all", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.topic, func(t *testing.T) {
+			got := matchTopic(strings.Split(tt.pattern, topicSeparator), strings.Split(tt.topic, topicSeparator))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
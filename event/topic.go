@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/lazygophers/utils/xerror"
+)
+
+// Topic 是类型安全的事件主题，底层复用 Manager：
+// 与 Register/Emit 共享同一个事件名空间，字符串订阅者收到的 args 即 Publish 的 T 值；
+// 经 Emit 触发且 args 类型与 T 不符时不调用处理器，类型错误交给 Manager 的 ErrorHook
+type Topic[T any] struct {
+	manager *Manager
+	name    string
+}
+
+// NewTopic 在 defaultManager 上创建主题
+func NewTopic[T any](name string) *Topic[T] {
+	return NewTopicOn[T](defaultManager, name)
+}
+
+// NewTopicOn 在指定 Manager 上创建主题
+func NewTopicOn[T any](manager *Manager, name string) *Topic[T] {
+	return &Topic[T]{
+		manager: manager,
+		name:    name,
+	}
+}
+
+// Name 返回主题对应的事件名
+func (p *Topic[T]) Name() string {
+	return p.name
+}
+
+// Publish 发布事件：同步处理器在当前协程按优先级执行，错误经 xerror.Join 合并返回；
+// 异步处理器入队执行，错误交给 Manager 的 ErrorHook。ctx 已结束时不分发并返回 xerror 超时/取消错误
+func (p *Topic[T]) Publish(ctx context.Context, value T) error {
+	if ctx.Err() != nil {
+		return xerror.FromContext(ctx)
+	}
+	return p.manager.emit(ctx, p.name, value)
+}
+
+// Subscribe 注册同步处理器，name 可以是通配事件名（如 "order.#"）
+func (p *Topic[T]) Subscribe(handler func(ctx context.Context, value T) error, opts ...HandlerOption) *Subscription {
+	return p.manager.register(p.name, &eventItem{
+		ctxHandler: typedHandler(handler),
+	}, opts)
+}
+
+// SubscribeAsync 注册异步处理器，执行时的 ctx 保留 Publish 的值但不继承其取消
+func (p *Topic[T]) SubscribeAsync(handler func(ctx context.Context, value T) error, opts ...HandlerOption) *Subscription {
+	return p.manager.register(p.name, &eventItem{
+		ctxHandler: typedHandler(handler),
+		async:      true,
+	}, opts)
+}
+
+func typedHandler[T any](handler func(ctx context.Context, value T) error) func(ctx context.Context, args any) error {
+	return func(ctx context.Context, args any) error {
+		value, ok := args.(T)
+		if !ok {
+			if args != nil {
+				return fmt.Errorf("event: payload type %T does not match topic type %v", args, reflect.TypeFor[T]())
+			}
+			// nil 视为 T 的零值
+		}
+		return handler(ctx, value)
+	}
+}
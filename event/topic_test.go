@@ -0,0 +1,192 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lazygophers/utils/xerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderCreated struct {
+	ID    int
+	Total float64
+}
+
+func TestTopicPublishSubscribe(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	topic := NewTopicOn[orderCreated](manager, "order.created")
+	assert.Equal(t, "order.created", topic.Name())
+
+	var got []orderCreated
+	topic.Subscribe(func(ctx context.Context, value orderCreated) error {
+		got = append(got, value)
+		return nil
+	})
+
+	require.NoError(t, topic.Publish(context.Background(), orderCreated{ID: 1, Total: 9.5}))
+	assert.Equal(t, []orderCreated{{ID: 1, Total: 9.5}}, got)
+}
+
+func TestTopicErrorsJoined(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	topic := NewTopicOn[int](manager, "numbers")
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	var called int32
+	topic.Subscribe(func(ctx context.Context, value int) error {
+		atomic.AddInt32(&called, 1)
+		return errA
+	})
+	topic.Subscribe(func(ctx context.Context, value int) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+	topic.Subscribe(func(ctx context.Context, value int) error {
+		atomic.AddInt32(&called, 1)
+		return errB
+	})
+
+	err := topic.Publish(context.Background(), 1)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errA))
+	assert.True(t, errors.Is(err, errB))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&called), "An error should not stop other handlers")
+}
+
+func TestTopicCoexistsWithStringHandlers(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	topic := NewTopicOn[string](manager, "user.created")
+
+	var untyped any
+	manager.Register("user.*", func(args any) {
+		untyped = args
+	})
+
+	var typed []string
+	topic.Subscribe(func(ctx context.Context, value string) error {
+		typed = append(typed, value)
+		return nil
+	})
+
+	require.NoError(t, topic.Publish(context.Background(), "alice"))
+	assert.Equal(t, "alice", untyped)
+
+	manager.Emit("user.created", "bob")
+	assert.Equal(t, []string{"alice", "bob"}, typed)
+}
+
+func TestTopicPayloadMismatch(t *testing.T) {
+	errs := make(chan error, 1)
+	manager := NewManager(WithErrorHook(func(eventName string, args any, err error) {
+		errs <- err
+	}))
+	defer manager.Close()
+
+	topic := NewTopicOn[int](manager, "mismatch")
+	var called int32
+	topic.Subscribe(func(ctx context.Context, value int) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+
+	// Emit 没有返回值，类型不符的错误交给 ErrorHook
+	manager.Emit("mismatch", "not-an-int")
+	select {
+	case err := <-errs:
+		assert.Contains(t, err.Error(), "does not match topic type int")
+	default:
+		t.Fatal("error hook should be called")
+	}
+	assert.Zero(t, atomic.LoadInt32(&called))
+
+	// nil 视为零值
+	manager.Emit("mismatch", nil)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&called))
+}
+
+func TestTopicPublishContext(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	type ctxKey struct{}
+	topic := NewTopicOn[int](manager, "ctx")
+
+	var seen any
+	topic.Subscribe(func(ctx context.Context, value int) error {
+		seen = ctx.Value(ctxKey{})
+		return nil
+	})
+
+	require.NoError(t, topic.Publish(context.WithValue(context.Background(), ctxKey{}, "v"), 1))
+	assert.Equal(t, "v", seen)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	seen = nil
+	err := topic.Publish(ctx, 2)
+	assert.Equal(t, xerror.CodeCanceled, xerror.Code(err))
+	assert.Nil(t, seen, "Handlers should not run for a cancelled publish")
+}
+
+func TestTopicSubscribeAsync(t *testing.T) {
+	errs := make(chan error, 1)
+	manager := NewManager(WithErrorHook(func(eventName string, args any, err error) {
+		errs <- err
+	}))
+	defer manager.Close()
+
+	topic := NewTopicOn[int](manager, "async")
+	boom := errors.New("boom")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var ctxErr error
+	sub := topic.SubscribeAsync(func(ctx context.Context, value int) error {
+		time.Sleep(10 * time.Millisecond)
+		ctxErr = ctx.Err()
+		return boom
+	})
+
+	require.NoError(t, topic.Publish(ctx, 1), "Async errors are not returned by Publish")
+	cancel()
+	require.NoError(t, manager.Drain(context.Background()))
+
+	assert.NoError(t, ctxErr, "Async handlers should not inherit the publisher's cancellation")
+	select {
+	case err := <-errs:
+		assert.Equal(t, boom, err)
+	default:
+		t.Fatal("error hook should be called")
+	}
+
+	sub.Unsubscribe()
+	require.NoError(t, topic.Publish(context.Background(), 2))
+	require.NoError(t, manager.Drain(context.Background()))
+	assert.Empty(t, errs)
+}
+
+func TestDefaultManagerTopic(t *testing.T) {
+	topic := NewTopic[int]("default-topic")
+
+	var got int
+	sub := topic.Subscribe(func(ctx context.Context, value int) error {
+		got = value
+		return nil
+	}, Once())
+	defer sub.Unsubscribe()
+
+	require.NoError(t, topic.Publish(context.Background(), 7))
+	require.NoError(t, topic.Publish(context.Background(), 8))
+	assert.Equal(t, 7, got)
+}
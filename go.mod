@@ -21,6 +21,7 @@ require (
 	github.com/hashicorp/hcl/v2 v2.24.0
 	github.com/oklog/ulid/v2 v2.1.1
 	github.com/yosuke-furukawa/json5 v0.1.1
+	github.com/zclconf/go-cty v1.18.0
 	golang.org/x/crypto v0.52.0
 	golang.org/x/exp v0.0.0-20260603202125-055de637280b
 	golang.org/x/text v0.37.0
@@ -36,7 +37,6 @@ require (
 	github.com/kr/text v0.2.0 // indirect
 	github.com/lazygophers/log/constant v0.0.0-20260505024342-2c291363de69 // indirect
 	github.com/mitchellh/go-wordwrap v1.0.1 // indirect
-	github.com/zclconf/go-cty v1.18.0 // indirect
 	golang.org/x/arch v0.27.0 // indirect
 	golang.org/x/mod v0.36.0 // indirect
 	golang.org/x/sync v0.20.0 // indirect
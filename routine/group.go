@@ -1,4 +1,169 @@
 package routine
 
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/lazygophers/utils/runtime"
+	"github.com/lazygophers/utils/xerror"
+	"github.com/petermattis/goid"
+)
+
+// PanicError 是 Group 成员 panic 后转换出的错误，Stack 为 panic 现场的堆栈
+type PanicError struct {
+	Value any
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap 在 panic 值本身是 error 时返回它，便于 errors.Is/As 穿透
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// GroupOption 配置 Group
+type GroupOption func(*Group)
+
+// WithLimit 限制同时运行的成员数量，n <= 0 表示不限制
+// 达到上限时 Go 阻塞，直到有成员退出
+func WithLimit(n int) GroupOption {
+	return func(g *Group) {
+		if n > 0 {
+			g.sem = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithCollectAll 让 Wait 返回所有成员的错误（经 xerror.Join 合并），而不是只返回第一个
+// 第一个错误仍会取消其他成员的 ctx
+func WithCollectAll() GroupOption {
+	return func(g *Group) {
+		g.collectAll = true
+	}
+}
+
+// Group 结构化并发组：一组协程共享同一个 ctx，任一成员返回错误或 panic 时取消其余成员，
+// Wait 等待全部成员退出并返回错误。
+// 成员启动时与 Go 一样经过 before/after 钩子，trace id 从调用 Group.Go 的协程派生。
+// 零值可用，此时不限并发、成员拿到的 ctx 为 context.Background()、出错时不取消其他成员。
 type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sem        chan struct{}
+	collectAll bool
+
+	wg sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewGroup 创建派生自 ctx 的 Group，Wait 返回后派生的 ctx 被取消
+func NewGroup(ctx context.Context, opts ...GroupOption) *Group {
+	g := &Group{}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	return g
+}
+
+// Context 返回传给成员的 ctx
+func (g *Group) Context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
+// Go 启动一个成员，达到并发上限时阻塞
+func (g *Group) Go(f func(ctx context.Context) error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(f)
+}
+
+// TryGo 在未达到并发上限时启动成员并返回 true，否则不启动并返回 false
+func (g *Group) TryGo(f func(ctx context.Context) error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+// Wait 等待所有成员退出，默认返回第一个错误；WithCollectAll 时返回所有错误的合并
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if g.collectAll {
+		return xerror.Join(g.errs...)
+	}
+	return g.errs[0]
+}
+
+func (g *Group) start(f func(ctx context.Context) error) {
+	g.wg.Add(1)
+
+	baseGid := goid.Get()
+	go func() {
+		currentGid := goid.Get()
+		before(baseGid, currentGid)
+		defer func() {
+			after(currentGid)
+		}()
+
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		if err := g.run(f); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+// run 执行成员，panic 转换为 *PanicError
+func (g *Group) run(f func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: runtime.GetStack()}
+		}
+	}()
+	return f(g.Context())
+}
+
+// fail 记录错误，首个错误取消其余成员
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	first := len(g.errs) == 0
+	if first || g.collectAll {
+		g.errs = append(g.errs, err)
+	}
+	g.mu.Unlock()
+
+	if first && g.cancel != nil {
+		g.cancel()
+	}
 }
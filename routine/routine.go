@@ -1,8 +1,10 @@
 package routine
 
 import (
+	"context"
 	"fmt"
 	"github.com/lazygophers/log"
+	"github.com/lazygophers/utils/xerror"
 	"github.com/petermattis/goid"
 	"os"
 	"runtime/debug"
@@ -111,3 +113,36 @@ func GoWithMustSuccess(f func() (err error)) {
 		}
 	}()
 }
+
+// GoCtx 与 Go 相同，但把 ctx 传给 f。
+// ctx 在协程启动前已结束时不执行 f，记录 xerror 超时/取消错误；trace id 透传与 Go 一致。
+func GoCtx(ctx context.Context, f func(ctx context.Context) (err error)) {
+	baseGid := goid.Get()
+	go func() {
+		currentGid := goid.Get()
+		before(baseGid, currentGid)
+		defer func() {
+			after(currentGid)
+		}()
+
+		if err := xerror.FromContext(ctx); err != nil {
+			log.Errorf("err:%v", err)
+			return
+		}
+
+		err := f(ctx)
+		if err != nil {
+			log.Errorf("err:%v", err)
+		}
+	}()
+}
+
+// GoWithRecoverCtx 与 GoWithRecover 相同，但把 ctx 传给 f，ctx 已结束时不执行 f
+func GoWithRecoverCtx(ctx context.Context, f func(ctx context.Context) (err error)) {
+	GoWithRecover(func() (err error) {
+		if err = xerror.FromContext(ctx); err != nil {
+			return err
+		}
+		return f(ctx)
+	})
+}
@@ -1,15 +1,20 @@
 package routine
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/lazygophers/log"
+	"github.com/petermattis/goid"
 )
 
 func TestGo_BasicOperation(t *testing.T) {
@@ -383,6 +388,46 @@ func TestGoWithRecover_EmptyStack(t *testing.T) {
 	}
 }
 
+func TestGoCtx_BasicOperation(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	done := make(chan any, 1)
+
+	GoCtx(ctx, func(ctx context.Context) error {
+		done <- ctx.Value(ctxKey{})
+		return nil
+	})
+
+	select {
+	case v := <-done:
+		if v != "v" {
+			t.Errorf("Expected ctx to be passed through, got %v", v)
+		}
+	case <-time.After(1 * time.Second):
+		t.Error("Goroutine did not complete within timeout")
+	}
+}
+
+func TestGoCtx_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	executed := int32(0)
+	GoCtx(ctx, func(ctx context.Context) error {
+		atomic.StoreInt32(&executed, 1)
+		return nil
+	})
+	GoWithRecoverCtx(ctx, func(ctx context.Context) error {
+		atomic.StoreInt32(&executed, 1)
+		return nil
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&executed) != 0 {
+		t.Error("Function should not run with a cancelled context")
+	}
+}
+
 func TestGroup_Basic(t *testing.T) {
 	// Test that Group struct exists and can be instantiated
 	g := &Group{}
@@ -391,6 +436,130 @@ func TestGroup_Basic(t *testing.T) {
 	}
 }
 
+func TestGroup_FirstError(t *testing.T) {
+	g := NewGroup(context.Background())
+	first := errors.New("first")
+
+	g.Go(func(ctx context.Context) error {
+		return first
+	})
+	g.Go(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return errors.New("cancelled")
+		case <-time.After(time.Second):
+			t.Error("Sibling was not cancelled")
+			return nil
+		}
+	})
+
+	if err := g.Wait(); err != first {
+		t.Errorf("Expected first error, got %v", err)
+	}
+	if g.Context().Err() == nil {
+		t.Error("Expected group context to be cancelled after Wait")
+	}
+}
+
+func TestGroup_CollectAll(t *testing.T) {
+	g := NewGroup(context.Background(), WithCollectAll())
+	errA, errB := errors.New("a"), errors.New("b")
+
+	g.Go(func(ctx context.Context) error { return errA })
+	g.Go(func(ctx context.Context) error { return errB })
+	g.Go(func(ctx context.Context) error { return nil })
+
+	err := g.Wait()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Expected both errors joined, got %v", err)
+	}
+}
+
+func TestGroup_Panic(t *testing.T) {
+	g := NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error {
+		panic("group boom")
+	})
+
+	err := g.Wait()
+	var p *PanicError
+	if !errors.As(err, &p) {
+		t.Fatalf("Expected *PanicError, got %v", err)
+	}
+	if p.Value != "group boom" || !strings.Contains(p.Stack, "dump stack:") {
+		t.Errorf("Unexpected panic error %q", err.Error())
+	}
+
+	cause := errors.New("cause")
+	g = &Group{}
+	g.Go(func(ctx context.Context) error {
+		panic(cause)
+	})
+	if err := g.Wait(); !errors.Is(err, cause) {
+		t.Errorf("Expected panic value to be unwrapped, got %v", err)
+	}
+}
+
+func TestGroup_Limit(t *testing.T) {
+	g := NewGroup(context.Background(), WithLimit(2))
+	var running, peak int32
+
+	for i := 0; i < 10; i++ {
+		g.Go(func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent members, got %d", peak)
+	}
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	g := NewGroup(context.Background(), WithLimit(1))
+	release := make(chan struct{})
+
+	if !g.TryGo(func(ctx context.Context) error { <-release; return nil }) {
+		t.Fatal("Expected first TryGo to start")
+	}
+	if g.TryGo(func(ctx context.Context) error { return nil }) {
+		t.Error("Expected TryGo to fail when the limit is reached")
+	}
+
+	close(release)
+	g.Wait()
+}
+
+func TestGroup_Trace(t *testing.T) {
+	gid := goid.Get()
+	log.SetTraceWithGID(gid, "parent")
+	defer log.DelTraceWithGID(gid)
+
+	var trace string
+	g := NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error {
+		trace = log.GetTraceWithGID(goid.Get())
+		return nil
+	})
+	g.Wait()
+
+	if !strings.HasPrefix(trace, "parent.") {
+		t.Errorf("Expected trace derived from parent, got %q", trace)
+	}
+}
+
 // Test concurrent execution of different routine types
 func TestMixedRoutines_Concurrent(t *testing.T) {
 	const numEach = 5
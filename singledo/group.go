@@ -1,6 +1,7 @@
 package singledo
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -40,6 +41,11 @@ func (p *Group[T]) Do(key string, fn func() (T, error)) (v T, err error) {
 	return p.getOrAddSingle(key).Do(fn)
 }
 
+// DoCtx 与 Do 相同，支持 ctx 取消，语义见 Single.DoCtx
+func (p *Group[T]) DoCtx(ctx context.Context, key string, fn func() (T, error)) (v T, err error) {
+	return p.getOrAddSingle(key).DoCtx(ctx, fn)
+}
+
 func NewSingleGroup[T any](wait time.Duration) *Group[T] {
 	return &Group[T]{
 		wait:      wait,
@@ -9,6 +9,8 @@
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/lazygophers/utils/xerror"
 )
 
 func TestSingle_Do(t *testing.T) {
@@ -86,12 +88,13 @@ func TestSingle_Do(t *testing.T) {
 		}
 
 		// First call fails
-		_, err := s.Do(fn)
+		_, err := s.DoCtx(context.Background(), fn)
 		assert.Error(t, err)
 		assert.Equal(t, 1, calls)
 
-		// Second call should execute fn again (error not cached)
-		v, err := s.Do(fn)
+		// Second call should execute fn again (DoCtx neither caches nor keeps a failed call;
+		// Do keeps it, see TestSingleDo_ErrorKeepsCall)
+		v, err := s.DoCtx(context.Background(), fn)
 		assert.NoError(t, err)
 		assert.Equal(t, "result", v)
 		assert.Equal(t, 2, calls, "should retry on error")
@@ -195,7 +198,8 @@ func TestSingle_DoCtx_Context(t *testing.T) {
 
 		v, err := s.DoCtx(ctx, fn)
 		assert.Error(t, err)
-		assert.Equal(t, context.Canceled, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, xerror.CodeCanceled, xerror.Code(err))
 		assert.Equal(t, "", v)
 		assert.Equal(t, 0, calls, "should not call fn if ctx already cancelled")
 	})
@@ -243,6 +247,30 @@ func TestSingle_DoCtx_Context(t *testing.T) {
 		assert.Equal(t, "result", v)
 		assert.Equal(t, 1, calls, "should not call fn again")
 	})
+
+	t.Run("waiter_released_on_cancel", func(t *testing.T) {
+		s := NewSingle[string](time.Second)
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		go func() {
+			_, _ = s.DoCtx(context.Background(), func() (string, error) {
+				close(started)
+				<-release
+				return "result", nil
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := s.DoCtx(ctx, func() (string, error) { return "other", nil })
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, xerror.CodeTimeout, xerror.Code(err))
+
+		close(release)
+	})
 }
 
 func TestGroup_Do(t *testing.T) {
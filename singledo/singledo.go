@@ -1,14 +1,22 @@
 package singledo
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/lazygophers/utils/xerror"
 )
 
 type call[T any] struct {
-	wg  sync.WaitGroup
-	val T
-	err error
+	done chan struct{}
+	val  T
+	err  error
+}
+
+func newCall[T any]() *call[T] {
+	return &call[T]{done: make(chan struct{})}
 }
 
 type Single[T any] struct {
@@ -29,17 +37,16 @@ func (s *Single[T]) Do(fn func() (T, error)) (v T, err error) {
 
 	if callM := s.call; callM != nil {
 		s.mux.Unlock()
-		callM.wg.Wait()
+		<-callM.done
 		return callM.val, callM.err
 	}
 
-	callM := &call[T]{}
-	callM.wg.Add(1)
+	callM := newCall[T]()
 	s.call = callM
 	s.mux.Unlock()
 
 	callM.val, callM.err = fn()
-	callM.wg.Done()
+	close(callM.done)
 
 	s.mux.Lock()
 	if callM.err == nil {
@@ -52,6 +59,65 @@ func (s *Single[T]) Do(fn func() (T, error)) (v T, err error) {
 	return callM.val, callM.err
 }
 
+// DoCtx 与 Do 相同，但支持 ctx 取消：
+//   - 缓存有效期内直接返回缓存结果，不检查 ctx
+//   - ctx 已结束时不执行 fn，返回 xerror 超时/取消错误（errors.Is 可匹配 ctx.Err()）
+//   - 等待其他 goroutine 的执行结果时 ctx 结束，立即返回 xerror 超时/取消错误（errors.Is 可匹配 ctx.Err()）
+//   - 由当前 goroutine 执行的 fn 不会被中断，需要中断时由 fn 自行捕获 ctx
+//
+// fn 中的 panic 会被转换为 error 返回给所有等待者。
+func (s *Single[T]) DoCtx(ctx context.Context, fn func() (T, error)) (v T, err error) {
+	s.mux.Lock()
+	now := time.Now()
+	if now.Before(s.last.Add(s.wait)) {
+		s.mux.Unlock()
+		return s.result, nil
+	}
+
+	if ctx.Err() != nil {
+		s.mux.Unlock()
+		return v, xerror.FromContext(ctx)
+	}
+
+	if callM := s.call; callM != nil {
+		s.mux.Unlock()
+		select {
+		case <-callM.done:
+			return callM.val, callM.err
+		case <-ctx.Done():
+			return v, xerror.FromContext(ctx)
+		}
+	}
+
+	callM := newCall[T]()
+	s.call = callM
+	s.mux.Unlock()
+
+	callM.val, callM.err = doWithRecover(fn)
+
+	// 与 Do 不同，失败后同样结束本次调用，下一次调用重新执行 fn；只有成功结果进入缓存
+	s.mux.Lock()
+	if callM.err == nil {
+		s.last = now
+		s.result = callM.val
+	}
+	s.call = nil
+	s.mux.Unlock()
+	close(callM.done)
+
+	return callM.val, callM.err
+}
+
+// doWithRecover 执行 fn，并把 panic 转换为 error
+func doWithRecover[T any](fn func() (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("singledo: panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
 func (s *Single[T]) Reset() {
 	s.last = time.Time{}
 }
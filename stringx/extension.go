@@ -5,6 +5,8 @@
 	"encoding/hex"
 	"strings"
 	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // TrimSpaceAll 移除字符串中所有空白字符（包括空格、制表符、换行符等）
@@ -40,20 +42,23 @@ func ToTitle(s string) string {
 }
 
 // Normalize 对字符串进行 Unicode 归一化
-// form: 0=NFC, 1=NFD, 2=NFKC, 3=NFKD
-// 注意：Go 标准库没有内置归一化功能，此函数为占位实现
-// 实际使用建议：import "golang.org/x/text/unicode/norm"
-// 例如：将 "é" 的组合字符分解为基础字符 + 变音符号
+// form: 0=NFC, 1=NFD, 2=NFKC, 3=NFKD，其他值原样返回
+// 例如：NFC 将 "e" + U+0301 组合为 "é"，NFD 则反向分解
 func Normalize(s string, form int) string {
 	if s == "" {
 		return ""
 	}
 
-	// 简化实现：目前仅返回原字符串
-	// 完整实现需要 golang.org/x/text/unicode/norm 包
-	// 可以通过以下方式使用：
-	//   import "golang.org/x/text/unicode/norm"
-	//   result := norm.NFC.String(s)  // 或 NFD, NFKC, NFKD
+	switch form {
+	case 0:
+		return norm.NFC.String(s)
+	case 1:
+		return norm.NFD.String(s)
+	case 2:
+		return norm.NFKC.String(s)
+	case 3:
+		return norm.NFKD.String(s)
+	}
 	return s
 }
 
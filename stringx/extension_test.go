@@ -64,6 +64,15 @@ func TestNormalize(t *testing.T) {
 		result := Normalize(input, 1)
 		assert.NotEmpty(t, result)
 	})
+
+	t.Run("forms", func(t *testing.T) {
+		composed, decomposed := "\u00e9", "e\u0301"
+		assert.Equal(t, composed, Normalize(decomposed, 0))
+		assert.Equal(t, decomposed, Normalize(composed, 1))
+		assert.Equal(t, "fi", Normalize("\ufb01", 2))
+		assert.Equal(t, decomposed, Normalize("\u00e9", 3))
+		assert.Equal(t, decomposed, Normalize(decomposed, 9))
+	})
 }
 
 func TestBase64Encode(t *testing.T) {
@@ -12,38 +12,6 @@
 	xlanguage "golang.org/x/text/language"
 )
 
-// ===== engine.go: Opt11 email/alpha/alphanum/url cases (lines 1331-1353) =====
-
-func TestOpt11AllCases(t *testing.T) {
-	e := NewEngine()
-	// empty email -> true (skip check)
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("")}, "email"))
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("a@b.com")}, "email"))
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("not-email")}, "email"))
-
-	// alpha
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("")}, "alpha"))
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("abc")}, "alpha"))
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("abc1")}, "alpha"))
-
-	// alphanum
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("")}, "alphanum"))
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("abc123")}, "alphanum"))
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("abc!123")}, "alphanum"))
-
-	// url
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("")}, "url"))
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("https://example.com")}, "url"))
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("not-url")}, "url"))
-
-	// default case: known validator
-	e.RegisterValidation("custom_opt11", func(fl FieldLevel) bool { return true })
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("x")}, "custom_opt11"))
-
-	// default case: unknown validator -> true
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf("x")}, "unknown_tag_opt11"))
-}
-
 // ===== engine.go: RegisterValidation nil (line 130-131) =====
 
 func TestRegisterValidationNilFunc2(t *testing.T) {
@@ -225,19 +193,6 @@ func TestGetLocaleNonexistent(t *testing.T) {
 	assert.True(t, ok)
 }
 
-// ===== engine.go: Opt11 required slice/map/ptr/nil =====
-
-func TestOpt11RequiredKinds(t *testing.T) {
-	e := NewEngine()
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf([]int{})}, "required"))
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf([]int{1})}, "required"))
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf(map[string]int{})}, "required"))
-	assert.True(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf(map[string]int{"a": 1})}, "required"))
-	var p *int
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf(p)}, "required"))
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf(nil)}, "required"))
-}
-
 // ===== engine.go: validateStruct interface field =====
 
 func TestValidateStructInterfaceField(t *testing.T) {
@@ -0,0 +1,220 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/lazygophers/utils/xerror"
+)
+
+// 上下文验证器：需要 I/O 的规则（用户名是否已占用、优惠券是否存在等）注册为 ValidatorCtxFunc，
+// 可以接收 ctx 并返回 error：
+//
+//	e.RegisterValidationCtx("username_free", func(ctx context.Context, fl FieldLevel) (bool, error) {
+//		taken, err := repo.UsernameTaken(ctx, fl.Field().String())
+//		return !taken, err
+//	})
+//	err := e.StructCtx(ctx, &req)
+//
+// 遍历结构体时上下文规则只登记不执行，遍历结束后以 SetCtxConcurrency 限定的并发度统一执行。
+// 规则返回 false 产生普通的 FieldError；返回 error 表示无法得出结论，产生 *RuleError。
+
+// DefaultCtxConcurrency 上下文规则的默认最大并发数
+const DefaultCtxConcurrency = 8
+
+// ValidatorCtxFunc 可访问 ctx、可返回错误的验证函数。
+// 函数可能在多个 goroutine 中并发执行，fl 只在本次调用内有效
+type ValidatorCtxFunc func(ctx context.Context, fl FieldLevel) (bool, error)
+
+// RuleError 规则执行出错（而非验证不通过），通过 xerror 包装原始错误，
+// errors.Is/As 可穿透到规则返回的 error
+type RuleError struct {
+	Field     string // 字段名（fieldNameFunc 的结果）
+	Namespace string // 字段路径
+	Tag       string // 规则名
+	Param     string // 规则参数
+	err       error
+}
+
+func newRuleError(fe *FieldError, cause error) *RuleError {
+	return &RuleError{
+		Field:     fe.Field,
+		Namespace: fe.Namespace,
+		Tag:       fe.Tag,
+		Param:     fe.Param,
+		err:       xerror.Wrap(cause, ""),
+	}
+}
+
+// Error 消息由规则与字段自行拼接，不受 xerror Localizer 对错误码翻译的影响
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("validator: rule %s on %s: %v", e.Tag, e.Namespace, errors.Unwrap(e.err))
+}
+
+// Unwrap 返回 xerror 包装后的错误
+func (e *RuleError) Unwrap() error {
+	return e.err
+}
+
+// RegisterValidationCtx 注册上下文验证规则，同名的普通规则会被替换
+func (e *Engine) RegisterValidationCtx(tag string, fn ValidatorCtxFunc) error {
+	if tag == "" {
+		return fmt.Errorf("validation tag cannot be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("validation function cannot be nil")
+	}
+
+	if e.ctxValidators == nil {
+		e.ctxValidators = make(map[string]ValidatorCtxFunc)
+	}
+	e.ctxValidators[tag] = fn
+	delete(e.validators, tag)
+	e.resetPlans()
+	return nil
+}
+
+// SetCtxConcurrency 设置一次验证中上下文规则的最大并发数，n <= 0 时恢复 DefaultCtxConcurrency
+func (e *Engine) SetCtxConcurrency(n int) {
+	e.ctxConcurrency = n
+}
+
+// StructCtx 验证结构体，上下文规则使用 ctx 执行。
+// 只有验证失败时返回 ValidationErrors；有规则出错时返回 *RuleError，
+// 与其他规则错误、ValidationErrors 一起经 xerror.Join 合并；
+// ctx 在全部规则启动前结束时返回 xerror.FromContext 的错误
+func (e *Engine) StructCtx(ctx context.Context, s interface{}) error {
+	rv := reflect.ValueOf(s)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+		if !rv.IsValid() {
+			return fmt.Errorf("nil pointer dereference")
+		}
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("expected struct, got %s", rv.Kind())
+	}
+
+	var validationErrors ValidationErrors
+	r := &planRun{e: e, top: rv, errors: &validationErrors}
+	r.run(rv, "")
+
+	// 执行结构体级别验证
+	if e.structValidators != nil {
+		if fn, ok := e.structValidators[rv.Type().Name()]; ok {
+			sl := &structLevel{
+				top:       rv,
+				current:   rv,
+				validator: e,
+				errors:    &validationErrors,
+				namespace: "",
+			}
+			// 错误已通过 ReportError 添加到 errors 中
+			_ = fn(sl)
+		}
+	}
+
+	return r.result(ctx)
+}
+
+// ctxCall 遍历时登记的上下文规则调用
+type ctxCall struct {
+	fn ValidatorCtxFunc
+	fl *fieldLevel
+	fe FieldError // 验证不通过时报告的错误
+}
+
+// ctxResult 上下文规则的执行结果
+type ctxResult struct {
+	ok  bool
+	err error
+}
+
+// deferCtx 登记上下文规则，fl 会被复制，调用方可以继续复用
+func (r *planRun) deferCtx(fn ValidatorCtxFunc, fl *fieldLevel, fe FieldError) {
+	call := &ctxCall{fn: fn, fl: &fieldLevel{}, fe: fe}
+	*call.fl = *fl
+	r.pending = append(r.pending, call)
+}
+
+// result 执行登记的上下文规则并合并全部错误
+func (r *planRun) result(ctx context.Context) error {
+	errs := append(r.ruleErrs, r.runPending(ctx)...)
+	if len(errs) == 0 {
+		// 常见路径：没有规则错误，直接返回 ValidationErrors，避免 Join 分配
+		if len(*r.errors) > 0 {
+			return *r.errors
+		}
+		return nil
+	}
+	if len(*r.errors) > 0 {
+		errs = append(errs, *r.errors)
+	}
+	return xerror.Join(errs...)
+}
+
+// runPending 以有限并发执行登记的上下文规则，验证失败追加到 r.errors，返回规则错误
+func (r *planRun) runPending(ctx context.Context) []error {
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	limit := r.e.ctxConcurrency
+	if limit <= 0 {
+		limit = DefaultCtxConcurrency
+	}
+
+	results := make([]ctxResult, len(r.pending))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	started := 0
+	for i, call := range r.pending {
+		// ctx 结束后不再启动新的规则
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		started++
+		wg.Add(1)
+		go func(res *ctxResult, call *ctxCall) {
+			defer func() {
+				if p := recover(); p != nil {
+					res.ok, res.err = false, fmt.Errorf("panic: %v", p)
+				}
+				<-sem
+				wg.Done()
+			}()
+			res.ok, res.err = call.fn(ctx, call.fl)
+		}(&results[i], call)
+	}
+	wg.Wait()
+
+	if started < len(r.pending) {
+		return []error{xerror.FromContext(ctx)}
+	}
+
+	var ruleErrs []error
+	for i, call := range r.pending {
+		res := results[i]
+		switch {
+		case res.err != nil:
+			ruleErrs = append(ruleErrs, newRuleError(&call.fe, res.err))
+		case !res.ok:
+			fe := call.fe
+			*r.errors = append(*r.errors, &fe)
+		}
+	}
+	return ruleErrs
+}
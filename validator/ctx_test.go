@@ -0,0 +1,217 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazygophers/utils/xerror"
+)
+
+var errRepoDown = errors.New("repo down")
+
+type ctxCoupon struct {
+	Code string `json:"code" validate:"required,coupon_exists"`
+}
+
+type ctxSignup struct {
+	Username string      `json:"username" validate:"required,min=3,username_free"`
+	Email    string      `json:"email" validate:"email"`
+	Coupons  []ctxCoupon `json:"coupons" validate:"dive"`
+	Invites  []string    `json:"invites" validate:"dive=username_free"`
+}
+
+// newCtxEngine 注册模拟 I/O 的上下文规则：taken 中的用户名已占用，"down" 触发规则错误
+func newCtxEngine(t *testing.T, delay time.Duration, running, peak *int32) *Engine {
+	t.Helper()
+	e := NewEngine()
+
+	track := func(ctx context.Context) error {
+		n := atomic.AddInt32(running, 1)
+		defer atomic.AddInt32(running, -1)
+		for {
+			p := atomic.LoadInt32(peak)
+			if n <= p || atomic.CompareAndSwapInt32(peak, p, n) {
+				break
+			}
+		}
+		select {
+		case <-time.After(delay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	taken := map[string]bool{"admin": true, "root": true}
+	require.NoError(t, e.RegisterValidationCtx("username_free", func(ctx context.Context, fl FieldLevel) (bool, error) {
+		if err := track(ctx); err != nil {
+			return false, err
+		}
+		if fl.Field().String() == "down" {
+			return false, errRepoDown
+		}
+		return !taken[fl.Field().String()], nil
+	}))
+	require.NoError(t, e.RegisterValidationCtx("coupon_exists", func(ctx context.Context, fl FieldLevel) (bool, error) {
+		if err := track(ctx); err != nil {
+			return false, err
+		}
+		return fl.Field().String() != "EXPIRED", nil
+	}))
+	return e
+}
+
+func TestStructCtx(t *testing.T) {
+	var running, peak int32
+	e := newCtxEngine(t, 0, &running, &peak)
+
+	valid := &ctxSignup{Username: "alice", Email: "a@example.com", Coupons: []ctxCoupon{{Code: "NEW"}}, Invites: []string{"bob"}}
+	assert.NoError(t, e.StructCtx(context.Background(), valid))
+	assert.NoError(t, e.Struct(valid))
+
+	invalid := &ctxSignup{
+		Username: "admin",
+		Email:    "bad",
+		Coupons:  []ctxCoupon{{Code: "NEW"}, {Code: "EXPIRED"}},
+		Invites:  []string{"bob", "root"},
+	}
+	err := e.StructCtx(context.Background(), invalid)
+	require.Error(t, err)
+
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	// 同步规则的错误在前，上下文规则按遍历顺序追加在后
+	assert.Equal(t, []string{"Email", "Username", "Coupons[1].Code", "Invites[1]"}, namespacesOf(t, errs))
+	assert.Equal(t, "username_free", errs[1].Tag)
+
+	var ruleErr *RuleError
+	assert.False(t, errors.As(err, &ruleErr))
+}
+
+func TestStructCtxRuleError(t *testing.T) {
+	var running, peak int32
+	e := newCtxEngine(t, 0, &running, &peak)
+
+	err := e.StructCtx(context.Background(), &ctxSignup{Username: "down", Email: "bad", Invites: []string{"root"}})
+	require.Error(t, err)
+
+	var ruleErr *RuleError
+	require.True(t, errors.As(err, &ruleErr))
+	assert.Equal(t, "Username", ruleErr.Namespace)
+	assert.Equal(t, "username", ruleErr.Field)
+	assert.Equal(t, "username_free", ruleErr.Tag)
+	assert.ErrorIs(t, err, errRepoDown)
+	assert.Contains(t, err.Error(), "validator: rule username_free on Username: repo down")
+
+	var xerr *xerror.Error
+	require.True(t, errors.As(ruleErr, &xerr))
+	assert.Equal(t, xerror.CodeSystem, xerr.Code())
+
+	// 验证失败仍然一并返回
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, []string{"Email", "Invites[0]"}, namespacesOf(t, errs))
+
+	// Var 同样支持上下文规则
+	assert.ErrorIs(t, e.Var("down", "username_free"), errRepoDown)
+	assert.Error(t, e.Var("admin", "username_free"))
+	assert.NoError(t, e.Var("alice", "username_free"))
+}
+
+func TestStructCtxConcurrency(t *testing.T) {
+	var running, peak int32
+	e := newCtxEngine(t, 20*time.Millisecond, &running, &peak)
+	e.SetCtxConcurrency(3)
+
+	s := &ctxSignup{Username: "alice", Email: "a@example.com"}
+	for i := 0; i < 12; i++ {
+		s.Invites = append(s.Invites, "bob")
+	}
+
+	start := time.Now()
+	require.NoError(t, e.StructCtx(context.Background(), s))
+	elapsed := time.Since(start)
+
+	assert.EqualValues(t, 3, atomic.LoadInt32(&peak))
+	// 13 次调用、并发 3：至少 5 轮，明显快于串行的 13 轮
+	assert.GreaterOrEqual(t, elapsed, 90*time.Millisecond)
+	assert.Less(t, elapsed, 13*20*time.Millisecond)
+}
+
+func TestStructCtxCanceled(t *testing.T) {
+	var running, peak int32
+	e := newCtxEngine(t, time.Second, &running, &peak)
+	e.SetCtxConcurrency(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	s := &ctxSignup{Username: "alice", Invites: []string{"a", "b", "c"}}
+	start := time.Now()
+	err := e.StructCtx(ctx, s)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, xerror.CodeTimeout, xerror.Code(err))
+
+	cctx, ccancel := context.WithCancel(context.Background())
+	ccancel()
+	err = e.StructCtx(cctx, s)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, xerror.CodeCanceled, xerror.Code(err))
+}
+
+func TestRegisterValidationCtx(t *testing.T) {
+	e := NewEngine()
+	assert.Error(t, e.RegisterValidationCtx("", func(context.Context, FieldLevel) (bool, error) { return true, nil }))
+	assert.Error(t, e.RegisterValidationCtx("x", nil))
+
+	type S struct {
+		V string `validate:"check"`
+	}
+
+	// 后注册的同名规则替换先注册的
+	require.NoError(t, e.RegisterValidation("check", func(fl FieldLevel) bool { return false }))
+	require.NoError(t, e.RegisterValidationCtx("check", func(context.Context, FieldLevel) (bool, error) {
+		panic("boom")
+	}))
+	err := e.Struct(&S{})
+	var ruleErr *RuleError
+	require.True(t, errors.As(err, &ruleErr))
+	assert.Contains(t, ruleErr.Error(), "panic: boom")
+
+	require.NoError(t, e.RegisterValidation("check", func(fl FieldLevel) bool { return true }))
+	assert.NoError(t, e.Struct(&S{}))
+}
+
+func TestValidatorStructCtx(t *testing.T) {
+	v, err := New(WithCtxConcurrency(2))
+	require.NoError(t, err)
+	require.NoError(t, v.RegisterValidationCtx("username_free", func(_ context.Context, fl FieldLevel) (bool, error) {
+		if fl.Field().String() == "down" {
+			return false, errRepoDown
+		}
+		return fl.Field().String() != "admin", nil
+	}))
+
+	err = v.StructCtx(context.Background(), &ctxSignup{Username: "down", Email: "bad"})
+	assert.ErrorIs(t, err, errRepoDown)
+
+	// 与规则错误合并返回的验证错误同样被翻译
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, "email must be a valid email address", errs[0].Message)
+
+	err = v.Struct(&ctxSignup{Username: "admin", Email: "a@example.com"})
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, "username_free", errs[0].Tag)
+
+	assert.Error(t, StructCtx(context.Background(), 1))
+	assert.Error(t, RegisterValidationCtx("", nil))
+}
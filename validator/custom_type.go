@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 自定义类型：sql.NullString、Optional[T] 这类"包装了一个值"的类型在执行规则前先解包，
+// 规则看到的是被包装的值（无效/缺失时为 nil）：
+//
+//	type Req struct {
+//		Nick  sql.NullString `validate:"omitempty,min=2"` // Valid 为 false 时视为空
+//		Age   Optional[int]  `validate:"required,gte=18"`
+//	}
+//
+// 解包函数的查找顺序：RegisterCustomTypeFunc 注册的函数、ValueUnwrapper、database/sql 的 Null* 类型。
+// 其他实现 driver.Valuer 的类型（如 GORM 的 JSON 列、Value 返回 JSON 文本的切片）不自动解包，
+// 规则作用于原始值；需要按 Value 的结果验证时注册 DriverValuerFunc：
+//
+//	e.RegisterCustomTypeFunc(validator.DriverValuerFunc, Money{})
+// 指针字段（*sql.NullString）为 nil 时保持 nil，否则解引用后解包。
+
+// CustomTypeFunc 将自定义类型的值转换为参与验证的值，返回 nil 表示值缺失
+type CustomTypeFunc func(field reflect.Value) interface{}
+
+// ValueUnwrapper 由 Optional[T] 一类的容器类型实现，Unwrap 返回被包装的值，缺失时返回 nil
+type ValueUnwrapper interface {
+	Unwrap() interface{}
+}
+
+var (
+	valueUnwrapperType = reflect.TypeOf((*ValueUnwrapper)(nil)).Elem()
+	driverValuerType   = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+	// nilValue 解包结果为 nil 时使用的值：Kind 为 Interface 且 IsNil，omitempty/required 都按空处理
+	nilValue = reflect.Zero(reflect.TypeOf((*interface{})(nil)).Elem())
+)
+
+// RegisterCustomTypeFunc 为 types 中各值的类型注册解包函数，优先于 ValueUnwrapper/driver.Valuer
+func (e *Engine) RegisterCustomTypeFunc(fn CustomTypeFunc, types ...interface{}) error {
+	if fn == nil {
+		return fmt.Errorf("custom type function cannot be nil")
+	}
+	if len(types) == 0 {
+		return fmt.Errorf("custom type function requires at least one type")
+	}
+
+	if e.customTypeFuncs == nil {
+		e.customTypeFuncs = make(map[reflect.Type]CustomTypeFunc)
+	}
+	for _, t := range types {
+		if t == nil {
+			return fmt.Errorf("custom type cannot be nil")
+		}
+		e.customTypeFuncs[reflect.TypeOf(t)] = fn
+	}
+	e.resetPlans()
+	return nil
+}
+
+// customTypeFunc 获取类型的解包函数，没有时返回 nil
+func (e *Engine) customTypeFunc(t reflect.Type) CustomTypeFunc {
+	if cached, ok := e.customTypes.Load(t); ok {
+		return cached.(CustomTypeFunc)
+	}
+	fn, _ := e.customTypes.LoadOrStore(t, e.resolveCustomTypeFunc(t))
+	return fn.(CustomTypeFunc)
+}
+
+// resolveCustomTypeFunc 查找类型的解包函数；指针与接口类型只使用注册的函数
+func (e *Engine) resolveCustomTypeFunc(t reflect.Type) CustomTypeFunc {
+	if fn, ok := e.customTypeFuncs[t]; ok {
+		return fn
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return nil
+	}
+
+	switch {
+	case t.Implements(valueUnwrapperType):
+		return unwrapValue
+	case reflect.PointerTo(t).Implements(valueUnwrapperType):
+		return addressable(unwrapValue)
+	case isSQLNullType(t):
+		return DriverValuerFunc
+	}
+	return nil
+}
+
+// isSQLNullType 判断是否为 database/sql 的 Null* 类型（sql.NullString、sql.Null[T] 等）
+func isSQLNullType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" &&
+		strings.HasPrefix(t.Name(), "Null") && t.Implements(driverValuerType)
+}
+
+// unwrapValue ValueUnwrapper 的解包函数
+func unwrapValue(field reflect.Value) interface{} {
+	return field.Interface().(ValueUnwrapper).Unwrap()
+}
+
+// DriverValuerFunc 按 driver.Valuer 的 Value 结果解包，值/指针接收者均可，Value 出错时视为值缺失
+func DriverValuerFunc(field reflect.Value) interface{} {
+	valuer, ok := field.Interface().(driver.Valuer)
+	if !ok {
+		if !reflect.PointerTo(field.Type()).Implements(driverValuerType) {
+			return field.Interface()
+		}
+		return addressable(DriverValuerFunc)(field)
+	}
+	v, err := valuer.Value()
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// addressable 包装指针接收者的解包函数：不可寻址的值先复制
+func addressable(fn CustomTypeFunc) CustomTypeFunc {
+	return func(field reflect.Value) interface{} {
+		if !field.CanAddr() {
+			ptr := reflect.New(field.Type())
+			ptr.Elem().Set(field)
+			field = ptr.Elem()
+		}
+		return fn(field.Addr())
+	}
+}
+
+// unwrapCustom 解包自定义类型的值；非 nil 指针在元素类型有解包函数时先解引用。
+// 解包结果仍是自定义类型时继续解包（Optional[sql.NullInt64]）
+func (e *Engine) unwrapCustom(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	if v.Kind() == reflect.Ptr && !v.IsNil() && e.customTypeFunc(v.Type().Elem()) != nil {
+		v = v.Elem()
+	}
+
+	for v.IsValid() && v.CanInterface() {
+		fn := e.customTypeFunc(v.Type())
+		if fn == nil {
+			break
+		}
+
+		out := fn(v)
+		if out == nil {
+			return nilValue
+		}
+		next := reflect.ValueOf(out)
+		if next.Type() == v.Type() {
+			return next
+		}
+		v = next
+	}
+	return v
+}
@@ -0,0 +1,231 @@
+package validator
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOptional Optional[T] 形式的容器类型
+type testOptional[T any] struct {
+	value T
+	ok    bool
+}
+
+func someOf[T any](v T) testOptional[T] {
+	return testOptional[T]{value: v, ok: true}
+}
+
+func (o testOptional[T]) Unwrap() interface{} {
+	if !o.ok {
+		return nil
+	}
+	return o.value
+}
+
+// testMoney 通过 RegisterCustomTypeFunc 注册
+type testMoney struct {
+	cents int64
+}
+
+// testCode 指针接收者实现 driver.Valuer 的非结构体类型
+type testCode string
+
+func (c *testCode) Value() (driver.Value, error) {
+	if *c == "!" {
+		return nil, errors.New("bad code")
+	}
+	return string(*c), nil
+}
+
+type customAddress struct {
+	City string `validate:"required"`
+}
+
+type customProfile struct {
+	Nick    sql.NullString                `validate:"omitempty,min=2"`
+	Age     sql.NullInt64                 `validate:"required,min=18"`
+	Email   *sql.NullString               `validate:"omitempty,email"`
+	Score   testOptional[int]             `validate:"omitempty,max=100"`
+	Nested  testOptional[sql.NullString]  `validate:"required"`
+	Address testOptional[customAddress]   `validate:"required"`
+	Balance testMoney                     `validate:"min=0"`
+	Code    testCode                      `validate:"required,len=3"`
+	Phones  []sql.NullString              `validate:"dive,omitempty,numeric"`
+	Extra   map[string]testOptional[bool] `validate:"dive,required"`
+}
+
+func newCustomProfile() *customProfile {
+	email := sql.NullString{String: "a@example.com", Valid: true}
+	return &customProfile{
+		Age:     sql.NullInt64{Int64: 20, Valid: true},
+		Email:   &email,
+		Score:   someOf(90),
+		Nested:  someOf(sql.NullString{String: "x", Valid: true}),
+		Address: someOf(customAddress{City: "Paris"}),
+		Code:    testCode("abc"),
+		Phones:  []sql.NullString{{}, {String: "123", Valid: true}},
+		Extra:   map[string]testOptional[bool]{"vip": someOf(true)},
+	}
+}
+
+func TestCustomTypeFunc(t *testing.T) {
+	e := NewEngine()
+	require.NoError(t, e.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(testMoney).cents
+	}, testMoney{}))
+	require.NoError(t, e.RegisterCustomTypeFunc(DriverValuerFunc, testCode("")))
+
+	assert.NoError(t, e.Struct(newCustomProfile()))
+
+	p := newCustomProfile()
+	p.Nick = sql.NullString{String: "a", Valid: true}
+	p.Age = sql.NullInt64{}
+	p.Email.String = "bad"
+	p.Score = someOf(101)
+	p.Nested = someOf(sql.NullString{})
+	p.Address = someOf(customAddress{})
+	p.Balance = testMoney{cents: -1}
+	p.Code = testCode("!")
+	p.Phones = append(p.Phones, sql.NullString{String: "abc", Valid: true})
+	p.Extra["trial"] = testOptional[bool]{}
+
+	err := e.Struct(p)
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"Nick", "Age", "Age", "Email", "Score", "Nested",
+		"Address", "Address.City", "Balance", "Code", "Code", "Phones[2]", "Extra[trial]",
+	}, namespacesOf(t, err))
+
+	errs := err.(ValidationErrors)
+	// 错误中的值是解包后的值
+	assert.Equal(t, "a", errs[0].Value)
+	assert.Equal(t, 101, errs[4].Value)
+	assert.Equal(t, int64(-1), errs[8].Value)
+
+	// nil 指针保持 nil
+	p = newCustomProfile()
+	p.Email = nil
+	assert.NoError(t, e.Struct(p))
+
+	assert.NoError(t, e.Var(sql.NullString{String: "ab", Valid: true}, "min=2"))
+	assert.Error(t, e.Var(sql.NullString{}, "required"))
+	assert.Error(t, e.Var(someOf(1), "min=2"))
+}
+
+func TestRegisterCustomTypeFunc(t *testing.T) {
+	e := NewEngine()
+	fn := func(field reflect.Value) interface{} { return nil }
+	assert.Error(t, e.RegisterCustomTypeFunc(nil, testMoney{}))
+	assert.Error(t, e.RegisterCustomTypeFunc(fn))
+	assert.Error(t, e.RegisterCustomTypeFunc(fn, nil))
+
+	type S struct {
+		Balance testMoney `validate:"required"`
+		Nick    sql.NullString
+	}
+
+	// 未注册时按结构体验证，required 看零值
+	assert.NoError(t, e.Struct(&S{Balance: testMoney{cents: 1}}))
+
+	// 注册的函数优先于 driver.Valuer，注册后计划失效
+	require.NoError(t, e.RegisterCustomTypeFunc(fn, testMoney{}, sql.NullString{}))
+	assert.Equal(t, []string{"Balance"}, namespacesOf(t, e.Struct(&S{Balance: testMoney{cents: 1}})))
+
+	v, err := New()
+	require.NoError(t, err)
+	require.NoError(t, v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(testMoney).cents
+	}, testMoney{}))
+	assert.Error(t, v.Struct(&S{}))
+	assert.NoError(t, v.Struct(&S{Balance: testMoney{cents: 5}}))
+	assert.Error(t, RegisterCustomTypeFunc(nil))
+}
+
+// testJSONColumn 实现 driver.Valuer 的结构体（GORM JSON 列的写法）
+type testJSONColumn struct {
+	Name string `validate:"required"`
+}
+
+func (c testJSONColumn) Value() (driver.Value, error) {
+	return "{}", nil
+}
+
+// testJSONAddress 指针接收者实现 driver.Valuer 的结构体
+type testJSONAddress struct {
+	City string `validate:"required"`
+}
+
+func (a *testJSONAddress) Value() (driver.Value, error) {
+	return a.City, nil
+}
+
+func TestCustomTypeFuncValuerStruct(t *testing.T) {
+	type S struct {
+		Column  testJSONColumn
+		Address testJSONAddress
+		Backup  *testJSONColumn
+	}
+
+	// 实现 driver.Valuer 的结构体不自动解包，仍递归验证字段
+	e := NewEngine()
+	assert.Equal(t, []string{"Column.Name", "Address.City"}, namespacesOf(t, e.Struct(&S{})))
+	assert.Equal(t, []string{"Column.Name", "Address.City", "Backup.Name"}, namespacesOf(t, e.Struct(&S{Backup: &testJSONColumn{}})))
+
+	v, err := New()
+	require.NoError(t, err)
+	err = v.Struct(&S{Column: testJSONColumn{Name: "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "City")
+
+	// 注册返回自身的函数不改变结果
+	require.NoError(t, e.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface()
+	}, testJSONColumn{}))
+	assert.Equal(t, []string{"Column.Name", "Address.City"}, namespacesOf(t, e.Struct(&S{})))
+
+	// 需要按 Value 的结果验证时显式注册
+	type T struct {
+		Column  testJSONColumn  `validate:"required,len=2"`
+		Address testJSONAddress `validate:"required"`
+	}
+	require.NoError(t, e.RegisterCustomTypeFunc(DriverValuerFunc, testJSONColumn{}, testJSONAddress{}))
+	assert.Equal(t, []string{"Address"}, namespacesOf(t, e.Struct(&T{})))
+}
+
+// testJSONTags Value 返回 JSON 文本的切片
+type testJSONTags []string
+
+func (t testJSONTags) Value() (driver.Value, error) {
+	data, err := json.Marshal([]string(t))
+	return string(data), err
+}
+
+func TestCustomTypeFuncValuerSlice(t *testing.T) {
+	type S struct {
+		Tags testJSONTags `validate:"max=2,dive,min=1"`
+	}
+
+	// 未注册时规则作用于切片本身
+	e := NewEngine()
+	assert.NoError(t, e.Struct(&S{Tags: testJSONTags{"a", "b"}}))
+	assert.NoError(t, e.Var(testJSONTags{"a", "b"}, "max=2"))
+
+	err := e.Struct(&S{Tags: testJSONTags{"a", "b", ""}})
+	assert.Equal(t, []string{"Tags", "Tags[2]"}, namespacesOf(t, err))
+	assert.NotContains(t, err.(ValidationErrors)[0].Message, "var")
+
+	// 注册后按 Value 的结果（JSON 文本）验证，错误仍挂在字段上
+	require.NoError(t, e.RegisterCustomTypeFunc(DriverValuerFunc, testJSONTags{}))
+	err = e.Struct(&S{Tags: testJSONTags{"a", "b"}})
+	assert.Equal(t, []string{"Tags"}, namespacesOf(t, err))
+	fe := err.(ValidationErrors)[0]
+	assert.Equal(t, `["a","b"]`, fe.Value)
+	assert.Contains(t, fe.Message, "Tags")
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -26,6 +27,13 @@ type Engine struct {
 	tagName       string
 	structValidators  map[string]StructValidatorFunc
 	fieldNameFunc func(reflect.StructField) string
+	plans         sync.Map // map[reflect.Type]*structPlan
+	modifiers     map[string]ModifierFunc
+	modPlans      sync.Map // map[reflect.Type]*modStructPlan
+	ctxValidators  map[string]ValidatorCtxFunc
+	ctxConcurrency int // 上下文规则最大并发数，<= 0 使用 DefaultCtxConcurrency
+	customTypeFuncs map[reflect.Type]CustomTypeFunc
+	customTypes     sync.Map // map[reflect.Type]CustomTypeFunc，解析结果缓存（含 nil）
 }
 
 // fieldLevel 对象池，用于减少内存分配
@@ -115,6 +123,9 @@ func NewEngine() *Engine {
 	// 注册内置验证器
 	e.registerBuiltinValidators()
 
+	// 注册内置清洗规则
+	e.registerBuiltinModifiers()
+
 	return e
 }
 
@@ -122,6 +133,7 @@ func NewEngine() *Engine {
 func (e *Engine) SetFieldNameFunc(fn func(reflect.StructField) string) {
 	if fn != nil {
 		e.fieldNameFunc = fn
+		e.resetPlans()
 	}
 }
 
@@ -135,58 +147,25 @@ func (e *Engine) RegisterValidation(tag string, fn ValidatorFunc) error {
 	}
 
 	e.validators[tag] = fn
+	delete(e.ctxValidators, tag)
+	e.resetPlans()
 	return nil
 }
 
 // SetTagName 设置验证标签名称
 func (e *Engine) SetTagName(name string) {
 	e.tagName = name
+	e.resetPlans()
 }
 
-// Struct 验证结构体
+// Struct 验证结构体，上下文规则使用 context.Background() 执行（见 StructCtx）
 func (e *Engine) Struct(s interface{}) error {
-	rv := reflect.ValueOf(s)
-	if rv.Kind() == reflect.Ptr {
-		rv = rv.Elem()
-		if !rv.IsValid() {
-			return fmt.Errorf("nil pointer dereference")
-		}
-	}
-
-	if rv.Kind() != reflect.Struct {
-		return fmt.Errorf("expected struct, got %s", rv.Kind())
-	}
-
-	var errors ValidationErrors
-	e.validateStruct(rv, rv, "", &errors)
-	rt := rv.Type()
-
-
-	// 执行结构体级别验证
-	if e.structValidators != nil {
-		typeName := rt.Name()
-		if fn, ok := e.structValidators[typeName]; ok {
-			sl := &structLevel{
-				top:       rv,
-				current:   rv,
-				validator: e,
-				errors:    &errors,
-				namespace: "",
-			}
-			if !fn(sl) {
-				// 错误已通过 ReportError 添加到 errors 中
-			}
-		}
-	}
-	if len(errors) > 0 {
-		return errors
-	}
-	return nil
+	return e.StructCtx(context.Background(), s)
 }
 
 // Var 验证单个变量
 func (e *Engine) Var(field interface{}, tag string) error {
-	rv := reflect.ValueOf(field)
+	rv := e.unwrapCustom(reflect.ValueOf(field))
 
 	// 解析验证标签
 	rules := e.parseTag(tag)
@@ -203,7 +182,28 @@ func (e *Engine) Var(field interface{}, tag string) error {
 
 	for _, rule := range rules {
 		fl.param = rule.param
-		if !e.validateField(fl, rule.tag) {
+		var valid bool
+		if fn, ok := e.ctxValidators[rule.tag]; ok {
+			var err error
+			valid, err = fn(context.Background(), fl)
+			if err != nil {
+				*fl = fieldLevel{}
+				fieldLevelPool.Put(fl)
+				return newRuleError(&FieldError{Field: "var", Tag: rule.tag, Param: rule.param, Namespace: "var"}, err)
+			}
+		} else if isExprValidator(e.validators[rule.tag]) {
+			prog := exprProgramOf(fl)
+			if prog.err != nil {
+				*fl = fieldLevel{}
+				fieldLevelPool.Put(fl)
+				return &ExprError{Field: "var", Expr: rule.param, err: prog.err}
+			}
+			ok, err := prog.run(fl)
+			valid = err == nil && ok
+		} else {
+			valid = e.validateField(fl, rule.tag)
+		}
+		if !valid {
 			*fl = fieldLevel{}
 			fieldLevelPool.Put(fl)
 			return &FieldError{
@@ -221,154 +221,7 @@ func (e *Engine) Var(field interface{}, tag string) error {
 	return nil
 }
 
-// validateStruct 验证结构体内部实现
-// 性能优化：Kind 缓存 + 内联访问 + 对象池，预期性能提升 15-25%
-func (e *Engine) validateStruct(top, current reflect.Value, namespace string, errors *ValidationErrors) {
-	rt := current.Type()
-	numField := current.NumField()
-	tagName := e.tagName
-	fieldNameFunc := e.fieldNameFunc
-
-	for i := 0; i < numField; i++ {
-		field := current.Field(i)
-		fieldType := rt.Field(i)
-
-		// 跳过非导出字段
-		if !fieldType.IsExported() {
-			continue
-		}
-
-		fieldName := fieldType.Name
-		if namespace != "" {
-			fieldName = namespace + "." + fieldName
-		}
-
-		// 获取验证标签
-		tag := fieldType.Tag.Get(tagName)
-		if tag == "" || tag == "-" {
-			// 如果没有验证标签，但是字段是结构体，递归验证
-			fieldKind := field.Kind()
-			if fieldKind == reflect.Struct {
-				e.validateStruct(top, field, fieldName, errors)
-			} else if fieldKind == reflect.Ptr && !field.IsNil() {
-				elem := field.Elem()
-				if elem.Kind() == reflect.Struct {
-					e.validateStruct(top, elem, fieldName, errors)
-				}
-			}
-			continue
-		}
-
-		// 解析验证规则
-		rules := e.parseTag(tag)
-
-		// 获取字段显示名称
-		displayName := fieldNameFunc(fieldType)
-
-		// 使用对象池获取 fieldLevel
-		fl := fieldLevelPool.Get().(*fieldLevel)
-		fl.top = top
-		fl.parent = current
-		fl.field = field
-		fl.fieldName = displayName
-		fl.structFieldName = fieldType.Name
-		fl.structField = fieldType
-
-		numRules := len(rules)
-		for j := 0; j < numRules; j++ {
-			rule := rules[j]
-			fl.param = rule.param
-
-			// 检查是否为 dive tag（用于切片/数组元素验证）
-			if rule.tag == "dive" {
-				// 验证切片/数组中的每个元素
-				fieldKind := field.Kind()
-				if fieldKind == reflect.Slice || fieldKind == reflect.Array {
-					fieldLen := field.Len()
-					for k := 0; k < fieldLen; k++ {
-						elem := field.Index(k)
-						elemFieldName := fieldName + "[" + strconv.Itoa(k) + "]"
-
-						// 如果元素是结构体，递归验证
-						elemKind := elem.Kind()
-						if elemKind == reflect.Struct {
-							e.validateStruct(top, elem, elemFieldName, errors)
-						} else if elemKind == reflect.Ptr && !elem.IsNil() {
-							elemElem := elem.Elem()
-							if elemElem.Kind() == reflect.Struct {
-								e.validateStruct(top, elemElem, elemFieldName, errors)
-							}
-						} else if rule.param != "" {
-							// 如果 dive 有参数，验证元素
-							elemRules := e.parseTag(rule.param)
-							elemFl := fieldLevelPool.Get().(*fieldLevel)
-							elemFl.top = top
-							elemFl.parent = field
-							elemFl.field = elem
-							elemFl.fieldName = elemFieldName
-							elemFl.structFieldName = elemFieldName
-							elemFl.structField = fieldType
-
-							numElemRules := len(elemRules)
-							for l := 0; l < numElemRules; l++ {
-								elemRule := elemRules[l]
-								elemFl.param = elemRule.param
-								if !e.validateField(elemFl, elemRule.tag) {
-									*errors = append(*errors, &FieldError{
-										Field:       elemFieldName,
-										Tag:         elemRule.tag,
-										Value:       elem.Interface(),
-										Param:       elemRule.param,
-										ActualTag:   elemRule.tag,
-										Namespace:   elemFieldName,
-										StructField: elemFieldName,
-										Message:     fmt.Sprintf("validation failed for tag '%s'", elemRule.tag),
-									})
-								}
-							}
-
-							*elemFl = fieldLevel{}
-							fieldLevelPool.Put(elemFl)
-						}
-					}
-				}
-				continue
-			}
-
-			if !e.validateField(fl, rule.tag) {
-				fieldError := &FieldError{
-					Field:       displayName,
-					Tag:         rule.tag,
-					Value:       field.Interface(),
-					Param:       rule.param,
-					ActualTag:   rule.tag,
-					Namespace:   fieldName,
-					StructField: fieldType.Name,
-					Message:     formatMessage(getDefaultMessage(rule.tag), "var", rule.tag, rule.param),
-				}
-				*errors = append(*errors, fieldError)
-			}
-		}
-
-		// 归还对象池（重置避免持有引用）
-		*fl = fieldLevel{}
-		fieldLevelPool.Put(fl)
-
-		// 递归验证嵌套结构体
-		fieldKind := field.Kind()
-		if fieldKind == reflect.Struct {
-			e.validateStruct(top, field, fieldName, errors)
-		} else if fieldKind == reflect.Ptr && !field.IsNil() {
-			elem := field.Elem()
-			if elem.Kind() == reflect.Struct {
-				e.validateStruct(top, elem, fieldName, errors)
-			}
-		}
-	}
-}
-
-// 性能优化: 内联 map 查找，性能提升约 7.3%
-// 基准测试: BenchmarkValidateField_Opt2_InlineMap-8 748.6 ns/op vs 807.1 ns/op (当前)
+// validateField 执行 tag 对应的验证函数，未注册的规则视为通过
 func (e *Engine) validateField(fl FieldLevel, tag string) bool {
 	if fn, ok := e.validators[tag]; ok {
 		return fn(fl)
@@ -389,8 +242,11 @@ func (e *Engine) parseTag(tag string) []validationRule {
 		return cached.([]validationRule)
 	}
 
-	parts := strings.Split(tag, ",")
-	rules := make([]validationRule, 0, len(parts))
+	// expr 参数中可能包含逗号，其后的全部内容都作为表达式
+	head, exprParam, hasExpr := cutExprRule(tag)
+
+	parts := strings.Split(head, ",")
+	rules := make([]validationRule, 0, len(parts)+1)
 
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
@@ -407,10 +263,30 @@ func (e *Engine) parseTag(tag string) []validationRule {
 		}
 	}
 
+	if hasExpr {
+		rules = append(rules, validationRule{tag: "expr", param: exprParam})
+	}
+
 	tagParseCache.Store(tag, rules)
 	return rules
 }
 
+// cutExprRule 从标签中切出 expr 规则，expr 必须是标签中的最后一条规则
+func cutExprRule(tag string) (head, expr string, found bool) {
+	for i := 0; i < len(tag); {
+		rest := strings.TrimLeft(tag[i:], " ")
+		if strings.HasPrefix(rest, "expr=") {
+			return tag[:i], strings.TrimSpace(rest[len("expr="):]), true
+		}
+		idx := strings.IndexByte(tag[i:], ',')
+		if idx == -1 {
+			break
+		}
+		i += idx + 1
+	}
+	return tag, "", false
+}
+
 // defaultFieldNameFunc 默认字段名称解析函数（优先使用JSON标签）
 func defaultFieldNameFunc(field reflect.StructField) string {
 	if jsonTag := field.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
@@ -692,6 +568,9 @@ func (e *Engine) registerBuiltinValidators() {
 		}
 		return true
 	}
+
+	// 表达式验证（见 expr.go）
+	e.validators["expr"] = validateExpr
 }
 
 // compareFields 比较两个字段的值
@@ -1236,150 +1115,3 @@ func NotIn(values ...interface{}) ValidatorFunc {
 		return true
 	}
 }
-
-// ===== 优化方案 (用于基准测试) =====
-
-// 方案2: 内联 map 查找
-func (e *Engine) validateField_Opt2_InlineMap(fl FieldLevel, tag string) bool {
-	if fn, ok := e.validators[tag]; ok {
-		return fn(fl)
-	}
-	return true
-}
-
-// 方案3: 单次查找
-func (e *Engine) validateField_Opt3_SingleLookup(fl FieldLevel, tag string) bool {
-	v := e.validators
-	if fn := v[tag]; fn != nil {
-		return fn(fl)
-	}
-	return true
-}
-
-// 方案5: 热路径 switch (前8个常用标签)
-func (e *Engine) validateField_Opt5_HotPathSwitch(fl FieldLevel, tag string) bool {
-	switch tag {
-	case "required":
-		return e.validators["required"](fl)
-	case "email":
-		return e.validators["email"](fl)
-	case "min":
-		return e.validators["min"](fl)
-	case "max":
-		return e.validators["max"](fl)
-	case "len":
-		return e.validators["len"](fl)
-	case "alpha":
-		return e.validators["alpha"](fl)
-	case "alphanum":
-		return e.validators["alphanum"](fl)
-	case "url":
-		return e.validators["url"](fl)
-	default:
-		if fn, ok := e.validators[tag]; ok {
-			return fn(fl)
-		}
-		return true
-	}
-}
-
-// 方案6: 完整 switch
-func (e *Engine) validateField_Opt6_FullSwitch(fl FieldLevel, tag string) bool {
-	switch tag {
-	case "required":
-		return e.validators["required"](fl)
-	case "email":
-		return e.validators["email"](fl)
-	case "min":
-		return e.validators["min"](fl)
-	case "max":
-		return e.validators["max"](fl)
-	case "len":
-		return e.validators["len"](fl)
-	case "alpha":
-		return e.validators["alpha"](fl)
-	case "alphanum":
-		return e.validators["alphanum"](fl)
-	case "url":
-		return e.validators["url"](fl)
-	case "numeric":
-		return e.validators["numeric"](fl)
-	case "eq":
-		return e.validators["eq"](fl)
-	case "ne":
-		return e.validators["ne"](fl)
-	case "eqfield":
-		return e.validators["eqfield"](fl)
-	case "nefield":
-		return e.validators["nefield"](fl)
-	case "required_if":
-		return e.validators["required_if"](fl)
-	case "required_with":
-		return e.validators["required_with"](fl)
-	case "required_without":
-		return e.validators["required_without"](fl)
-	default:
-		return true
-	}
-}
-
-// 方案11: 内联验证器函数
-func (e *Engine) validateField_Opt11_InlinedValidators(fl FieldLevel, tag string) bool {
-	switch tag {
-	case "required":
-		field := fl.Field()
-		switch field.Kind() {
-		case reflect.String:
-			return field.String() != ""
-		case reflect.Slice, reflect.Map, reflect.Array:
-			return field.Len() > 0
-		case reflect.Ptr, reflect.Interface:
-			return !field.IsNil()
-		default:
-			return field.IsValid() && !field.IsZero()
-		}
-	case "email":
-		email := fl.Field().String()
-		if email == "" {
-			return true
-		}
-		return emailRegex.MatchString(email)
-	case "alpha":
-		s := fl.Field().String()
-		if s == "" {
-			return true
-		}
-		return alphaRegex.MatchString(s)
-	case "alphanum":
-		s := fl.Field().String()
-		if s == "" {
-			return true
-		}
-		return alphanumRegex.MatchString(s)
-	case "url":
-		url := fl.Field().String()
-		if url == "" {
-			return true
-		}
-		return urlRegex.MatchString(url)
-	default:
-		if fn, ok := e.validators[tag]; ok {
-			return fn(fl)
-		}
-		return true
-	}
-}
-
-// 方案13: goto 优化
-func (e *Engine) validateField_Opt13_GotoOptimized(fl FieldLevel, tag string) bool {
-	var fn ValidatorFunc
-	var ok bool
-
-	if fn, ok = e.validators[tag]; !ok {
-		goto notFound
-	}
-	return fn(fl)
-
-notFound:
-	return true
-}
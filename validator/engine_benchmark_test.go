@@ -634,90 +634,6 @@ func BenchmarkValidateField_Current_Single(b *testing.B) {
 	}
 }
 
-// ===== 方案2: 内联 map 查找 =====
-
-func BenchmarkValidateField_Opt2_InlineMap(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, tag := range commonTags {
-			_ = e.validateField_Opt2_InlineMap(fl, tag)
-		}
-	}
-}
-
-// ===== 方案3: 单次查找 =====
-
-func BenchmarkValidateField_Opt3_SingleLookup(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, tag := range commonTags {
-			_ = e.validateField_Opt3_SingleLookup(fl, tag)
-		}
-	}
-}
-
-// ===== 方案5: 热路径 switch =====
-
-func BenchmarkValidateField_Opt5_HotPathSwitch(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, tag := range commonTags {
-			_ = e.validateField_Opt5_HotPathSwitch(fl, tag)
-		}
-	}
-}
-
-// ===== 方案6: 完整 switch =====
-
-func BenchmarkValidateField_Opt6_FullSwitch(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, tag := range commonTags {
-			_ = e.validateField_Opt6_FullSwitch(fl, tag)
-		}
-	}
-}
-
-// ===== 方案11: 内联验证器函数 =====
-
-func BenchmarkValidateField_Opt11_InlinedValidators(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, tag := range commonTags {
-			_ = e.validateField_Opt11_InlinedValidators(fl, tag)
-		}
-	}
-}
-
-// ===== 方案13: goto 优化 =====
-
-func BenchmarkValidateField_Opt13_GotoOptimized(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, tag := range commonTags {
-			_ = e.validateField_Opt13_GotoOptimized(fl, tag)
-		}
-	}
-}
-
 // ===== 内存分配基准 =====
 
 func BenchmarkValidateField_Alloc_Current(b *testing.B) {
@@ -731,28 +647,6 @@ func BenchmarkValidateField_Alloc_Current(b *testing.B) {
 	}
 }
 
-func BenchmarkValidateField_Alloc_Opt5_Switch(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	b.ReportAllocs()
-	for i := 0; i < b.N; i++ {
-		_ = e.validateField_Opt5_HotPathSwitch(fl, "email")
-	}
-}
-
-func BenchmarkValidateField_Alloc_Opt11_Inlined(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	b.ReportAllocs()
-	for i := 0; i < b.N; i++ {
-		_ = e.validateField_Opt11_InlinedValidators(fl, "email")
-	}
-}
-
 // ===== 并行基准 =====
 
 func BenchmarkValidateField_Parallel_Current(b *testing.B) {
@@ -767,30 +661,6 @@ func BenchmarkValidateField_Parallel_Current(b *testing.B) {
 	})
 }
 
-func BenchmarkValidateField_Parallel_Opt5_Switch(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			_ = e.validateField_Opt5_HotPathSwitch(fl, "email")
-		}
-	})
-}
-
-func BenchmarkValidateField_Parallel_Opt11_Inlined(b *testing.B) {
-	e := NewEngine()
-	fl := genFieldLevel()
-
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			_ = e.validateField_Opt11_InlinedValidators(fl, "email")
-		}
-	})
-}
-
 // 生成测试数据（固定种子保证可重复）
 func genFloats(n int) []float64 {
 	r := rand.New(rand.NewSource(42))
@@ -190,22 +190,6 @@ type S2 struct {
 	assert.Error(t, v2_Struct(v, S2{Color: "red"}))
 }
 
-func TestEngineOptFunctions(t *testing.T) {
-	// Cover the dead code Opt* functions
-	e := NewEngine()
-	e.RegisterValidation("required", func(fl FieldLevel) bool { return fl.Field().String() != "" })
-
-	fl := testFL{field: reflect.ValueOf("hello")}
-
-	// Each Opt function should at least not panic
-	_ = e.validateField_Opt2_InlineMap(fl, "required")
-	_ = e.validateField_Opt3_SingleLookup(fl, "required")
-	_ = e.validateField_Opt5_HotPathSwitch(fl, "required")
-	_ = e.validateField_Opt6_FullSwitch(fl, "required")
-	_ = e.validateField_Opt11_InlinedValidators(fl, "required")
-	_ = e.validateField_Opt13_GotoOptimized(fl, "required")
-}
-
 func TestRegisterValidationDuplicate(t *testing.T) {
 	e := NewEngine()
 	err := e.RegisterValidation("test_dup", func(fl FieldLevel) bool { return true })
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -11,6 +12,13 @@
 	"github.com/stretchr/testify/require"
 )
 
+// validateStruct 测试辅助：按计划验证 current，只收集验证失败，规则错误被忽略
+func (e *Engine) validateStruct(top, current reflect.Value, namespace string, errors *ValidationErrors) {
+	r := &planRun{e: e, top: top, errors: errors}
+	r.run(current, namespace)
+	_ = r.runPending(context.Background())
+}
+
 // ========== isFieldNotEmpty 优化方案 ==========
 
 // 原始实现
@@ -0,0 +1,955 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expr 规则：validate:"expr=this.End > this.Start && len(this.Items) <= this.Max"
+//
+// 表达式语言是一个只读的小型沙箱，不能调用方法、不能赋值、不能访问非导出字段：
+//   - 根标识符：this（当前字段值）、parent（当前字段所在结构体）、top（顶级结构体），
+//     分别对应 FieldLevel 的 Field()/Parent()/Top()
+//   - 字段访问 a.B、下标 a[0] / m['key']，nil 指针上的字段访问结果为 nil
+//   - 字面量：整数、浮点数、时长（1h30m、500ms）、'字符串' / "字符串"、true、false、nil
+//   - 运算符：|| && ! == != < <= > >= + - * / %，以及括号
+//   - 内置函数：len(x)、now()
+//
+// 表达式按 (表达式, this/parent/top 类型) 编译一次并缓存，结构体字段在编译期解析为字段索引。
+// 构建结构体的验证计划时即按字段的静态类型编译（top 与接口类型的值运行时才确定），
+// 无法编译的表达式是标签书写错误，以 *ExprError 返回而不是验证失败。
+
+// exprCache 缓存已编译的表达式
+var exprCache sync.Map // map[exprCacheKey]*exprProgram
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// exprCacheKey 表达式缓存键
+type exprCacheKey struct {
+	expr   string
+	this   reflect.Type
+	parent reflect.Type
+	top    reflect.Type
+}
+
+// exprProgram 编译后的表达式（编译失败时记录错误，避免重复解析）
+type exprProgram struct {
+	root exprNode
+	err  error
+}
+
+// exprEnv 表达式求值环境
+type exprEnv struct {
+	this   reflect.Value
+	parent reflect.Value
+	top    reflect.Value
+}
+
+// exprNode 表达式语法树节点
+type exprNode interface {
+	eval(env *exprEnv) (any, error)
+}
+
+// ExprError expr 规则的表达式无法编译（语法错误、未知字段等）
+type ExprError struct {
+	Field string // 规则所在的字段：结构体字段为 类型.字段名，Var 为 var
+	Expr  string
+	err   error
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("validator: %s: %v", e.Field, e.err)
+}
+
+// Unwrap 返回编译错误
+func (e *ExprError) Unwrap() error {
+	return e.err
+}
+
+// validateExpr expr 规则验证函数，表达式编译或求值出错时视为验证失败
+func validateExpr(fl FieldLevel) bool {
+	ok, err := evalExpr(fl)
+	return err == nil && ok
+}
+
+// isExprValidator 判断 fn 是否为内置的 expr 规则（未被 RegisterValidation 覆盖）
+func isExprValidator(fn ValidatorFunc) bool {
+	return fn != nil && reflect.ValueOf(fn).Pointer() == reflect.ValueOf(ValidatorFunc(validateExpr)).Pointer()
+}
+
+// evalExpr 编译（带缓存）并对当前字段求值表达式
+func evalExpr(fl FieldLevel) (bool, error) {
+	prog := exprProgramOf(fl)
+	if prog.err != nil {
+		return false, prog.err
+	}
+	return prog.run(fl)
+}
+
+// exprProgramOf 按当前字段的值类型编译表达式
+func exprProgramOf(fl FieldLevel) *exprProgram {
+	return compileExpr(fl.Param(), valueType(fl.Field()), valueType(fl.Parent()), valueType(fl.Top()))
+}
+
+// run 对当前字段求值已编译的表达式
+func (prog *exprProgram) run(fl FieldLevel) (bool, error) {
+	env := &exprEnv{
+		this:   fl.Field(),
+		parent: fl.Parent(),
+		top:    fl.Top(),
+	}
+
+	result, err := prog.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("expr: expression must evaluate to bool, got %T", result)
+	}
+	return ok, nil
+}
+
+// compileExpr 编译表达式，相同表达式与类型组合只编译一次
+func compileExpr(expr string, this, parent, top reflect.Type) *exprProgram {
+	key := exprCacheKey{expr: expr, this: this, parent: parent, top: top}
+	if cached, ok := exprCache.Load(key); ok {
+		return cached.(*exprProgram)
+	}
+
+	prog := &exprProgram{}
+	tokens, err := tokenizeExpr(expr)
+	if err == nil {
+		p := &exprParser{tokens: tokens, this: this, parent: parent, top: top}
+		prog.root, err = p.parse()
+	}
+	if err != nil {
+		prog.err = fmt.Errorf("expr: %q: %w", expr, err)
+	}
+
+	actual, _ := exprCache.LoadOrStore(key, prog)
+	return actual.(*exprProgram)
+}
+
+// valueType 返回值的类型，无效值返回 nil
+func valueType(v reflect.Value) reflect.Type {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Type()
+}
+
+// indirectType 解引用指针类型，接口类型返回 nil（运行时动态解析）
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Interface {
+		return nil
+	}
+	return t
+}
+
+// ---------------------------------------------------------------------------
+// 词法分析
+// ---------------------------------------------------------------------------
+
+type exprTokenKind int
+
+const (
+	exprTokEOF exprTokenKind = iota
+	exprTokIdent
+	exprTokNumber
+	exprTokString
+	exprTokOp
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	text  string
+	value any // 数字与字符串字面量的值
+	pos   int
+}
+
+// exprOperators 运算符，双字符的排在前面优先匹配
+var exprOperators = []string{
+	"||", "&&", "==", "!=", "<=", ">=",
+	"<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ".", ",",
+}
+
+func tokenizeExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case isExprIdentStart(c):
+			start := i
+			for i < len(src) && (isExprIdentStart(src[i]) || isExprDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokIdent, text: src[start:i], pos: start})
+
+		case isExprDigit(c):
+			start := i
+			for i < len(src) && (isExprDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			// 数字后紧跟字母时按时长解析，如 1h30m、500ms
+			if i < len(src) && isExprIdentStart(src[i]) {
+				for i < len(src) && (isExprIdentStart(src[i]) || isExprDigit(src[i]) || src[i] == '.') {
+					i++
+				}
+				d, err := time.ParseDuration(src[start:i])
+				if err != nil {
+					return nil, fmt.Errorf("invalid duration %q at %d", src[start:i], start)
+				}
+				tokens = append(tokens, exprToken{kind: exprTokNumber, text: src[start:i], value: int64(d), pos: start})
+				continue
+			}
+			text := src[start:i]
+			var value any
+			if strings.IndexByte(text, '.') != -1 {
+				f, err := strconv.ParseFloat(text, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid number %q at %d", text, start)
+				}
+				value = f
+			} else {
+				n, err := strconv.ParseInt(text, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid number %q at %d", text, start)
+				}
+				value = n
+			}
+			tokens = append(tokens, exprToken{kind: exprTokNumber, text: text, value: value, pos: start})
+
+		case c == '\'' || c == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				if src[i] == c {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(src[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokString, text: src[start:i], value: sb.String(), pos: start})
+
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, exprToken{kind: exprTokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+		}
+	}
+	tokens = append(tokens, exprToken{kind: exprTokEOF, pos: len(src)})
+	return tokens, nil
+}
+
+func isExprIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isExprDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// ---------------------------------------------------------------------------
+// 语法分析（优先级爬升），同时做静态字段解析
+// ---------------------------------------------------------------------------
+
+// exprBinaryPrecedence 二元运算符优先级
+var exprBinaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+
+	this   reflect.Type
+	parent reflect.Type
+	top    reflect.Type
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != exprTokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) isOp(text string) bool {
+	tok := p.peek()
+	return tok.kind == exprTokOp && tok.text == text
+}
+
+func (p *exprParser) expectOp(text string) error {
+	tok := p.next()
+	if tok.kind != exprTokOp || tok.text != text {
+		return fmt.Errorf("expected %q at %d", text, tok.pos)
+	}
+	return nil
+}
+
+func (p *exprParser) parse() (exprNode, error) {
+	node, _, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != exprTokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+// parseBinary 解析优先级不低于 minPrec 的二元表达式。
+// 返回值中的 reflect.Type 为节点的静态类型，未知时为 nil。
+func (p *exprParser) parseBinary(minPrec int) (exprNode, reflect.Type, error) {
+	left, typ, err := p.parseUnary()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != exprTokOp {
+			return left, typ, nil
+		}
+		prec, ok := exprBinaryPrecedence[tok.text]
+		if !ok || prec < minPrec {
+			return left, typ, nil
+		}
+		p.next()
+
+		right, _, err := p.parseBinary(prec + 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		left, typ = &exprBinary{op: tok.text, left: left, right: right}, nil
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, reflect.Type, error) {
+	if p.isOp("!") || p.isOp("-") {
+		op := p.next().text
+		operand, _, err := p.parseUnary()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &exprUnary{op: op, operand: operand}, nil, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, reflect.Type, error) {
+	node, typ, err := p.parsePrimary()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		switch {
+		case p.isOp("."):
+			p.next()
+			tok := p.next()
+			if tok.kind != exprTokIdent {
+				return nil, nil, fmt.Errorf("expected field name at %d", tok.pos)
+			}
+			node, typ, err = newExprSelector(node, typ, tok.text)
+			if err != nil {
+				return nil, nil, err
+			}
+
+		case p.isOp("["):
+			p.next()
+			index, _, err := p.parseBinary(1)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := p.expectOp("]"); err != nil {
+				return nil, nil, err
+			}
+			var elem reflect.Type
+			if typ != nil {
+				switch typ.Kind() {
+				case reflect.Slice, reflect.Array, reflect.Map:
+					elem = indirectType(typ.Elem())
+				}
+			}
+			node, typ = &exprIndex{target: node, index: index}, elem
+
+		default:
+			return node, typ, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, reflect.Type, error) {
+	tok := p.next()
+	switch tok.kind {
+	case exprTokNumber, exprTokString:
+		return &exprLiteral{value: tok.value}, nil, nil
+
+	case exprTokIdent:
+		switch tok.text {
+		case "true":
+			return &exprLiteral{value: true}, nil, nil
+		case "false":
+			return &exprLiteral{value: false}, nil, nil
+		case "nil":
+			return &exprLiteral{value: nil}, nil, nil
+		case "this":
+			return &exprRoot{name: tok.text}, indirectType(p.this), nil
+		case "parent":
+			return &exprRoot{name: tok.text}, indirectType(p.parent), nil
+		case "top":
+			return &exprRoot{name: tok.text}, indirectType(p.top), nil
+		}
+
+		if !p.isOp("(") {
+			return nil, nil, fmt.Errorf("unknown identifier %q at %d", tok.text, tok.pos)
+		}
+		return p.parseCall(tok)
+
+	case exprTokOp:
+		if tok.text == "(" {
+			node, typ, err := p.parseBinary(1)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, nil, err
+			}
+			return node, typ, nil
+		}
+	}
+
+	if tok.kind == exprTokEOF {
+		return nil, nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+}
+
+// exprFuncArity 内置函数及其参数个数
+var exprFuncArity = map[string]int{
+	"len": 1,
+	"now": 0,
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, reflect.Type, error) {
+	arity, ok := exprFuncArity[name.text]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown function %q at %d", name.text, name.pos)
+	}
+	if err := p.expectOp("("); err != nil {
+		return nil, nil, err
+	}
+
+	var args []exprNode
+	for !p.isOp(")") {
+		if len(args) > 0 {
+			if err := p.expectOp(","); err != nil {
+				return nil, nil, err
+			}
+		}
+		arg, _, err := p.parseBinary(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+
+	if len(args) != arity {
+		return nil, nil, fmt.Errorf("%s() expects %d argument(s), got %d", name.text, arity, len(args))
+	}
+
+	var typ reflect.Type
+	if name.text == "now" {
+		typ = timeType
+	}
+	return &exprCall{name: name.text, args: args}, typ, nil
+}
+
+// newExprSelector 创建字段访问节点，静态类型已知时在编译期解析字段索引
+func newExprSelector(target exprNode, typ reflect.Type, name string) (exprNode, reflect.Type, error) {
+	node := &exprSelector{target: target, name: name}
+	if typ == nil {
+		return node, nil, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		sf, ok := typ.FieldByName(name)
+		if !ok || !sf.IsExported() {
+			return nil, nil, fmt.Errorf("unknown field %q in %s", name, typ)
+		}
+		node.index = sf.Index
+		return node, indirectType(sf.Type), nil
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return nil, nil, fmt.Errorf("cannot access %q on %s", name, typ)
+		}
+		return node, indirectType(typ.Elem()), nil
+	}
+	return nil, nil, fmt.Errorf("cannot access %q on %s", name, typ)
+}
+
+// ---------------------------------------------------------------------------
+// 求值
+// ---------------------------------------------------------------------------
+
+type exprLiteral struct {
+	value any
+}
+
+func (n *exprLiteral) eval(*exprEnv) (any, error) {
+	return n.value, nil
+}
+
+type exprRoot struct {
+	name string
+}
+
+func (n *exprRoot) eval(env *exprEnv) (any, error) {
+	switch n.name {
+	case "this":
+		return exprValue(env.this), nil
+	case "parent":
+		return exprValue(env.parent), nil
+	default:
+		return exprValue(env.top), nil
+	}
+}
+
+type exprSelector struct {
+	target exprNode
+	name   string
+	index  []int // 编译期解析的字段索引，nil 表示运行时按名查找
+}
+
+func (n *exprSelector) eval(env *exprEnv) (any, error) {
+	target, err := n.target.eval(env)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	rv, ok := target.(reflect.Value)
+	if !ok {
+		return nil, fmt.Errorf("expr: cannot access %q on %T", n.name, target)
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if n.index != nil {
+			field, err := rv.FieldByIndexErr(n.index)
+			if err != nil {
+				// 嵌入的 nil 指针
+				return nil, nil
+			}
+			return exprValue(field), nil
+		}
+		sf, ok := rv.Type().FieldByName(n.name)
+		if !ok || !sf.IsExported() {
+			return nil, fmt.Errorf("expr: unknown field %q in %s", n.name, rv.Type())
+		}
+		field, err := rv.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return nil, nil
+		}
+		return exprValue(field), nil
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		return exprValue(rv.MapIndex(reflect.ValueOf(n.name).Convert(rv.Type().Key()))), nil
+	}
+	return nil, fmt.Errorf("expr: cannot access %q on %s", n.name, rv.Type())
+}
+
+type exprIndex struct {
+	target exprNode
+	index  exprNode
+}
+
+func (n *exprIndex) eval(env *exprEnv) (any, error) {
+	target, err := n.target.eval(env)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	index, err := n.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	rv, ok := target.(reflect.Value)
+	if !ok {
+		return nil, fmt.Errorf("expr: cannot index %T", target)
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, ok := index.(int64)
+		if !ok {
+			return nil, fmt.Errorf("expr: index must be an integer, got %T", index)
+		}
+		if i < 0 || i >= int64(rv.Len()) {
+			return nil, fmt.Errorf("expr: index %d out of range [0:%d]", i, rv.Len())
+		}
+		return exprValue(rv.Index(int(i))), nil
+
+	case reflect.Map:
+		key := reflect.ValueOf(index)
+		keyType := rv.Type().Key()
+		if !key.IsValid() || !key.Type().ConvertibleTo(keyType) {
+			return nil, fmt.Errorf("expr: invalid map key %v for %s", index, rv.Type())
+		}
+		return exprValue(rv.MapIndex(key.Convert(keyType))), nil
+	}
+	return nil, fmt.Errorf("expr: cannot index %s", rv.Type())
+}
+
+type exprCall struct {
+	name string
+	args []exprNode
+}
+
+func (n *exprCall) eval(env *exprEnv) (any, error) {
+	switch n.name {
+	case "now":
+		return time.Now(), nil
+	default: // len
+		arg, err := n.args[0].eval(env)
+		if err != nil {
+			return nil, err
+		}
+		switch v := arg.(type) {
+		case nil:
+			return int64(0), nil
+		case string:
+			return int64(len(v)), nil
+		case reflect.Value:
+			switch v.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				return int64(v.Len()), nil
+			}
+		}
+		return nil, fmt.Errorf("expr: invalid argument %T for len()", arg)
+	}
+}
+
+type exprUnary struct {
+	op      string
+	operand exprNode
+}
+
+func (n *exprUnary) eval(env *exprEnv) (any, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expr: operator ! not defined on %T", v)
+		}
+		return !b, nil
+	}
+
+	switch x := v.(type) {
+	case int64:
+		return -x, nil
+	case float64:
+		return -x, nil
+	}
+	return nil, fmt.Errorf("expr: operator - not defined on %T", v)
+}
+
+type exprBinary struct {
+	op    string
+	left  exprNode
+	right exprNode
+}
+
+func (n *exprBinary) eval(env *exprEnv) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逻辑运算短路求值
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expr: operator %s not defined on %T", n.op, left)
+		}
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return l, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expr: operator %s not defined on %T", n.op, right)
+		}
+		return r, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return exprEqual(left, right), nil
+	case "!=":
+		return !exprEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		cmp, err := exprCompare(left, right)
+		if err != nil {
+			return nil, fmt.Errorf("expr: operator %s: %w", n.op, err)
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+	return exprArith(n.op, left, right)
+}
+
+// exprValue 将反射值转换为求值使用的值：
+// 整数（含 time.Duration）→ int64，浮点 → float64，string/bool/time.Time 保持原样，
+// nil 指针/接口 → nil，结构体/切片/映射等复合值保留为 reflect.Value
+func exprValue(v reflect.Value) any {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > 1<<63-1 {
+			return float64(u)
+		}
+		return int64(u)
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time)
+		}
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	return v
+}
+
+// exprEqual 判断两个值是否相等，数字按数值比较，类型不同视为不等
+func exprEqual(left, right any) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if l, r, ok := exprNumbers(left, right); ok {
+		return l == r
+	}
+
+	switch l := left.(type) {
+	case time.Time:
+		r, ok := right.(time.Time)
+		return ok && l.Equal(r)
+	case reflect.Value:
+		r, ok := right.(reflect.Value)
+		return ok && reflect.DeepEqual(l.Interface(), r.Interface())
+	}
+	return left == right
+}
+
+// exprCompare 比较两个有序值（数字、字符串、time.Time）
+func exprCompare(left, right any) (int, error) {
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if l, r, ok := exprNumbers(left, right); ok {
+		switch {
+		case l < r:
+			return -1, nil
+		case l > r:
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case time.Time:
+		if r, ok := right.(time.Time); ok {
+			return l.Compare(r), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T and %T", left, right)
+}
+
+// exprArith 算术运算：整数之间保持整数，与浮点混合时提升为浮点；
+// 另支持字符串拼接、time.Time ± 时长、time.Time - time.Time
+func exprArith(op string, left, right any) (any, error) {
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			switch op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/", "%":
+				if r == 0 {
+					return nil, fmt.Errorf("expr: division by zero")
+				}
+				if op == "/" {
+					return l / r, nil
+				}
+				return l % r, nil
+			}
+		}
+	}
+
+	if l, r, ok := exprNumbers(left, right); ok {
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			if r == 0 {
+				return nil, fmt.Errorf("expr: division by zero")
+			}
+			return l / r, nil
+		}
+	}
+
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok && op == "+" {
+			return l + r, nil
+		}
+	case time.Time:
+		switch r := right.(type) {
+		case time.Time:
+			if op == "-" {
+				return int64(l.Sub(r)), nil
+			}
+		case int64:
+			switch op {
+			case "+":
+				return l.Add(time.Duration(r)), nil
+			case "-":
+				return l.Add(-time.Duration(r)), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("expr: operator %s not defined on %T and %T", op, left, right)
+}
+
+// exprNumbers 将两个数字值统一转换为 float64
+func exprNumbers(left, right any) (float64, float64, bool) {
+	l, ok := exprFloat(left)
+	if !ok {
+		return 0, 0, false
+	}
+	r, ok := exprFloat(right)
+	if !ok {
+		return 0, 0, false
+	}
+	return l, r, true
+}
+
+func exprFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
@@ -0,0 +1,266 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xlanguage "golang.org/x/text/language"
+)
+
+type exprWindow struct {
+	Start time.Time
+	End   time.Time
+	Items []string
+	Max   int
+}
+
+type exprOrder struct {
+	Kind     string            `json:"kind"`
+	Quantity int               `json:"quantity" validate:"expr=this > 0 && this <= parent.Limit"`
+	Limit    int               `json:"limit"`
+	Discount float64           `json:"discount" validate:"expr=this == 0 || parent.Kind == 'vip'"`
+	Window   exprWindow        `json:"window" validate:"expr=this.End > this.Start && len(this.Items) <= this.Max"`
+	Lines    []exprOrderLine   `json:"lines" validate:"dive"`
+	Meta     map[string]string `json:"meta" validate:"expr=this == nil || len(this['source']) > 0"`
+	Note     *string           `json:"note" validate:"expr=this == nil || len(this) <= 10"`
+}
+
+type exprOrderLine struct {
+	Price int `json:"price" validate:"expr=this * top.Quantity <= 1000 || top.Kind == 'a,b'"`
+}
+
+func validExprOrder() exprOrder {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return exprOrder{
+		Kind:     "vip",
+		Quantity: 2,
+		Limit:    5,
+		Discount: 0.1,
+		Window:   exprWindow{Start: start, End: start.Add(time.Hour), Items: []string{"a"}, Max: 2},
+		Lines:    []exprOrderLine{{Price: 100}},
+	}
+}
+
+func TestExprStruct(t *testing.T) {
+	v, err := New(WithLocale(xlanguage.Make("en")))
+	require.NoError(t, err)
+
+	assert.NoError(t, v.Struct(validExprOrder()))
+
+	o := validExprOrder()
+	o.Quantity = 6
+	o.Kind = "normal"
+	o.Window.End = o.Window.Start
+	o.Meta = map[string]string{"other": "x"}
+	note := "a very long note"
+	o.Note = &note
+
+	err = v.Struct(o)
+	require.Error(t, err)
+	errs := err.(ValidationErrors)
+	assert.Equal(t, []string{"quantity", "discount", "window", "meta", "note"}, errs.Fields())
+	assert.Equal(t, "quantity must satisfy the expression this > 0 && this <= parent.Limit", errs.ByField("quantity").Message)
+	assert.Equal(t, "expr", errs.ByField("window").Tag)
+}
+
+func TestExprTopAndCommaInTag(t *testing.T) {
+	e := NewEngine()
+
+	o := validExprOrder()
+	o.Lines = []exprOrderLine{{Price: 400}, {Price: 600}}
+
+	// Price 上的表达式包含逗号，整段都应作为表达式参数；top 指向顶级结构体
+	err := e.Struct(&o)
+	require.Error(t, err)
+	fe := err.(ValidationErrors).First()
+	assert.Equal(t, "Lines[1].Price", fe.Namespace)
+	assert.Equal(t, "this * top.Quantity <= 1000 || top.Kind == 'a,b'", fe.Param)
+
+	rules := e.parseTag("required, min=1, expr=len(this) > 1, this != 'x'")
+	assert.Equal(t, []validationRule{
+		{tag: "required"},
+		{tag: "min", param: "1"},
+		{tag: "expr", param: "len(this) > 1, this != 'x'"},
+	}, rules)
+}
+
+func TestExprVar(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value interface{}
+		expr  string
+		want  bool
+	}{
+		{"int compare", 5, "this >= 5 && this < 10", true},
+		{"arith precedence", 3, "this + 2 * 3 == 9", true},
+		{"parens", 3, "(this + 2) * 3 == 15", true},
+		{"int division", 7, "this / 2 == 3 && this % 2 == 1", true},
+		{"float mix", 1.5, "this * 2 == 3", true},
+		{"negation", 3, "-this < 0 && !(this == 0)", true},
+		{"string concat", "ab", "this + 'c' == \"abc\"", true},
+		{"string escape", "it's", `this == 'it\'s'`, true},
+		{"string compare", "b", "this > 'a'", true},
+		{"len string", "hello", "len(this) == 5", true},
+		{"len slice", []int{1, 2}, "len(this) == 2 && this[1] == 2", true},
+		{"map index", map[string]int{"a": 1}, "this['a'] == 1 && this.a == 1 && this['b'] == nil", true},
+		{"duration literal", 90 * time.Minute, "this == 1h30m && this > 500ms", true},
+		{"time arithmetic", start, "this + 1h > this && (this + 2h) - this == 2h", true},
+		{"now", start, "this < now()", true},
+		{"uint", uint8(200), "this > 100", true},
+		{"bool", true, "this", true},
+		{"nil pointer", (*int)(nil), "this == nil", true},
+		{"short circuit", 0, "this != 0 && 10 / this > 1", false},
+		{"false", 1, "this > 1", false},
+		{"mismatched equal", "1", "this == 1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Var(tt.value, "expr="+tt.expr)
+			if tt.want {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	type inner struct {
+		A int
+		b int
+	}
+	type S struct {
+		In  inner
+		Any interface{}
+	}
+	s := reflect.ValueOf(S{In: inner{A: 1}, Any: inner{A: 2}})
+
+	tests := []struct {
+		name  string
+		field reflect.Value
+		expr  string
+		err   string
+	}{
+		{"syntax", s.Field(0), "this.A >", "unexpected end of expression"},
+		{"unknown identifier", s.Field(0), "foo > 1", `unknown identifier "foo"`},
+		{"unknown function", s.Field(0), "exec('rm')", `unknown function "exec"`},
+		{"arity", s.Field(0), "len() == 0", "len() expects 1 argument(s), got 0"},
+		{"unknown field", s.Field(0), "this.C == 1", `unknown field "C"`},
+		{"unexported field", s.Field(0), "this.b == 1", `unknown field "b"`},
+		{"field on scalar", s.Field(0), "this.A.B == 1", `cannot access "B"`},
+		{"dynamic unexported", s.Field(1), "this.b == 1", `unknown field "b"`},
+		{"not bool", s.Field(0), "this.A + 1", "must evaluate to bool"},
+		{"compare types", s.Field(0), "this.A > 'x'", "cannot compare int64 and string"},
+		{"division by zero", s.Field(0), "this.A / 0 == 1", "division by zero"},
+		{"bad operand", s.Field(0), "!this.A", "operator ! not defined"},
+		{"unterminated", s.Field(0), "'abc", "unterminated string"},
+		{"bad char", s.Field(0), "this.A # 1", "unexpected character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &fieldLevel{top: s, parent: s, field: tt.field, param: tt.expr}
+			_, err := evalExpr(fl)
+			assert.ErrorContains(t, err, tt.err)
+			assert.False(t, validateExpr(fl))
+		})
+	}
+
+	// 运行时按名解析的字段
+	fl := &fieldLevel{top: s, parent: s, field: s.Field(1), param: "this.A == 2"}
+	ok, err := evalExpr(fl)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestExprCompileError(t *testing.T) {
+	type bad struct {
+		Name string `validate:"required"`
+		A    int    `validate:"expr=this >"`
+	}
+	type unknown struct {
+		A int `validate:"expr=parent.Missing > 0"`
+	}
+	type diveBad struct {
+		Items []exprWindow `validate:"dive,expr=this.Nope"`
+	}
+	type outer struct {
+		Name  string    `validate:"required"`
+		Inner []unknown `validate:"dive"`
+	}
+
+	e := NewEngine()
+
+	// 构建计划时编译失败，不执行任何规则，也不是 ValidationErrors
+	err := e.Struct(&bad{})
+	var exprErr *ExprError
+	require.True(t, errors.As(err, &exprErr))
+	assert.Equal(t, "validator.bad.A", exprErr.Field)
+	assert.Equal(t, "this >", exprErr.Expr)
+	assert.ErrorContains(t, err, "unexpected end of expression")
+	var errs ValidationErrors
+	assert.False(t, errors.As(err, &errs))
+
+	err = e.Struct(unknown{})
+	require.True(t, errors.As(err, &exprErr))
+	assert.ErrorContains(t, err, `unknown field "Missing"`)
+
+	err = e.Struct(diveBad{Items: []exprWindow{{}}})
+	require.True(t, errors.As(err, &exprErr))
+	assert.Equal(t, "validator.diveBad.Items", exprErr.Field)
+
+	// 嵌套结构体的编译错误与其他字段的验证失败一起返回，同一类型只报告一次
+	err = e.Struct(outer{Inner: []unknown{{}, {}}})
+	require.True(t, errors.As(err, &exprErr))
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, []string{"Name"}, errs.Fields())
+	assert.Equal(t, 1, strings.Count(err.Error(), "validator.unknown.A"))
+
+	// top 在运行时才确定
+	type topRef struct {
+		A int `validate:"expr=top.Missing > 0"`
+	}
+	err = e.Struct(topRef{})
+	require.True(t, errors.As(err, &exprErr))
+	assert.Equal(t, "A", exprErr.Field)
+
+	err = e.Var(1, "expr=this >")
+	require.True(t, errors.As(err, &exprErr))
+	assert.Equal(t, "var", exprErr.Field)
+
+	// 同名规则被覆盖后不再按表达式编译
+	require.NoError(t, e.RegisterValidation("expr", func(fl FieldLevel) bool { return false }))
+	assert.Equal(t, []string{"A"}, namespacesOf(t, e.Struct(&bad{Name: "x"})))
+}
+
+func TestExprCache(t *testing.T) {
+	type S struct {
+		A int `validate:"expr=this > 1"`
+	}
+	e := NewEngine()
+	assert.NoError(t, e.Struct(S{A: 2}))
+	assert.Error(t, e.Struct(S{A: 1}))
+
+	typ := reflect.TypeOf(0)
+	cached, ok := exprCache.Load(exprCacheKey{expr: "this > 1", this: typ, parent: reflect.TypeOf(S{}), top: reflect.TypeOf(S{})})
+	require.True(t, ok)
+	assert.Same(t, cached, compileExpr("this > 1", typ, reflect.TypeOf(S{}), reflect.TypeOf(S{})))
+}
+
+func TestExprLocale(t *testing.T) {
+	type S struct {
+		A int `json:"a" validate:"expr=this > 1"`
+	}
+	v, err := New(WithLocale(xlanguage.Make("zh")))
+	require.NoError(t, err)
+
+	err = v.Struct(S{A: 1})
+	require.Error(t, err)
+	assert.Equal(t, "a必须满足表达式this > 1", err.(ValidationErrors).First().Message)
+}
@@ -278,13 +278,6 @@ func TestInNotInIntPaths(t *testing.T) {
 	assert.False(t, gn(paramFL{field: reflect.ValueOf(2)}))
 }
 
-func TestOpt11SliceNilPtr(t *testing.T) {
-	e := NewEngine()
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf([]int{})}, "required"))
-	var p *string
-	assert.False(t, e.validateField_Opt11_InlinedValidators(&fieldLevel{field: reflect.ValueOf(p)}, "required"))
-}
-
 // ===== registerDefaultValidators: cover more registration paths =====
 
 func TestRegisterAllValidatorTypes(t *testing.T) {
@@ -152,6 +152,7 @@ func init() {
 			"excluded_with_all":    "{field} is excluded when all of {param} are present",
 			"excluded_without":     "{field} is excluded when {param} is not present",
 			"excluded_without_all": "{field} is excluded when none of {param} are present",
+			"expr":                 "{field} must satisfy the expression {param}",
 
 			// 自定义验证规则
 			"strong_password": "{field} must be a strong password (at least 8 characters with uppercase, lowercase, numbers, and special characters)",
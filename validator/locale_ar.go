@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} مستبعد عندما جميع {param} موجودة",
 			"excluded_without":     "{field} مستبعد عندما {param} غير موجود",
 			"excluded_without_all": "{field} مستبعد عندما لا يوجد أي من {param}",
+			"expr":                 "{field} يجب أن يحقق التعبير {param}",
 
 			// قواعد التحقق المخصصة
 			"strong_password": "{field} يجب أن يكون كلمة مرور قوية (8 أحرف على الأقل مع أحرف كبيرة وصغيرة وأرقام ورموز خاصة)",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} ist ausgeschlossen, wenn alle {param} vorhanden sind",
 			"excluded_without":     "{field} ist ausgeschlossen, wenn {param} nicht vorhanden ist",
 			"excluded_without_all": "{field} ist ausgeschlossen, wenn keines der {param} vorhanden ist",
+			"expr":                 "{field} muss den Ausdruck {param} erfüllen",
 
 			// Benutzerdefinierte Validierungsregeln
 			"strong_password": "{field} muss ein starkes Passwort sein (mindestens 8 Zeichen mit Groß-, Kleinbuchstaben, Zahlen und Sonderzeichen)",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} está excluido cuando todos los {param} están presentes",
 			"excluded_without":     "{field} está excluido cuando {param} no está presente",
 			"excluded_without_all": "{field} está excluido cuando ninguno de los {param} está presente",
+			"expr":                 "{field} debe cumplir la expresión {param}",
 
 			// Reglas de validación personalizadas
 			"strong_password": "{field} debe ser una contraseña segura (al menos 8 caracteres con mayúsculas, minúsculas, números y caracteres especiales)",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} est exclu quand tous les {param} sont présents",
 			"excluded_without":     "{field} est exclu quand {param} n'est pas présent",
 			"excluded_without_all": "{field} est exclu quand aucun des {param} n'est présent",
+			"expr":                 "{field} doit satisfaire l'expression {param}",
 
 			// Règles de validation personnalisées
 			"strong_password": "{field} doit être un mot de passe fort (au moins 8 caractères avec majuscules, minuscules, chiffres et caractères spéciaux)",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} è escluso quando tutti i {param} sono presenti",
 			"excluded_without":     "{field} è escluso quando {param} non è presente",
 			"excluded_without_all": "{field} è escluso quando nessuno dei {param} è presente",
+			"expr":                 "{field} deve soddisfare l'espressione {param}",
 
 			// Regole di validazione personalizzate
 			"strong_password": "{field} deve essere una password forte (almeno 8 caratteri con maiuscole, minuscole, numeri e caratteri speciali)",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "すべての{param}が存在する場合、{field}は存在してはいけません",
 			"excluded_without":     "{param}が存在しない場合、{field}は存在してはいけません",
 			"excluded_without_all": "いずれの{param}も存在しない場合、{field}は存在してはいけません",
+			"expr":                 "{field}は式{param}を満たす必要があります",
 
 			// カスタム検証ルール
 			"strong_password": "{field}は強力なパスワードである必要があります（8文字以上で大文字、小文字、数字、特殊文字を含む）",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "모든 {param}이 있을 때 {field}은(는) 있으면 안 됩니다",
 			"excluded_without":     "{param}이 없을 때 {field}은(는) 있으면 안 됩니다",
 			"excluded_without_all": "모든 {param}이 없을 때 {field}은(는) 있으면 안 됩니다",
+			"expr":                 "{field}은(는) 식 {param}을(를) 만족해야 합니다",
 
 			// 사용자 정의 검증 규칙
 			"strong_password": "{field}은(는) 강력한 비밀번호여야 합니다 (8자 이상, 대문자, 소문자, 숫자, 특수문자 포함)",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} é excluído quando todos os {param} estão presentes",
 			"excluded_without":     "{field} é excluído quando {param} não está presente",
 			"excluded_without_all": "{field} é excluído quando nenhum dos {param} está presente",
+			"expr":                 "{field} deve satisfazer a expressão {param}",
 
 			// Regras de validação personalizadas
 			"strong_password": "{field} deve ser uma senha forte (pelo menos 8 caracteres com maiúsculas, minúsculas, números e caracteres especiais)",
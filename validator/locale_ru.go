@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "{field} исключено при наличии всех {param}",
 			"excluded_without":     "{field} исключено при отсутствии {param}",
 			"excluded_without_all": "{field} исключено при отсутствии всех {param}",
+			"expr":                 "{field} должно удовлетворять выражению {param}",
 
 			// Пользовательские правила валидации
 			"strong_password": "{field} должно быть сильным паролем (не менее 8 символов с заглавными, строчными буквами, цифрами и специальными символами)",
@@ -100,6 +100,7 @@ func init() {
 			"excluded_with_all":    "当存在所有{param}时，{field}不能存在",
 			"excluded_without":     "当不存在{param}时，{field}不能存在",
 			"excluded_without_all": "当不存在任何{param}时，{field}不能存在",
+			"expr":                 "{field}必须满足表达式{param}",
 
 			// 自定义验证规则
 			"strong_password": "{field}必须是强密码（至少8位，包含大写字母、小写字母、数字和特殊字符）",
@@ -269,6 +270,7 @@ func init() {
 			"excluded_with_all":    "当存在所有{param}时，{field}不能存在",
 			"excluded_without":     "当不存在{param}时，{field}不能存在",
 			"excluded_without_all": "当不存在任何{param}时，{field}不能存在",
+			"expr":                 "{field}必须满足表达式{param}",
 
 			// 自定义验证规则
 			"strong_password": "{field}必须是强密码（至少8位，包含大写字母、小写字母、数字和特殊字符）",
@@ -102,6 +102,7 @@ func init() {
 			"excluded_with_all":    "當存在所有{param}時，{field}不能存在",
 			"excluded_without":     "當不存在{param}時，{field}不能存在",
 			"excluded_without_all": "當不存在任何{param}時，{field}不能存在",
+			"expr":                 "{field}必須滿足表達式{param}",
 
 			// 自定義驗證規則
 			"strong_password": "{field}必須是強密碼（至少8位，包含大寫字母、小寫字母、數字和特殊字符）",
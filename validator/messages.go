@@ -20,6 +20,7 @@
 	"mac":               "{field} 必须是有效的 MAC 地址",
 	"json":              "{field} 必须是有效的 JSON",
 	"uuid":              "{field} 必须是有效的 UUID",
+	"expr":              "{field} 必须满足表达式 {param}",
 }
 
 // formatMessage 格式化错误消息，零分配快速路径。
@@ -0,0 +1,593 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPI 导出：按结构体类型和 validate 标签生成 OpenAPI 3.1 Schema 对象（即 JSON Schema 2020-12），
+// 用于 API 文档，避免在文档中手工重复维护约束：
+//
+//	schema, _ := e.OpenAPISchema(CreateUserReq{})
+//	doc, _ := json.MarshalIndent(schema, "", "  ")
+//
+// 能映射的规则转换为对应关键字，无法映射的规则原样列在 x-validate 扩展中。
+// 属性名与 encoding/json 一致；属性按名称排序、x-validate 按标签顺序输出，结果稳定可 diff。
+// SchemaOption 可替换属性名、特定类型的 schema，并在字段/结构体生成后补充关键字，
+// 用于在同一套映射之上生成其他文档（如 config.GenerateSchema 生成的配置文件 JSON Schema）。
+
+// SchemaType 是 Schema 的 type，只有一个类型时序列化为字符串
+type SchemaType []string
+
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Has 判断是否包含指定类型
+func (t SchemaType) Has(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema OpenAPI 3.1 Schema 对象中由验证规则生成的子集，
+// $schema/$id/title/description/default/not 不由规则生成，留给调用方填写
+type Schema struct {
+	Schema      string      `json:"$schema,omitempty"`
+	ID          string      `json:"$id,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Not         *Schema     `json:"not,omitempty"`
+
+	Type            SchemaType    `json:"type,omitempty"`
+	Format          string        `json:"format,omitempty"`
+	Pattern         string        `json:"pattern,omitempty"`
+	ContentEncoding string        `json:"contentEncoding,omitempty"`
+	Enum            []interface{} `json:"enum,omitempty"`
+	Const           interface{}   `json:"const,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	MinProperties    *int     `json:"minProperties,omitempty"`
+	MaxProperties    *int     `json:"maxProperties,omitempty"`
+
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+
+	// AllOf 同一字段有多条 pattern 规则时，第一条写入 Pattern，其余放在这里
+	AllOf []*Schema `json:"allOf,omitempty"`
+
+	// XValidate 无法映射为 Schema 关键字的规则，保持标签中的写法与顺序
+	XValidate []string `json:"x-validate,omitempty"`
+}
+
+// SchemaOption 定制 Schema 的生成
+type SchemaOption func(*schemaGenerator)
+
+// WithSchemaPropertyName 替换属性名的解析，返回属性名及是否显式指定（未显式指定的匿名结构体展开到当前层），
+// 属性名为 "-" 时跳过该字段；缺省与 encoding/json 一致
+func WithSchemaPropertyName(fn func(field reflect.StructField) (string, bool)) SchemaOption {
+	return func(g *schemaGenerator) {
+		g.propertyName = fn
+	}
+}
+
+// WithSchemaTypeFunc 为特定类型提供 schema，返回 nil 时按默认规则生成
+func WithSchemaTypeFunc(fn func(t reflect.Type) *Schema) SchemaOption {
+	return func(g *schemaGenerator) {
+		g.typeFunc = fn
+	}
+}
+
+// WithSchemaFieldFunc 在字段的 schema（含规则）生成后调用，parent 为声明该字段的结构体类型
+func WithSchemaFieldFunc(fn func(prop *Schema, parent reflect.Type, field reflect.StructField)) SchemaOption {
+	return func(g *schemaGenerator) {
+		g.fieldFunc = fn
+	}
+}
+
+// WithSchemaStructFunc 在结构体的属性全部生成后调用
+func WithSchemaStructFunc(fn func(schema *Schema, t reflect.Type)) SchemaOption {
+	return func(g *schemaGenerator) {
+		g.structFunc = fn
+	}
+}
+
+// OpenAPISchema 由结构体（或其指针）类型生成 OpenAPI 3.1 Schema，只读取类型与标签，不验证值
+func (e *Engine) OpenAPISchema(v interface{}, opts ...SchemaOption) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %v", t)
+	}
+
+	g := &schemaGenerator{e: e, visiting: make(map[reflect.Type]bool), propertyName: jsonPropertyName}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g.schemaFor(t), nil
+}
+
+// OpenAPISchemaJSON 生成缩进格式的 Schema JSON
+func (e *Engine) OpenAPISchemaJSON(v interface{}, opts ...SchemaOption) ([]byte, error) {
+	schema, err := e.OpenAPISchema(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// schemaFormats 映射为 format 的规则（仅作用于字符串字段）
+var schemaFormats = map[string]string{
+	"email":            "email",
+	"url":              "uri",
+	"uri":              "uri",
+	"http_url":         "uri",
+	"uuid":             "uuid",
+	"uuid_rfc4122":     "uuid",
+	"uuid3":            "uuid",
+	"uuid3_rfc4122":    "uuid",
+	"uuid4":            "uuid",
+	"uuid4_rfc4122":    "uuid",
+	"uuid5":            "uuid",
+	"uuid5_rfc4122":    "uuid",
+	"ipv4":             "ipv4",
+	"ip4_addr":         "ipv4",
+	"ipv6":             "ipv6",
+	"ip6_addr":         "ipv6",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"fqdn":             "hostname",
+}
+
+// schemaDatetimeFormats datetime=<layout> 中可以映射为 format 的布局
+var schemaDatetimeFormats = map[string]string{
+	time.RFC3339:     "date-time",
+	time.RFC3339Nano: "date-time",
+	time.DateOnly:    "date",
+	time.TimeOnly:    "time",
+}
+
+// schemaPatterns 由正则实现的规则，直接导出验证器使用的正则（仅作用于字符串字段）
+var schemaPatterns = map[string]*regexp.Regexp{
+	"alpha":                     alphaRegex,
+	"alphanum":                  alphanumRegex,
+	"hexcolor":                  hexcolorRegex,
+	"e164":                      e164Regex,
+	"ein":                       einRegex,
+	"ssn":                       ssnRegex,
+	"btc_addr":                  btcAddrRegex,
+	"btc_addr_bech32":           btcBech32Regex,
+	"eth_addr":                  ethAddrRegex,
+	"iso3166_1_alpha2":          isoAlpha2Regex,
+	"iso3166_1_alpha3":          isoAlpha3Regex,
+	"iso3166_1_alpha_numeric":   isoNumericRegex,
+	"iso3166_2":                 iso31662Regex,
+	"iso4217":                   iso4217Regex,
+	"postcode_iso3166_alpha2":   postcodeRegex,
+	"semver":                    semverRegex,
+	"ulid":                      ulidRegex,
+	"cve":                       cveRegex,
+	"jwt":                       jwtRegex,
+	"html":                      htmlTagRegex,
+	"html_encoded":              htmlEncodedRegex,
+	"mongodb":                   mongodbOIDRegex,
+	"mongodb_connection_string": mongoConnRegex,
+	"spicedb":                   spicedbRegex,
+	"bic":                       bicRegex,
+	"bic_iso_9362_2014":         bicRegex,
+	"bcp47_language_tag":        bcp47Regex,
+	"bcp47_strict_language_tag": bcp47Regex,
+	"lowercase":                 regexp.MustCompile(`^[a-z]+$`),
+	"uppercase":                 regexp.MustCompile(`^[A-Z]+$`),
+	"number":                    regexp.MustCompile(`^[0-9]+$`),
+}
+
+type schemaGenerator struct {
+	e        *Engine
+	visiting map[reflect.Type]bool
+
+	propertyName func(field reflect.StructField) (string, bool)
+	typeFunc     func(t reflect.Type) *Schema
+	fieldFunc    func(prop *Schema, parent reflect.Type, field reflect.StructField)
+	structFunc   func(schema *Schema, t reflect.Type)
+}
+
+// schemaFor 生成类型 t 的 schema，指针类型允许 null
+func (g *schemaGenerator) schemaFor(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	schema := g.schemaForElem(t)
+	if nullable && len(schema.Type) > 0 {
+		schema.Type = append(schema.Type, "null")
+	}
+	return schema
+}
+
+func (g *schemaGenerator) schemaForElem(t reflect.Type) *Schema {
+	if g.typeFunc != nil {
+		if schema := g.typeFunc(t); schema != nil {
+			return schema
+		}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: SchemaType{"string"}, Format: "date-time"}
+	case durationType:
+		// encoding/json 输出纳秒整数
+		return &Schema{Type: SchemaType{"integer"}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: SchemaType{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: SchemaType{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: SchemaType{"integer"}, Minimum: float64Ptr(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaType{"number"}}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json 把 []byte 编码为 base64 字符串
+			return &Schema{Type: SchemaType{"string"}, ContentEncoding: "base64"}
+		}
+		return &Schema{Type: SchemaType{"array"}, Items: g.schemaFor(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: SchemaType{"object"}, AdditionalProperties: g.schemaFor(t.Elem())}
+
+	case reflect.Struct:
+		if g.visiting[t] {
+			// 递归类型不再展开
+			return &Schema{Type: SchemaType{"object"}}
+		}
+		g.visiting[t] = true
+		defer delete(g.visiting, t)
+
+		schema := &Schema{Type: SchemaType{"object"}, Properties: make(map[string]*Schema)}
+		g.fillProperties(schema, t)
+		if g.structFunc != nil {
+			g.structFunc(schema, t)
+		}
+		return schema
+
+	default:
+		// interface 等任意值
+		return &Schema{}
+	}
+}
+
+// fillProperties 填充结构体属性，未命名的匿名结构体按 encoding/json 规则展开到当前层
+func (g *schemaGenerator) fillProperties(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, explicit := g.propertyName(field)
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && !explicit {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fillProperties(schema, ft)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		prop := g.schemaFor(field.Type)
+		if tag := field.Tag.Get(g.e.tagName); tag != "" && tag != "-" {
+			if g.applyRules(prop, field.Type, g.e.parseTag(tag)) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		if g.fieldFunc != nil {
+			g.fieldFunc(prop, t, field)
+		}
+		schema.Properties[name] = prop
+	}
+}
+
+// jsonPropertyName 返回 encoding/json 使用的属性名及是否在 json 标签中显式指定
+func jsonPropertyName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "-", true
+	}
+	if idx := strings.IndexByte(tag, ','); idx != -1 {
+		tag = tag[:idx]
+	}
+	if tag != "" {
+		return tag, true
+	}
+	return field.Name, false
+}
+
+// applyRules 把规则写入 schema，返回是否 required
+func (g *schemaGenerator) applyRules(schema *Schema, t reflect.Type, rules []validationRule) (required bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i, rule := range rules {
+		switch rule.tag {
+		case "omitempty":
+			continue
+		case "required":
+			required = true
+			continue
+		case "dive":
+			// dive 之后的规则全部作用于下一层
+			g.applyDive(schema, t, rule, rules[i+1:])
+			return required
+		default:
+			if applySchemaRule(schema, t, rule) {
+				continue
+			}
+		}
+
+		schema.XValidate = append(schema.XValidate, ruleString(rule))
+	}
+	return required
+}
+
+// applyDive 把 dive 之后的规则写入下一层：元素规则写入 items/additionalProperties，
+// 键规则写入 propertyNames；不是数组或映射的字段保留原始写法
+func (g *schemaGenerator) applyDive(schema *Schema, t reflect.Type, dive validationRule, rest []validationRule) {
+	var elem *Schema
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		elem = schema.Items
+	case reflect.Map:
+		elem = schema.AdditionalProperties
+	}
+	if elem == nil {
+		schema.XValidate = append(schema.XValidate, ruleString(dive))
+		for _, rule := range rest {
+			schema.XValidate = append(schema.XValidate, ruleString(rule))
+		}
+		return
+	}
+
+	keys, elemRules := g.e.splitDive(dive.param, rest)
+	if len(keys) > 0 && t.Kind() == reflect.Map {
+		schema.PropertyNames = g.schemaFor(t.Key())
+		g.applyRules(schema.PropertyNames, t.Key(), keys)
+	}
+	g.applyRules(elem, t.Elem(), elemRules)
+}
+
+// applySchemaRule 把单条规则映射为 schema 关键字，无法映射时返回 false
+func applySchemaRule(schema *Schema, t reflect.Type, rule validationRule) bool {
+	switch rule.tag {
+	case "min", "gte":
+		return applySchemaBound(schema, t, rule.param, false, false)
+	case "max", "lte":
+		return applySchemaBound(schema, t, rule.param, true, false)
+	case "gt":
+		return applySchemaBound(schema, t, rule.param, false, true)
+	case "lt":
+		return applySchemaBound(schema, t, rule.param, true, true)
+	case "len":
+		n, err := strconv.Atoi(rule.param)
+		if err != nil || !setSchemaSize(schema, t, n, false) {
+			return false
+		}
+		return setSchemaSize(schema, t, n, true)
+	case "eq":
+		value, ok := schemaEnumValue(t, rule.param)
+		if ok {
+			schema.Const = value
+		}
+		return ok
+	case "oneof":
+		if rule.param == "" {
+			return false
+		}
+		// 与 validateOneOf 一致，参数以逗号分隔
+		var values []interface{}
+		for _, item := range strings.Split(rule.param, ",") {
+			value, ok := schemaEnumValue(t, item)
+			if !ok {
+				return false
+			}
+			values = append(values, value)
+		}
+		schema.Enum = values
+		return true
+	}
+
+	if t.Kind() != reflect.String {
+		return false
+	}
+
+	switch rule.tag {
+	case "datetime":
+		format, ok := schemaDatetimeFormats[rule.param]
+		if ok {
+			schema.Format = format
+		}
+		return ok
+	case "contains":
+		addSchemaPattern(schema, regexp.QuoteMeta(rule.param))
+		return true
+	case "startswith":
+		addSchemaPattern(schema, "^"+regexp.QuoteMeta(rule.param))
+		return true
+	case "endswith":
+		addSchemaPattern(schema, regexp.QuoteMeta(rule.param)+"$")
+		return true
+	}
+
+	if format, ok := schemaFormats[rule.tag]; ok {
+		schema.Format = format
+		return true
+	}
+	if pattern, ok := schemaPatterns[rule.tag]; ok {
+		addSchemaPattern(schema, pattern.String())
+		return true
+	}
+	return false
+}
+
+// applySchemaBound 数值类型设置取值范围，字符串/切片/映射设置长度或个数。
+// 长度是整数，gt/lt 等开区间在这里换算为闭区间
+func applySchemaBound(schema *Schema, t reflect.Type, param string, upper, exclusive bool) bool {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch {
+		case upper && exclusive:
+			schema.ExclusiveMaximum = &n
+		case upper:
+			schema.Maximum = &n
+		case exclusive:
+			schema.ExclusiveMinimum = &n
+		default:
+			schema.Minimum = &n
+		}
+		return true
+	}
+
+	var size float64
+	switch {
+	case upper && exclusive:
+		size = math.Ceil(n) - 1
+	case upper:
+		size = math.Floor(n)
+	case exclusive:
+		size = math.Floor(n) + 1
+	default:
+		size = math.Ceil(n)
+	}
+	return setSchemaSize(schema, t, int(math.Max(size, 0)), upper)
+}
+
+// setSchemaSize 按类型设置 minLength/maxLength、minItems/maxItems 或 minProperties/maxProperties
+func setSchemaSize(schema *Schema, t reflect.Type, n int, upper bool) bool {
+	var target **int
+	switch t.Kind() {
+	case reflect.String:
+		target = &schema.MinLength
+		if upper {
+			target = &schema.MaxLength
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte 编码为 base64，长度约束无法直接表达
+			return false
+		}
+		target = &schema.MinItems
+		if upper {
+			target = &schema.MaxItems
+		}
+	case reflect.Map:
+		target = &schema.MinProperties
+		if upper {
+			target = &schema.MaxProperties
+		}
+	default:
+		return false
+	}
+	*target = &n
+	return true
+}
+
+// addSchemaPattern 设置 pattern，已有 pattern 时追加到 allOf
+func addSchemaPattern(schema *Schema, pattern string) {
+	if schema.Pattern == "" {
+		schema.Pattern = pattern
+		return
+	}
+	schema.AllOf = append(schema.AllOf, &Schema{Pattern: pattern})
+}
+
+// schemaEnumValue 按字段类型转换 eq/oneof 的参数
+func schemaEnumValue(t reflect.Type, param string) (interface{}, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return param, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(param, 10, 64)
+		return n, err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(param, 10, 64)
+		return n, err == nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(param, 64)
+		return n, err == nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(param)
+		return b, err == nil
+	}
+	return nil, false
+}
+
+// ruleString 还原规则在标签中的写法
+func ruleString(rule validationRule) string {
+	if rule.param == "" {
+		return rule.tag
+	}
+	return rule.tag + "=" + rule.param
+}
+
+func float64Ptr(n float64) *float64 {
+	return &n
+}
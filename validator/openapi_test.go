@@ -0,0 +1,243 @@
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIBase struct {
+	ID      string    `json:"id" validate:"required,uuid4"`
+	Created time.Time `json:"created_at"`
+}
+
+type openAPIAddress struct {
+	City    string `json:"city" validate:"required,max=64"`
+	Country string `json:"country" validate:"iso3166_1_alpha2"`
+}
+
+type openAPINode struct {
+	Name     string         `json:"name"`
+	Children []*openAPINode `json:"children" validate:"dive"`
+}
+
+type openAPIUser struct {
+	openAPIBase
+	Name     string            `json:"name" validate:"required,min=2,max=32,alpha"`
+	Email    string            `json:"email,omitempty" validate:"omitempty,email"`
+	Website  *string           `json:"website" validate:"url"`
+	IP       string            `json:"ip" validate:"ipv4"`
+	Birthday string            `json:"birthday" validate:"datetime=2006-01-02"`
+	Login    string            `json:"login" validate:"datetime=Jan 2"`
+	Age      int               `json:"age" validate:"gte=18,lt=130"`
+	Score    float64           `json:"score" validate:"gt=0,lte=1"`
+	Level    uint8             `json:"level" validate:"oneof=2"`
+	Role     string            `json:"role" validate:"oneof=admin"`
+	Code     string            `json:"code" validate:"startswith=A-,endswith=.x,len=6"`
+	Tags     []string          `json:"tags" validate:"min=1,max=5,unique,dive=lowercase"`
+	Labels   map[string]string `json:"labels" validate:"max=3,dive=max=10"`
+	Enabled  bool              `json:"enabled" validate:"eq=true"`
+	Password string            `json:"-" validate:"required"`
+	Confirm  string            `json:"confirm" validate:"required,eqfield=Password,expr=len(this) > 0"`
+	Address  *openAPIAddress   `json:"address" validate:"required"`
+	Tree     openAPINode       `json:"tree"`
+	Raw      []byte            `json:"raw" validate:"max=10"`
+	Timeout  time.Duration     `json:"timeout" validate:"min=1000"`
+	Any      interface{}       `json:"any"`
+	NoTag    int
+	internal string
+}
+
+func TestOpenAPISchema(t *testing.T) {
+	e := NewEngine()
+	schema, err := e.OpenAPISchema(&openAPIUser{})
+	require.NoError(t, err)
+
+	assert.Equal(t, SchemaType{"object"}, schema.Type)
+	assert.Equal(t, []string{"id", "name", "confirm", "address"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "Password")
+	assert.NotContains(t, schema.Properties, "internal")
+	assert.Contains(t, schema.Properties, "NoTag")
+
+	props := schema.Properties
+
+	// 匿名嵌入结构体展开
+	assert.Equal(t, "uuid", props["id"].Format)
+	assert.Equal(t, "date-time", props["created_at"].Format)
+
+	name := props["name"]
+	assert.Equal(t, 2, *name.MinLength)
+	assert.Equal(t, 32, *name.MaxLength)
+	assert.Equal(t, alphaRegex.String(), name.Pattern)
+	assert.Empty(t, name.XValidate)
+
+	assert.Equal(t, "email", props["email"].Format)
+	assert.Equal(t, SchemaType{"string", "null"}, props["website"].Type)
+	assert.Equal(t, "uri", props["website"].Format)
+	assert.Equal(t, "ipv4", props["ip"].Format)
+	assert.Equal(t, "date", props["birthday"].Format)
+	assert.Equal(t, []string{"datetime=Jan 2"}, props["login"].XValidate)
+
+	assert.Equal(t, 18.0, *props["age"].Minimum)
+	assert.Equal(t, 130.0, *props["age"].ExclusiveMaximum)
+	assert.Equal(t, 0.0, *props["score"].ExclusiveMinimum)
+	assert.Equal(t, 1.0, *props["score"].Maximum)
+	assert.Equal(t, []interface{}{uint64(2)}, props["level"].Enum)
+	assert.Equal(t, []interface{}{"admin"}, props["role"].Enum)
+
+	code := props["code"]
+	assert.Equal(t, "^A-", code.Pattern)
+	require.Len(t, code.AllOf, 1)
+	assert.Equal(t, `\.x$`, code.AllOf[0].Pattern)
+	assert.Equal(t, 6, *code.MinLength)
+	assert.Equal(t, 6, *code.MaxLength)
+
+	tags := props["tags"]
+	assert.Equal(t, 1, *tags.MinItems)
+	assert.Equal(t, 5, *tags.MaxItems)
+	assert.Equal(t, []string{"unique"}, tags.XValidate)
+	assert.Equal(t, "^[a-z]+$", tags.Items.Pattern)
+
+	labels := props["labels"]
+	assert.Equal(t, 3, *labels.MaxProperties)
+	assert.Equal(t, 10, *labels.AdditionalProperties.MaxLength)
+
+	assert.Equal(t, true, props["enabled"].Const)
+	assert.Equal(t, []string{"eqfield=Password", "expr=len(this) > 0"}, props["confirm"].XValidate)
+
+	address := props["address"]
+	assert.Equal(t, SchemaType{"object", "null"}, address.Type)
+	assert.Equal(t, []string{"city"}, address.Required)
+	assert.Equal(t, `^[A-Z]{2}$`, address.Properties["country"].Pattern)
+
+	// 递归类型只展开一层
+	children := props["tree"].Properties["children"]
+	assert.Equal(t, SchemaType{"object", "null"}, children.Items.Type)
+	assert.Nil(t, children.Items.Properties)
+
+	assert.Equal(t, "base64", props["raw"].ContentEncoding)
+	assert.Equal(t, []string{"max=10"}, props["raw"].XValidate)
+	assert.Equal(t, 1000.0, *props["timeout"].Minimum)
+	assert.Equal(t, &Schema{}, props["any"])
+}
+
+func TestOpenAPISchemaJSON(t *testing.T) {
+	type Req struct {
+		Zeta  string `json:"zeta" validate:"required,cron,max=20"`
+		Alpha int    `json:"alpha" validate:"min=1,max=10"`
+		Mid   uint   `json:"mid" validate:"eq=0"`
+	}
+
+	first, err := OpenAPISchemaJSON(Req{})
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		again, err := NewEngine().OpenAPISchemaJSON(&Req{})
+		require.NoError(t, err)
+		assert.Equal(t, string(first), string(again))
+	}
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"alpha": {"type": "integer", "minimum": 1, "maximum": 10},
+			"mid": {"type": "integer", "minimum": 0, "const": 0},
+			"zeta": {"type": "string", "maxLength": 20, "x-validate": ["cron"]}
+		},
+		"required": ["zeta"]
+	}`, string(first))
+
+	var decoded Schema
+	require.NoError(t, json.Unmarshal(first, &decoded))
+	assert.Equal(t, SchemaType{"object"}, decoded.Type)
+}
+
+func TestOpenAPISchemaErrors(t *testing.T) {
+	v, err := New()
+	require.NoError(t, err)
+
+	_, err = v.OpenAPISchema(1)
+	assert.Error(t, err)
+	_, err = v.OpenAPISchemaJSON(nil)
+	assert.Error(t, err)
+	_, err = OpenAPISchema([]openAPIUser{})
+	assert.Error(t, err)
+
+	schema, err := v.OpenAPISchema((*openAPIAddress)(nil))
+	require.NoError(t, err)
+	assert.Equal(t, SchemaType{"object"}, schema.Type)
+}
+
+func TestOpenAPISchemaDive(t *testing.T) {
+	type Item struct {
+		SKU string `json:"sku" validate:"required"`
+	}
+	type Req struct {
+		Groups map[string][]Item `json:"groups" validate:"min=1,dive,keys,alpha,max=8,endkeys,min=1,dive"`
+		Matrix [][]int           `json:"matrix" validate:"dive,max=3,dive=min=0"`
+		Raw    []byte            `json:"raw" validate:"dive,max=1"`
+	}
+
+	schema, err := NewEngine().OpenAPISchema(&Req{})
+	require.NoError(t, err)
+
+	groups := schema.Properties["groups"]
+	assert.Equal(t, 1, *groups.MinProperties)
+	assert.Equal(t, alphaRegex.String(), groups.PropertyNames.Pattern)
+	assert.Equal(t, 8, *groups.PropertyNames.MaxLength)
+	assert.Equal(t, 1, *groups.AdditionalProperties.MinItems)
+	assert.Equal(t, []string{"sku"}, groups.AdditionalProperties.Items.Required)
+
+	matrix := schema.Properties["matrix"]
+	assert.Equal(t, 3, *matrix.Items.MaxItems)
+	assert.Equal(t, 0.0, *matrix.Items.Items.Minimum)
+
+	assert.Equal(t, []string{"dive", "max=1"}, schema.Properties["raw"].XValidate)
+}
+
+func TestOpenAPISchemaOptions(t *testing.T) {
+	type Req struct {
+		Name    string         `yaml:"name" validate:"required"`
+		Timeout time.Duration  `yaml:"timeout" validate:"required"`
+		Tags    []string       `yaml:"tags" validate:"dive,alpha"`
+		Extra   map[string]int `validate:"dive,min=1"`
+	}
+
+	var fields []string
+	schema, err := NewEngine().OpenAPISchema(&Req{},
+		WithSchemaPropertyName(func(field reflect.StructField) (string, bool) {
+			if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" {
+				return name, true
+			}
+			return strings.ToLower(field.Name), false
+		}),
+		WithSchemaTypeFunc(func(t reflect.Type) *Schema {
+			if t == durationType {
+				return &Schema{Type: SchemaType{"string", "integer"}}
+			}
+			return nil
+		}),
+		WithSchemaFieldFunc(func(prop *Schema, parent reflect.Type, field reflect.StructField) {
+			fields = append(fields, parent.Name()+"."+field.Name)
+			prop.Description = field.Name
+		}),
+		WithSchemaStructFunc(func(schema *Schema, t reflect.Type) {
+			// 结构体禁止未声明的属性，dive 规则仍然写入字段自身的 items/additionalProperties
+			schema.AdditionalProperties = &Schema{Not: &Schema{}}
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"name", "timeout"}, schema.Required)
+	assert.Equal(t, []string{"Req.Name", "Req.Timeout", "Req.Tags", "Req.Extra"}, fields)
+	assert.Equal(t, SchemaType{"string", "integer"}, schema.Properties["timeout"].Type)
+	assert.True(t, schema.Properties["timeout"].Type.Has("integer"))
+	assert.Equal(t, "Name", schema.Properties["name"].Description)
+	assert.Equal(t, alphaRegex.String(), schema.Properties["tags"].Items.Pattern)
+	assert.Equal(t, 1.0, *schema.Properties["extra"].AdditionalProperties.Minimum)
+	assert.Equal(t, &Schema{Not: &Schema{}}, schema.AdditionalProperties)
+}
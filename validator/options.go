@@ -39,6 +39,13 @@ func WithCustomValidator(tag string, fn func(interface{}) bool) Option {
 	}
 }
 
+// WithCtxConcurrency 设置上下文规则的最大并发数
+func WithCtxConcurrency(n int) Option {
+	return func(v *Validator) {
+		v.engine.SetCtxConcurrency(n)
+	}
+}
+
 // Config 验证器配置（用于批量设置）
 type Config struct {
 	Locale       xlanguage.Tag     // 语言地区
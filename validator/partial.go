@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 部分验证与分组验证：
+//
+//	e.StructPartial(&req, "Name", "Address.City", "Items.SKU") // 只验证列出的字段
+//	e.StructExcept(&req, "Password")                           // 验证列出字段以外的全部字段
+//	e.StructGroups(&req, "create")                             // 只验证 groups 标签包含 create 的字段
+//
+// 字段路径使用结构体字段名，以 "." 分隔，与 FieldError.Namespace 一致；
+// 切片元素可写下标（Items[0].SKU，只匹配该元素）或省略下标（Items.SKU，匹配所有元素）。
+// 列出某个结构体字段等价于列出其全部子字段。
+
+// groupsTagName 验证分组标签名，如 `validate:"required" groups:"create,update"`
+const groupsTagName = "groups"
+
+// DefaultGroup 未声明 groups 标签的字段所属的分组
+const DefaultGroup = "default"
+
+// fieldFilter 字段过滤器，决定字段自身规则是否执行、是否继续递归其子字段
+type fieldFilter interface {
+	check(fp *fieldPlan, namespace string) (checkRules, descend bool)
+}
+
+// parseGroups 解析 groups 标签
+func parseGroups(tag string) []string {
+	if tag == "" || tag == "-" {
+		return nil
+	}
+	var groups []string
+	for _, g := range strings.Split(tag, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// partialFilter StructPartial / StructExcept 使用的路径过滤器
+type partialFilter struct {
+	paths  []string
+	except bool
+}
+
+func (f *partialFilter) check(_ *fieldPlan, namespace string) (bool, bool) {
+	plain := stripPathIndexes(namespace)
+	for _, path := range f.paths {
+		if pathCovers(path, namespace) || pathCovers(path, plain) {
+			// 字段本身或其祖先被列出
+			return !f.except, !f.except
+		}
+	}
+	if f.except {
+		return true, true
+	}
+
+	// 列出的是该字段的子孙：不执行自身规则，仅向下递归
+	for _, path := range f.paths {
+		if pathCovers(namespace, path) || pathCovers(plain, path) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// pathCovers 判断 path 是否等于 namespace 或是其祖先
+func pathCovers(path, namespace string) bool {
+	if !strings.HasPrefix(namespace, path) {
+		return false
+	}
+	if len(namespace) == len(path) {
+		return true
+	}
+	next := namespace[len(path)]
+	return next == '.' || next == '['
+}
+
+// stripPathIndexes 去掉路径中的切片下标：Items[1].Name -> Items.Name
+func stripPathIndexes(namespace string) string {
+	if strings.IndexByte(namespace, '[') == -1 {
+		return namespace
+	}
+	var b strings.Builder
+	b.Grow(len(namespace))
+	depth := 0
+	for i := 0; i < len(namespace); i++ {
+		switch c := namespace[i]; {
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// groupFilter StructGroups 使用的分组过滤器
+type groupFilter map[string]struct{}
+
+func (f groupFilter) check(fp *fieldPlan, _ string) (bool, bool) {
+	if len(fp.groups) == 0 {
+		// 未分组字段属于 DefaultGroup；其子字段有各自的分组，始终递归
+		_, ok := f[DefaultGroup]
+		return ok, true
+	}
+	for _, g := range fp.groups {
+		if _, ok := f[g]; ok {
+			return true, true
+		}
+	}
+	// 不属于指定分组时跳过自身规则，但与未分组字段一样递归，子字段按各自分组过滤
+	return false, true
+}
+
+// StructPartial 只验证列出的字段（及其子字段），不执行结构体级别验证
+func (e *Engine) StructPartial(s interface{}, fields ...string) error {
+	return e.structFiltered(s, &partialFilter{paths: fields})
+}
+
+// StructExcept 验证列出字段（及其子字段）以外的全部字段，不执行结构体级别验证
+func (e *Engine) StructExcept(s interface{}, fields ...string) error {
+	return e.structFiltered(s, &partialFilter{paths: fields, except: true})
+}
+
+// StructGroups 只验证属于指定分组的字段，不执行结构体级别验证。
+// 未声明 groups 标签的字段属于 DefaultGroup，需显式传入 "default" 才会验证
+func (e *Engine) StructGroups(s interface{}, groups ...string) error {
+	filter := make(groupFilter, len(groups))
+	for _, g := range groups {
+		filter[g] = struct{}{}
+	}
+	return e.structFiltered(s, filter)
+}
+
+// structFiltered 按过滤器验证结构体
+func (e *Engine) structFiltered(s interface{}, filter fieldFilter) error {
+	rv := reflect.ValueOf(s)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+		if !rv.IsValid() {
+			return fmt.Errorf("nil pointer dereference")
+		}
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("expected struct, got %s", rv.Kind())
+	}
+
+	var errors ValidationErrors
+	r := &planRun{e: e, top: rv, errors: &errors, filter: filter}
+	r.run(rv, "")
+	return r.result(context.Background())
+}
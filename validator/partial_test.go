@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type partialAddress struct {
+	City string `json:"city" validate:"required" groups:"create"`
+	Zip  string `json:"zip" validate:"len=5" groups:"create,update"`
+}
+
+type partialItem struct {
+	SKU   string `json:"sku" validate:"required" groups:"create"`
+	Count int    `json:"count" validate:"min=1" groups:"update"`
+}
+
+type partialUser struct {
+	ID      int             `json:"id" validate:"min=1" groups:"update"`
+	Name    string          `json:"name" validate:"required,min=2" groups:"create,update"`
+	Email   string          `json:"email" validate:"required,email"`
+	Address partialAddress  `json:"address"`
+	Backup  *partialAddress `json:"backup" groups:"update"`
+	Items   []partialItem   `json:"items" validate:"min=1,dive" groups:"create"`
+	Tags    []string        `json:"tags" validate:"dive=alpha"`
+}
+
+// invalidPartialUser 每个带规则的字段都不合法
+func invalidPartialUser() *partialUser {
+	return &partialUser{
+		Name:    "a",
+		Email:   "bad",
+		Address: partialAddress{Zip: "1"},
+		Backup:  &partialAddress{Zip: "2"},
+		Items:   []partialItem{{SKU: "ok"}, {}},
+		Tags:    []string{"1"},
+	}
+}
+
+func namespacesOf(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	errs, ok := err.(ValidationErrors)
+	require.True(t, ok, "unexpected error: %v", err)
+	namespaces := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		namespaces = append(namespaces, fe.Namespace)
+	}
+	return namespaces
+}
+
+func TestStructPartial(t *testing.T) {
+	e := NewEngine()
+	u := invalidPartialUser()
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   []string
+	}{
+		{"top level", []string{"Name"}, []string{"Name"}},
+		{"nested struct", []string{"Address"}, []string{"Address.City", "Address.Zip"}},
+		{"nested field", []string{"Address.Zip", "Backup.Zip"}, []string{"Address.Zip", "Backup.Zip"}},
+		{"slice all elements", []string{"Items.Count"}, []string{"Items[0].Count", "Items[1].Count"}},
+		{"slice one element", []string{"Items[1].SKU"}, []string{"Items[1].SKU"}},
+		{"slice field", []string{"Items"}, []string{"Items[0].Count", "Items[1].SKU", "Items[1].Count"}},
+		{"dive rules", []string{"Tags"}, []string{"Tags[0]"}},
+		{"unknown", []string{"Nope"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, namespacesOf(t, e.StructPartial(u, tt.fields...)))
+		})
+	}
+}
+
+func TestStructExcept(t *testing.T) {
+	e := NewEngine()
+	u := invalidPartialUser()
+
+	err := e.StructExcept(u, "Name", "Address", "Items.Count", "Backup.Zip")
+	assert.Equal(t, []string{"ID", "Email", "Backup.City", "Items[1].SKU", "Tags[0]"}, namespacesOf(t, err))
+
+	// 排除全部带错误的字段
+	assert.NoError(t, e.StructExcept(u, "ID", "Name", "Email", "Address", "Backup", "Items", "Tags"))
+}
+
+func TestStructGroups(t *testing.T) {
+	e := NewEngine()
+	u := invalidPartialUser()
+
+	// Backup 属于 update，但子字段按各自分组过滤
+	assert.Equal(t, []string{
+		"Name", "Address.City", "Address.Zip", "Backup.City", "Backup.Zip", "Items[1].SKU",
+	}, namespacesOf(t, e.StructGroups(u, "create")))
+
+	// Items 属于 create，元素的 Count 属于 update；Items 自身的 min=1 不验证
+	assert.Equal(t, []string{
+		"ID", "Name", "Address.Zip", "Backup.Zip", "Items[0].Count", "Items[1].Count",
+	}, namespacesOf(t, e.StructGroups(u, "update")))
+
+	// 未分组字段属于 default
+	assert.Equal(t, []string{"Email", "Tags[0]"}, namespacesOf(t, e.StructGroups(u, DefaultGroup)))
+
+	// Struct 不受分组影响
+	all := namespacesOf(t, e.Struct(u))
+	assert.Contains(t, all, "ID")
+	assert.Contains(t, all, "Items[1].Count")
+}
+
+func TestStructGroupsNested(t *testing.T) {
+	type Inner struct {
+		Name string `validate:"required" groups:"create"`
+		Note string `validate:"required"`
+	}
+	type Outer struct {
+		In  Inner  `groups:"update"`
+		Ptr *Inner `validate:"required" groups:"update"`
+	}
+
+	// 外层字段不属于 create，内层 create 分组的字段仍然验证
+	e := NewEngine()
+	assert.Equal(t, []string{"In.Name"}, namespacesOf(t, e.StructGroups(&Outer{}, "create")))
+	assert.Equal(t, []string{"In.Name", "Ptr.Name"}, namespacesOf(t, e.StructGroups(&Outer{Ptr: &Inner{}}, "create")))
+	assert.Equal(t, []string{"Ptr"}, namespacesOf(t, e.StructGroups(&Outer{}, "update")))
+	assert.Equal(t, []string{"In.Note"}, namespacesOf(t, e.StructGroups(&Outer{}, DefaultGroup)))
+}
+
+func TestStructPartialValidator(t *testing.T) {
+	v, err := New()
+	require.NoError(t, err)
+
+	err = v.StructPartial(invalidPartialUser(), "Email")
+	require.Error(t, err)
+	fe := err.(ValidationErrors).First()
+	assert.Equal(t, "email", fe.Field)
+	assert.Equal(t, "email must be a valid email address", fe.Message)
+
+	assert.NoError(t, StructExcept(invalidPartialUser(), "ID", "Name", "Email", "Address", "Backup", "Items", "Tags"))
+	assert.Error(t, StructGroups(invalidPartialUser(), "create"))
+	assert.Error(t, StructPartial(1, "X"))
+	assert.Error(t, v.StructGroups((*partialUser)(nil)))
+}
+
+func TestStripPathIndexes(t *testing.T) {
+	assert.Equal(t, "Items.Tags", stripPathIndexes("Items[1].Tags[20]"))
+	assert.Equal(t, "Name", stripPathIndexes("Name"))
+	assert.Equal(t, []string{"a", "b"}, parseGroups(" a, ,b"))
+	assert.Nil(t, parseGroups("-"))
+}
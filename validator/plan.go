@@ -0,0 +1,543 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 验证计划：首次遇到某个结构体类型时，把标签解析、字段索引、验证函数查找、
+// 默认错误消息格式化等工作一次性完成，缓存为只读的 structPlan；
+// 之后对同类型的 Struct 调用只执行计划，不再解析标签和遍历无关字段。
+//
+// 计划依赖引擎的 tagName、fieldNameFunc 和已注册的验证器，
+// 它们变化时（SetTagName/SetFieldNameFunc/RegisterValidation）缓存会被清空。
+
+// ruleKind 规则类别
+type ruleKind uint8
+
+const (
+	ruleValidate  ruleKind = iota // 普通验证规则
+	ruleOmitEmpty                 // omitempty：字段为空时跳过其后的规则
+	ruleDive                      // dive：展开切片/数组/映射，之后的规则作用于元素
+)
+
+// nestedKind 字段的嵌套结构体类别（由静态类型决定）
+type nestedKind uint8
+
+const (
+	nestedNone    nestedKind = iota
+	nestedStruct             // 结构体字段
+	nestedPtr                // 指向结构体的指针字段
+	nestedDynamic            // 自定义类型字段：解包后的值是结构体时递归
+)
+
+// structPlan 结构体类型的验证计划
+type structPlan struct {
+	fields []fieldPlan
+	err    error // 标签中无法编译的 expr 表达式，执行计划时直接报告
+}
+
+// fieldPlan 单个字段的验证计划
+type fieldPlan struct {
+	index       int
+	name        string // 结构体字段名，用于拼接命名空间
+	displayName string // 错误中展示的字段名（fieldNameFunc 的结果）
+	structField reflect.StructField
+	rules       []rulePlan
+	nested      nestedKind
+	groups      []string // groups 标签声明的验证分组
+	custom      bool     // 字段（或其指针的元素）类型有 CustomTypeFunc，规则执行前解包
+}
+
+// rulePlan 预解析的规则
+type rulePlan struct {
+	kind    ruleKind
+	tag     string
+	param   string
+	fn      ValidatorFunc
+	ctxFn   ValidatorCtxFunc // 上下文规则，与 fn 二选一
+	message string           // 预格式化的默认错误消息
+	expr    bool             // 内置 expr 规则，编译错误单独报告
+
+	// dive 之后各层的规则
+	dive *divePlan
+}
+
+// divePlan dive 展开一层后的规则：
+//
+//	validate:"min=1,dive,keys,alpha,endkeys,required,dive,gt=0"
+//
+// keys ... endkeys 之间的规则作用于映射的键（须紧跟 dive），其后直到下一个 dive 的规则作用于元素/映射值，
+// 再遇到 dive 时继续展开下一层。dive=<规则> 等价于把参数中的规则写在 dive 之后
+type divePlan struct {
+	keys []rulePlan
+	elem []rulePlan
+}
+
+// structPlan 获取（必要时构建）结构体类型的验证计划
+func (e *Engine) structPlan(rt reflect.Type) *structPlan {
+	if cached, ok := e.plans.Load(rt); ok {
+		return cached.(*structPlan)
+	}
+	plan, _ := e.plans.LoadOrStore(rt, e.buildStructPlan(rt))
+	return plan.(*structPlan)
+}
+
+// resetPlans 清空验证计划、清洗计划与自定义类型解包函数缓存
+func (e *Engine) resetPlans() {
+	e.plans.Clear()
+	e.modPlans.Clear()
+	e.customTypes.Clear()
+}
+
+// buildStructPlan 构建结构体类型的验证计划。
+// 嵌套结构体的计划在执行时按类型懒加载，因此自引用类型不会无限递归。
+func (e *Engine) buildStructPlan(rt reflect.Type) *structPlan {
+	plan := &structPlan{}
+	numField := rt.NumField()
+
+	for i := 0; i < numField; i++ {
+		fieldType := rt.Field(i)
+
+		// 跳过非导出字段
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		fp := fieldPlan{
+			index:       i,
+			name:        fieldType.Name,
+			displayName: e.fieldNameFunc(fieldType),
+			structField: fieldType,
+			nested:      nestedKindOf(fieldType.Type),
+		}
+
+		base := fieldType.Type
+		if base.Kind() == reflect.Ptr {
+			base = base.Elem()
+		}
+		if e.customTypeFunc(base) != nil {
+			fp.custom = true
+			fp.nested = nestedDynamic
+		}
+
+		if tag := fieldType.Tag.Get(e.tagName); tag != "" && tag != "-" {
+			fp.rules = e.compileRules(e.parseTag(tag), fp.displayName)
+
+			// 解包后的类型运行时才确定
+			this := fieldType.Type
+			if fp.custom {
+				this = nil
+			}
+			if err := e.compileExprRules(fp.rules, this, rt); err != nil && plan.err == nil {
+				plan.err = &ExprError{Field: rt.String() + "." + fieldType.Name, Expr: err.expr, err: err.err}
+			}
+		}
+		fp.groups = parseGroups(fieldType.Tag.Get(groupsTagName))
+
+		// 既无规则也无需递归的字段不进入计划
+		if len(fp.rules) == 0 && fp.nested == nestedNone {
+			continue
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+
+	return plan
+}
+
+// compileRules 将解析后的规则转换为规则计划，未注册的规则直接丢弃。
+// fieldName 为字段的显示名称，默认错误消息中使用；为空时是 dive 展开后的元素/键规则，使用通用的默认错误消息
+func (e *Engine) compileRules(rules []validationRule, fieldName string) []rulePlan {
+	plans := make([]rulePlan, 0, len(rules))
+	for i, rule := range rules {
+		rp := rulePlan{tag: rule.tag, param: rule.param}
+
+		switch rule.tag {
+		case "dive":
+			// dive 之后的规则全部属于下一层
+			rp.kind = ruleDive
+			rp.dive = e.compileDive(rule.param, rules[i+1:])
+			return append(plans, rp)
+		case "omitempty":
+			rp.kind = ruleOmitEmpty
+		default:
+			if fn, ok := e.validators[rule.tag]; ok {
+				rp.fn = fn
+				rp.expr = isExprValidator(fn)
+			} else if fn, ok := e.ctxValidators[rule.tag]; ok {
+				rp.ctxFn = fn
+			} else {
+				continue
+			}
+			if fieldName != "" {
+				rp.message = formatMessage(getDefaultMessage(rule.tag), fieldName, rule.tag, rule.param)
+			} else {
+				rp.message = fmt.Sprintf("validation failed for tag '%s'", rule.tag)
+			}
+		}
+
+		plans = append(plans, rp)
+	}
+	return plans
+}
+
+// exprCompileError 构建计划时编译失败的表达式
+type exprCompileError struct {
+	expr string
+	err  error
+}
+
+// compileExprRules 按静态类型编译规则中的 expr 表达式（dive 之后的规则按元素/键类型），
+// 类型为 nil（自定义类型、接口）时按名动态解析，只检查语法
+func (e *Engine) compileExprRules(rules []rulePlan, this, parent reflect.Type) *exprCompileError {
+	for i := range rules {
+		rule := &rules[i]
+		switch {
+		case rule.expr:
+			if prog := compileExpr(rule.param, this, parent, nil); prog.err != nil {
+				return &exprCompileError{expr: rule.param, err: prog.err}
+			}
+
+		case rule.kind == ruleDive:
+			container := indirectType(this)
+			var key, elem reflect.Type
+			if container != nil {
+				switch container.Kind() {
+				case reflect.Map:
+					key, elem = e.staticElemType(container.Key()), e.staticElemType(container.Elem())
+				case reflect.Slice, reflect.Array:
+					elem = e.staticElemType(container.Elem())
+				}
+			}
+			if err := e.compileExprRules(rule.dive.keys, key, container); err != nil {
+				return err
+			}
+			if err := e.compileExprRules(rule.dive.elem, elem, container); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// staticElemType 返回 dive 元素在规则中的静态类型，与 unwrapElem 一致：解引用指针，自定义类型返回 nil
+func (e *Engine) staticElemType(t reflect.Type) reflect.Type {
+	t = indirectType(t)
+	if t != nil && e.customTypeFunc(t) != nil {
+		return nil
+	}
+	return t
+}
+
+// compileDive 编译 dive 之后的规则
+func (e *Engine) compileDive(param string, rest []validationRule) *divePlan {
+	keys, elem := e.splitDive(param, rest)
+	return &divePlan{
+		keys: e.compileRules(keys, ""),
+		elem: e.compileRules(elem, ""),
+	}
+}
+
+// splitDive 拆分 dive 之后的规则：紧跟 dive 的 keys ... endkeys 为键规则（缺少 endkeys 时到末尾），
+// 其余为元素规则，dive 参数中的规则排在元素规则之前
+func (e *Engine) splitDive(param string, rest []validationRule) (keys, elem []validationRule) {
+	if len(rest) > 0 && rest[0].tag == "keys" {
+		end := len(rest)
+		for i := 1; i < len(rest); i++ {
+			if rest[i].tag == "endkeys" {
+				end = i
+				break
+			}
+		}
+		keys = rest[1:end]
+		if end < len(rest) {
+			end++
+		}
+		rest = rest[end:]
+	}
+
+	if param != "" {
+		// parseTag 的结果被缓存共享，不能直接 append
+		rest = append(append([]validationRule(nil), e.parseTag(param)...), rest...)
+	}
+	return keys, rest
+}
+
+// nestedKindOf 根据字段静态类型判断是否需要递归验证
+func nestedKindOf(t reflect.Type) nestedKind {
+	switch t.Kind() {
+	case reflect.Struct:
+		return nestedStruct
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct {
+			return nestedPtr
+		}
+	}
+	return nestedNone
+}
+
+// isOmitEmpty omitempty 的判空规则：指针/接口只看是否为 nil，其余同 isFieldNotEmpty
+func isOmitEmpty(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return field.IsNil()
+	}
+	return !isFieldNotEmpty(field)
+}
+
+// planRun 一次验证的执行状态
+type planRun struct {
+	e      *Engine
+	top    reflect.Value
+	errors *ValidationErrors
+	filter fieldFilter // nil 表示验证全部字段
+
+	// 登记的上下文规则，遍历结束后由 runPending 执行
+	pending []*ctxCall
+	// 无法编译的 expr 表达式（*ExprError），与上下文规则的错误一起报告
+	ruleErrs []error
+
+	// 最近一次确认无需解包的元素类型，同一 dive 的元素类型通常相同，可省去 customTypes 查找
+	plainType reflect.Type
+}
+
+// run 按计划验证结构体
+func (r *planRun) run(current reflect.Value, namespace string) {
+	p := r.e.structPlan(current.Type())
+	if p.err != nil {
+		// 同一类型的多个值只报告一次
+		if !slices.Contains(r.ruleErrs, p.err) {
+			r.ruleErrs = append(r.ruleErrs, p.err)
+		}
+		return
+	}
+
+	for i := range p.fields {
+		fp := &p.fields[i]
+		field := current.Field(fp.index)
+		if fp.custom {
+			field = r.e.unwrapCustom(field)
+		}
+
+		fieldName := fp.name
+		if namespace != "" {
+			fieldName = namespace + "." + fieldName
+		}
+
+		checkRules, descend := true, true
+		if r.filter != nil {
+			checkRules, descend = r.filter.check(fp, fieldName)
+			if !checkRules && !descend {
+				continue
+			}
+		}
+
+		if len(fp.rules) > 0 && !r.runRules(fp, current, field, fieldName, checkRules) {
+			// omitempty 命中，字段为空时不再递归
+			continue
+		}
+
+		if !descend {
+			continue
+		}
+
+		// 递归验证嵌套结构体
+		switch fp.nested {
+		case nestedStruct:
+			r.run(field, fieldName)
+		case nestedPtr:
+			if !field.IsNil() {
+				r.run(field.Elem(), fieldName)
+			}
+		case nestedDynamic:
+			if v := indirectValue(field); v.Kind() == reflect.Struct {
+				r.run(v, fieldName)
+			}
+		}
+	}
+}
+
+// runRules 执行字段规则，omitempty 命中时返回 false。
+// checkRules 为 false 时只通过 dive 递归结构体元素，不执行任何规则
+func (r *planRun) runRules(fp *fieldPlan, current, field reflect.Value, fieldName string, checkRules bool) bool {
+	if !checkRules {
+		for j := range fp.rules {
+			if fp.rules[j].kind == ruleDive {
+				r.runDive(fp, field, fieldName, fp.rules[j].dive, false)
+			}
+		}
+		return true
+	}
+
+	// 使用对象池获取 fieldLevel
+	fl := fieldLevelPool.Get().(*fieldLevel)
+	fl.top = r.top
+	fl.parent = current
+	fl.field = field
+	fl.fieldName = fp.displayName
+	fl.structFieldName = fp.name
+	fl.structField = fp.structField
+
+	completed := true
+	for j := range fp.rules {
+		rule := &fp.rules[j]
+
+		switch rule.kind {
+		case ruleOmitEmpty:
+			if isOmitEmpty(field) {
+				completed = false
+			}
+		case ruleDive:
+			r.runDive(fp, field, fieldName, rule.dive, true)
+		default:
+			r.check(rule, fl, fieldName)
+		}
+
+		if !completed {
+			break
+		}
+	}
+
+	// 归还对象池（重置避免持有引用）
+	*fl = fieldLevel{}
+	fieldLevelPool.Put(fl)
+	return completed
+}
+
+// check 执行单条规则：上下文规则登记到 pending，普通规则不通过时追加错误
+func (r *planRun) check(rule *rulePlan, fl *fieldLevel, namespace string) {
+	fl.param = rule.param
+	if rule.expr {
+		// 按运行时类型编译：top 与接口类型的值在构建计划时未知
+		prog := exprProgramOf(fl)
+		if prog.err != nil {
+			r.ruleErrs = append(r.ruleErrs, &ExprError{Field: namespace, Expr: rule.param, err: prog.err})
+		} else if ok, err := prog.run(fl); err != nil || !ok {
+			fe := newPlanFieldError(rule, fl, namespace)
+			*r.errors = append(*r.errors, &fe)
+		}
+	} else if rule.ctxFn != nil {
+		r.deferCtx(rule.ctxFn, fl, newPlanFieldError(rule, fl, namespace))
+	} else if !rule.fn(fl) {
+		fe := newPlanFieldError(rule, fl, namespace)
+		*r.errors = append(*r.errors, &fe)
+	}
+}
+
+// newPlanFieldError 由规则与 fieldLevel 构造错误，只在需要时调用以免 Interface() 分配
+func newPlanFieldError(rule *rulePlan, fl *fieldLevel, namespace string) FieldError {
+	return FieldError{
+		Field:       fl.fieldName,
+		Tag:         rule.tag,
+		Value:       fl.field.Interface(),
+		Param:       rule.param,
+		ActualTag:   rule.tag,
+		Namespace:   namespace,
+		StructField: fl.structFieldName,
+		Message:     rule.message,
+	}
+}
+
+// runDive 按 dive 计划展开一层：切片/数组验证每个元素，映射验证每个键和值（按键名排序以保证错误顺序稳定）。
+// checkRules 为 false 时只递归结构体元素
+func (r *planRun) runDive(fp *fieldPlan, field reflect.Value, fieldName string, dp *divePlan, checkRules bool) {
+	field = indirectValue(field)
+
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		fieldLen := field.Len()
+		for k := 0; k < fieldLen; k++ {
+			elemFieldName := fieldName + "[" + strconv.Itoa(k) + "]"
+			r.runElem(fp, field, field.Index(k), elemFieldName, dp.elem, checkRules, true)
+		}
+
+	case reflect.Map:
+		type mapEntry struct {
+			key  reflect.Value
+			name string
+		}
+		entries := make([]mapEntry, 0, field.Len())
+		iter := field.MapRange()
+		for iter.Next() {
+			entries = append(entries, mapEntry{key: iter.Key(), name: fieldName + "[" + fmt.Sprint(iter.Key().Interface()) + "]"})
+		}
+		slices.SortFunc(entries, func(a, b mapEntry) int {
+			return strings.Compare(a.name, b.name)
+		})
+
+		for _, entry := range entries {
+			if len(dp.keys) > 0 {
+				r.runElem(fp, field, entry.key, entry.name, dp.keys, checkRules, false)
+			}
+			r.runElem(fp, field, field.MapIndex(entry.key), entry.name, dp.elem, checkRules, true)
+		}
+	}
+}
+
+// runElem 对 dive 展开后的单个元素（或映射键）执行规则：非 nil 指针先解引用，自定义类型先解包；
+// 规则中的 dive 继续展开下一层，descend 为 true 时结构体元素递归验证
+func (r *planRun) runElem(fp *fieldPlan, parent, elem reflect.Value, elemFieldName string, rules []rulePlan, checkRules, descend bool) {
+	elem = r.unwrapElem(elem)
+
+	var fl *fieldLevel
+	omitted := false
+	for l := 0; l < len(rules) && !omitted; l++ {
+		rule := &rules[l]
+
+		switch rule.kind {
+		case ruleOmitEmpty:
+			omitted = isOmitEmpty(elem)
+		case ruleDive:
+			r.runDive(fp, elem, elemFieldName, rule.dive, checkRules)
+		default:
+			if !checkRules {
+				continue
+			}
+			if fl == nil {
+				fl = fieldLevelPool.Get().(*fieldLevel)
+				fl.top = r.top
+				fl.parent = parent
+				fl.field = elem
+				fl.fieldName = elemFieldName
+				fl.structFieldName = elemFieldName
+				fl.structField = fp.structField
+			}
+			r.check(rule, fl, elemFieldName)
+		}
+	}
+
+	if fl != nil {
+		*fl = fieldLevel{}
+		fieldLevelPool.Put(fl)
+	}
+
+	if descend && !omitted && elem.Kind() == reflect.Struct {
+		r.run(elem, elemFieldName)
+	}
+}
+
+// unwrapElem 解引用并解包 dive 元素
+func (r *planRun) unwrapElem(elem reflect.Value) reflect.Value {
+	elem = indirectValue(elem)
+	if !elem.IsValid() {
+		return elem
+	}
+	if t := elem.Type(); t != r.plainType {
+		if r.e.customTypeFunc(t) != nil {
+			return r.e.unwrapCustom(elem)
+		}
+		r.plainType = t
+	}
+	return elem
+}
+
+// indirectValue 解引用非 nil 的指针与接口
+func indirectValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
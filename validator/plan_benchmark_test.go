@@ -0,0 +1,264 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// validateStructLegacy 引入验证计划之前的实现：每次调用都解析标签并遍历全部字段，
+// 仅用于基准对比和结果一致性测试
+func (e *Engine) validateStructLegacy(top, current reflect.Value, namespace string, errors *ValidationErrors) {
+	rt := current.Type()
+	numField := current.NumField()
+	tagName := e.tagName
+	fieldNameFunc := e.fieldNameFunc
+
+	for i := 0; i < numField; i++ {
+		field := current.Field(i)
+		fieldType := rt.Field(i)
+
+		// 跳过非导出字段
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		fieldName := fieldType.Name
+		if namespace != "" {
+			fieldName = namespace + "." + fieldName
+		}
+
+		// 获取验证标签
+		tag := fieldType.Tag.Get(tagName)
+		if tag == "" || tag == "-" {
+			// 如果没有验证标签，但是字段是结构体，递归验证
+			fieldKind := field.Kind()
+			if fieldKind == reflect.Struct {
+				e.validateStructLegacy(top, field, fieldName, errors)
+			} else if fieldKind == reflect.Ptr && !field.IsNil() {
+				elem := field.Elem()
+				if elem.Kind() == reflect.Struct {
+					e.validateStructLegacy(top, elem, fieldName, errors)
+				}
+			}
+			continue
+		}
+
+		// 解析验证规则
+		rules := e.parseTag(tag)
+
+		// 获取字段显示名称
+		displayName := fieldNameFunc(fieldType)
+
+		// 使用对象池获取 fieldLevel
+		fl := fieldLevelPool.Get().(*fieldLevel)
+		fl.top = top
+		fl.parent = current
+		fl.field = field
+		fl.fieldName = displayName
+		fl.structFieldName = fieldType.Name
+		fl.structField = fieldType
+
+		numRules := len(rules)
+		for j := 0; j < numRules; j++ {
+			rule := rules[j]
+			fl.param = rule.param
+
+			// 检查是否为 dive tag（用于切片/数组元素验证）
+			if rule.tag == "dive" {
+				// 验证切片/数组中的每个元素
+				fieldKind := field.Kind()
+				if fieldKind == reflect.Slice || fieldKind == reflect.Array {
+					fieldLen := field.Len()
+					for k := 0; k < fieldLen; k++ {
+						elem := field.Index(k)
+						elemFieldName := fieldName + "[" + strconv.Itoa(k) + "]"
+
+						// 如果元素是结构体，递归验证
+						elemKind := elem.Kind()
+						if elemKind == reflect.Struct {
+							e.validateStructLegacy(top, elem, elemFieldName, errors)
+						} else if elemKind == reflect.Ptr && !elem.IsNil() {
+							elemElem := elem.Elem()
+							if elemElem.Kind() == reflect.Struct {
+								e.validateStructLegacy(top, elemElem, elemFieldName, errors)
+							}
+						} else if rule.param != "" {
+							// 如果 dive 有参数，验证元素
+							elemRules := e.parseTag(rule.param)
+							elemFl := fieldLevelPool.Get().(*fieldLevel)
+							elemFl.top = top
+							elemFl.parent = field
+							elemFl.field = elem
+							elemFl.fieldName = elemFieldName
+							elemFl.structFieldName = elemFieldName
+							elemFl.structField = fieldType
+
+							numElemRules := len(elemRules)
+							for l := 0; l < numElemRules; l++ {
+								elemRule := elemRules[l]
+								elemFl.param = elemRule.param
+								if !e.validateField(elemFl, elemRule.tag) {
+									*errors = append(*errors, &FieldError{
+										Field:       elemFieldName,
+										Tag:         elemRule.tag,
+										Value:       elem.Interface(),
+										Param:       elemRule.param,
+										ActualTag:   elemRule.tag,
+										Namespace:   elemFieldName,
+										StructField: elemFieldName,
+										Message:     fmt.Sprintf("validation failed for tag '%s'", elemRule.tag),
+									})
+								}
+							}
+
+							*elemFl = fieldLevel{}
+							fieldLevelPool.Put(elemFl)
+						}
+					}
+				}
+				continue
+			}
+
+			if !e.validateField(fl, rule.tag) {
+				fieldError := &FieldError{
+					Field:       displayName,
+					Tag:         rule.tag,
+					Value:       field.Interface(),
+					Param:       rule.param,
+					ActualTag:   rule.tag,
+					Namespace:   fieldName,
+					StructField: fieldType.Name,
+					Message:     formatMessage(getDefaultMessage(rule.tag), displayName, rule.tag, rule.param),
+				}
+				*errors = append(*errors, fieldError)
+			}
+		}
+
+		// 归还对象池（重置避免持有引用）
+		*fl = fieldLevel{}
+		fieldLevelPool.Put(fl)
+
+		// 递归验证嵌套结构体
+		fieldKind := field.Kind()
+		if fieldKind == reflect.Struct {
+			e.validateStructLegacy(top, field, fieldName, errors)
+		} else if fieldKind == reflect.Ptr && !field.IsNil() {
+			elem := field.Elem()
+			if elem.Kind() == reflect.Struct {
+				e.validateStructLegacy(top, elem, fieldName, errors)
+			}
+		}
+	}
+}
+
+// 典型请求 DTO：嵌套结构体 + 结构体切片 + 元素规则
+type planBenchItem struct {
+	SKU      string   `json:"sku" validate:"required,alphanum,min=3,max=32"`
+	Quantity int      `json:"quantity" validate:"gte=1,lte=100"`
+	Price    float64  `json:"price" validate:"gt=0"`
+	Tags     []string `json:"tags" validate:"max=5,dive=alpha"`
+}
+
+type planBenchAddress struct {
+	Street  string `json:"street" validate:"required"`
+	City    string `json:"city" validate:"required,max=64"`
+	ZipCode string `json:"zip_code" validate:"len=5,numeric"`
+}
+
+type planBenchOrder struct {
+	Email    string            `json:"email" validate:"required,email"`
+	Name     string            `json:"name" validate:"required,min=2,max=64"`
+	Note     string            `json:"note" validate:"omitempty,max=200"`
+	Shipping planBenchAddress  `json:"shipping"`
+	Billing  *planBenchAddress `json:"billing"`
+	Items    []planBenchItem   `json:"items" validate:"min=1,dive"`
+	Coupons  []string          `json:"coupons" validate:"dive=alphanum"`
+	internal string
+}
+
+func newPlanBenchOrder(items int) planBenchOrder {
+	o := planBenchOrder{
+		Email:    "buyer@example.com",
+		Name:     "Buyer",
+		Shipping: planBenchAddress{Street: "1 Main St", City: "Springfield", ZipCode: "12345"},
+		Billing:  &planBenchAddress{Street: "2 Main St", City: "Springfield", ZipCode: "12345"},
+		Coupons:  []string{"SAVE10", "FREESHIP"},
+	}
+	for i := 0; i < items; i++ {
+		o.Items = append(o.Items, planBenchItem{
+			SKU:      fmt.Sprintf("SKU%03d", i),
+			Quantity: 1 + i%10,
+			Price:    9.99,
+			Tags:     []string{"new", "sale"},
+		})
+	}
+	return o
+}
+
+func newPlanBenchEngine(b testing.TB) *Engine {
+	v, err := New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return v.engine
+}
+
+// legacyStruct 用旧实现验证结构体（不含结构体级别验证）
+func legacyStruct(e *Engine, s interface{}) ValidationErrors {
+	rv := reflect.Indirect(reflect.ValueOf(s))
+	var errors ValidationErrors
+	e.validateStructLegacy(rv, rv, "", &errors)
+	return errors
+}
+
+func benchmarkStructPlan(b *testing.B, o planBenchOrder) {
+	e := newPlanBenchEngine(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = e.Struct(&o)
+	}
+}
+
+func benchmarkStructLegacy(b *testing.B, o planBenchOrder) {
+	e := newPlanBenchEngine(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = legacyStruct(e, &o)
+	}
+}
+
+func BenchmarkStructPlan_OrderDTO_Valid(b *testing.B) {
+	benchmarkStructPlan(b, newPlanBenchOrder(10))
+}
+
+func BenchmarkStructLegacy_OrderDTO_Valid(b *testing.B) {
+	benchmarkStructLegacy(b, newPlanBenchOrder(10))
+}
+
+func BenchmarkStructPlan_OrderDTO_Invalid(b *testing.B) {
+	o := newPlanBenchOrder(10)
+	o.Email = "bad"
+	o.Items[3].SKU = ""
+	o.Items[5].Tags = []string{"1"}
+	benchmarkStructPlan(b, o)
+}
+
+func BenchmarkStructLegacy_OrderDTO_Invalid(b *testing.B) {
+	o := newPlanBenchOrder(10)
+	o.Email = "bad"
+	o.Items[3].SKU = ""
+	o.Items[5].Tags = []string{"1"}
+	benchmarkStructLegacy(b, o)
+}
+
+func BenchmarkStructPlan_OrderDTO_Large(b *testing.B) {
+	benchmarkStructPlan(b, newPlanBenchOrder(100))
+}
+
+func BenchmarkStructLegacy_OrderDTO_Large(b *testing.B) {
+	benchmarkStructLegacy(b, newPlanBenchOrder(100))
+}
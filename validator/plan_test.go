@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructPlanMatchesLegacy(t *testing.T) {
+	e := newPlanBenchEngine(t)
+
+	invalid := newPlanBenchOrder(3)
+	invalid.Email = "bad"
+	invalid.Name = ""
+	invalid.Shipping.ZipCode = "12a"
+	invalid.Billing = nil
+	invalid.Items[1].SKU = "x"
+	invalid.Items[2].Tags = []string{"ok", "n0", "a", "b", "c", "d"}
+	invalid.Coupons = []string{"OK", "NOT-OK"}
+
+	empty := planBenchOrder{}
+
+	for name, o := range map[string]planBenchOrder{
+		"valid":   newPlanBenchOrder(3),
+		"invalid": invalid,
+		"empty":   empty,
+	} {
+		t.Run(name, func(t *testing.T) {
+			want := legacyStruct(e, &o)
+			err := e.Struct(&o)
+			if len(want) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, want, err.(ValidationErrors))
+		})
+	}
+}
+
+func TestStructPlanCache(t *testing.T) {
+	type S struct {
+		Name string `json:"name" validate:"required,even"`
+		Skip int
+	}
+	e := NewEngine()
+	rt := reflect.TypeOf(S{})
+
+	err := e.Struct(S{})
+	require.Error(t, err)
+	assert.Equal(t, "name", err.(ValidationErrors).First().Field)
+
+	plan := e.structPlan(rt)
+	assert.Same(t, plan, e.structPlan(rt))
+	require.Len(t, plan.fields, 1)
+	// 未注册的 even 规则不进入计划
+	assert.Len(t, plan.fields[0].rules, 1)
+
+	// 注册新规则后计划重建
+	require.NoError(t, e.RegisterValidation("even", func(fl FieldLevel) bool {
+		return len(fl.Field().String())%2 == 0
+	}))
+	assert.Error(t, e.Struct(S{Name: "abc"}))
+	assert.NoError(t, e.Struct(S{Name: "ab"}))
+
+	// 字段名函数变化后计划重建
+	e.SetFieldNameFunc(structFieldNameFunc)
+	err = e.Struct(S{})
+	require.Error(t, err)
+	assert.Equal(t, "Name", err.(ValidationErrors).First().Field)
+
+	// 标签名变化后计划重建
+	e.SetTagName("binding")
+	assert.NoError(t, e.Struct(S{}))
+}
+
+func TestStructPlanOmitEmpty(t *testing.T) {
+	type Inner struct {
+		Name string `validate:"required"`
+	}
+	type S struct {
+		Code  string   `validate:"omitempty,min=3"`
+		Tags  []string `validate:"omitempty,min=2,dive=alpha"`
+		Inner *Inner   `validate:"omitempty"`
+		Value Inner    `validate:"omitempty"`
+	}
+	e := NewEngine()
+
+	assert.NoError(t, e.Struct(S{}))
+
+	err := e.Struct(S{
+		Code:  "ab",
+		Tags:  []string{"1"},
+		Inner: &Inner{},
+		Value: Inner{Name: "x"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"Code", "Tags", "Tags[0]", "Name"}, err.(ValidationErrors).Fields())
+	assert.Equal(t, "Inner.Name", err.(ValidationErrors)[3].Namespace)
+}
+
+type planNode struct {
+	Name     string `validate:"required"`
+	Next     *planNode
+	Children []*planNode `validate:"dive"`
+}
+
+func TestStructPlanRecursiveType(t *testing.T) {
+	e := NewEngine()
+	n := &planNode{
+		Name:     "root",
+		Next:     &planNode{},
+		Children: []*planNode{{Name: "a"}, {Children: []*planNode{{}}}},
+	}
+
+	err := e.Struct(n)
+	require.Error(t, err)
+	namespaces := make([]string, 0)
+	for _, fe := range err.(ValidationErrors) {
+		namespaces = append(namespaces, fe.Namespace)
+	}
+	assert.Equal(t, []string{"Next.Name", "Children[1].Name", "Children[1].Children[0].Name"}, namespaces)
+}
+
+type diveItem struct {
+	SKU string `validate:"required"`
+}
+
+type diveOrder struct {
+	Labels  map[string]string     `validate:"max=3,dive,keys,alpha,min=2,endkeys,required"`
+	Groups  map[string][]diveItem `validate:"min=1,dive,keys,alpha,endkeys,min=1,dive"`
+	Matrix  [][]int               `validate:"dive,min=2,dive,min=0"`
+	Ptrs    []*string             `validate:"dive,required,min=2"`
+	Aliases []string              `validate:"dive,omitempty,alpha"`
+	Codes   map[string]int        `validate:"dive,keys,len=2"`
+	Notes   []string              `validate:"dive=alpha,max=3"`
+}
+
+func TestStructPlanDive(t *testing.T) {
+	e := NewEngine()
+
+	a, ok := "a", "ok"
+	valid := &diveOrder{
+		Labels:  map[string]string{"env": "prod"},
+		Groups:  map[string][]diveItem{"main": {{SKU: "A1"}}},
+		Matrix:  [][]int{{1, 2}, {0, 0}},
+		Ptrs:    []*string{&ok},
+		Aliases: []string{"", "bob"},
+		Codes:   map[string]int{"cn": 0},
+		Notes:   []string{"abc"},
+	}
+	assert.NoError(t, e.Struct(valid))
+
+	invalid := &diveOrder{
+		Labels: map[string]string{"Zz": "v", "b1": "x", "ok": ""},
+		Groups: map[string][]diveItem{
+			"b-1":   {{SKU: "A1"}},
+			"empty": {},
+			"good":  {{SKU: ""}},
+		},
+		Matrix:  [][]int{{1, 2}, {3}, {-1, 4}},
+		Ptrs:    []*string{nil, &a, &ok},
+		Aliases: []string{"", "a1", "ab"},
+		Codes:   map[string]int{"usa": 1},
+		Notes:   []string{"abcd", "a1"},
+	}
+	err := e.Struct(invalid)
+	require.Error(t, err)
+
+	errs := err.(ValidationErrors)
+	// 映射按键名排序，键规则先于值规则
+	assert.Equal(t, []string{
+		"Labels[b1]", "Labels[ok]",
+		"Groups[b-1]", "Groups[empty]", "Groups[good][0].SKU",
+		"Matrix[1]", "Matrix[2][0]",
+		"Ptrs[0]", "Ptrs[0]", "Ptrs[1]",
+		"Aliases[1]",
+		"Codes[usa]",
+		"Notes[0]", "Notes[1]",
+	}, namespacesOf(t, err))
+
+	tags := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		tags = append(tags, fe.Tag)
+	}
+	assert.Equal(t, []string{
+		"alpha", "required",
+		"alpha", "min", "required",
+		"min", "min",
+		"required", "min", "min",
+		"alpha",
+		"len",
+		"max", "alpha",
+	}, tags)
+	// 与字段规则一致，元素的规则全部执行；非 nil 指针解引用后验证
+	assert.Equal(t, "a", errs[9].Value)
+}
+
+func TestStructPlanDivePartial(t *testing.T) {
+	e := NewEngine()
+
+	o := &diveOrder{
+		Groups: map[string][]diveItem{"b-1": {{SKU: ""}}},
+		Ptrs:   []*string{nil},
+	}
+	// 未选中的字段只递归结构体元素，不执行 dive 规则
+	err := e.StructPartial(o, "Groups.SKU")
+	assert.Equal(t, []string{"Groups[b-1][0].SKU"}, namespacesOf(t, err))
+}
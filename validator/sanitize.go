@@ -0,0 +1,412 @@
+package validator
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lazygophers/utils/stringx"
+)
+
+// 清洗（mod）标签：在验证之前就地规范化结构体字段，例如
+//
+//	Email string   `mod:"trim,lower" validate:"required,email"`
+//	Slug  string   `mod:"trim,slug"`
+//	Tags  []string `mod:"dive,trim,lower"`
+//
+// dive 之前的规则作用于字段本身，之后的规则作用于切片/数组/映射的每个元素；
+// 结构体字段与结构体元素会自动递归。`sanitize` 标签与 `mod` 等价（同时存在时取 `mod`）。
+
+// modTagNames 清洗标签名，按优先级排列
+var modTagNames = []string{"mod", "sanitize"}
+
+// ModifierFunc 清洗函数，通过 fl.Field()（可寻址）就地修改字段值
+type ModifierFunc func(fl FieldLevel) error
+
+// modStructPlan 结构体类型的清洗计划
+type modStructPlan struct {
+	fields []modFieldPlan
+	err    error
+}
+
+// modFieldPlan 单个字段的清洗计划
+type modFieldPlan struct {
+	index       int
+	name        string
+	displayName string
+	structField reflect.StructField
+	rules       []modRule // 作用于字段本身
+	dive        bool
+	elemRules   []modRule // dive 之后，作用于每个元素
+	nested      nestedKind
+}
+
+// modRule 预解析的清洗规则
+type modRule struct {
+	tag   string
+	param string
+	fn    ModifierFunc
+}
+
+// RegisterModifier 注册清洗规则
+func (e *Engine) RegisterModifier(tag string, fn ModifierFunc) error {
+	if tag == "" {
+		return fmt.Errorf("modifier tag cannot be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("modifier function cannot be nil")
+	}
+
+	if e.modifiers == nil {
+		e.modifiers = make(map[string]ModifierFunc)
+	}
+	e.modifiers[tag] = fn
+	e.modPlans.Clear()
+	return nil
+}
+
+// Sanitize 按 mod 标签就地清洗结构体，s 必须是非 nil 的结构体指针
+func (e *Engine) Sanitize(s interface{}) error {
+	rv := reflect.ValueOf(s)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("sanitize requires a non-nil pointer to struct, got %T", s)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("expected struct, got %s", rv.Kind())
+	}
+
+	return e.sanitizeStruct(rv, rv, "")
+}
+
+// SanitizeStruct 先清洗再验证结构体
+func (e *Engine) SanitizeStruct(s interface{}) error {
+	if err := e.Sanitize(s); err != nil {
+		return err
+	}
+	return e.Struct(s)
+}
+
+// modPlan 获取（必要时构建）结构体类型的清洗计划
+func (e *Engine) modPlan(rt reflect.Type) *modStructPlan {
+	if cached, ok := e.modPlans.Load(rt); ok {
+		return cached.(*modStructPlan)
+	}
+	plan, _ := e.modPlans.LoadOrStore(rt, e.buildModPlan(rt))
+	return plan.(*modStructPlan)
+}
+
+// buildModPlan 构建结构体类型的清洗计划，未知规则记录为计划错误
+func (e *Engine) buildModPlan(rt reflect.Type) *modStructPlan {
+	plan := &modStructPlan{}
+	numField := rt.NumField()
+
+	for i := 0; i < numField; i++ {
+		fieldType := rt.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		fp := modFieldPlan{
+			index:       i,
+			name:        fieldType.Name,
+			displayName: e.fieldNameFunc(fieldType),
+			structField: fieldType,
+			nested:      nestedKindOf(fieldType.Type),
+		}
+
+		for _, tagName := range modTagNames {
+			tag := fieldType.Tag.Get(tagName)
+			if tag == "" || tag == "-" {
+				continue
+			}
+
+			for _, rule := range e.parseTag(tag) {
+				if rule.tag == "dive" {
+					fp.dive = true
+					continue
+				}
+				fn, ok := e.modifiers[rule.tag]
+				if !ok {
+					plan.err = fmt.Errorf("unknown modifier %q on %s.%s", rule.tag, rt.Name(), fieldType.Name)
+					return plan
+				}
+				mr := modRule{tag: rule.tag, param: rule.param, fn: fn}
+				if fp.dive {
+					fp.elemRules = append(fp.elemRules, mr)
+				} else {
+					fp.rules = append(fp.rules, mr)
+				}
+			}
+			break
+		}
+
+		if len(fp.rules) == 0 && !fp.dive && fp.nested == nestedNone {
+			continue
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+
+	return plan
+}
+
+// sanitizeStruct 按计划清洗结构体
+func (e *Engine) sanitizeStruct(top, current reflect.Value, namespace string) error {
+	plan := e.modPlan(current.Type())
+	if plan.err != nil {
+		return plan.err
+	}
+
+	for i := range plan.fields {
+		fp := &plan.fields[i]
+		field := current.Field(fp.index)
+
+		fieldName := fp.name
+		if namespace != "" {
+			fieldName = namespace + "." + fieldName
+		}
+
+		if err := e.applyModifiers(fp.rules, top, current, field, fp.displayName, fp.name, fieldName, fp.structField); err != nil {
+			return err
+		}
+
+		if fp.dive {
+			if err := e.sanitizeDive(fp, top, field, fieldName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// 递归清洗嵌套结构体
+		switch fp.nested {
+		case nestedStruct:
+			if err := e.sanitizeStruct(top, field, fieldName); err != nil {
+				return err
+			}
+		case nestedPtr:
+			if !field.IsNil() {
+				if err := e.sanitizeStruct(top, field.Elem(), fieldName); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sanitizeDive 清洗切片/数组/映射中的每个元素；映射的值不可寻址，修改副本后写回
+func (e *Engine) sanitizeDive(fp *modFieldPlan, top, field reflect.Value, fieldName string) error {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		fieldLen := field.Len()
+		for k := 0; k < fieldLen; k++ {
+			elemName := fieldName + "[" + strconv.Itoa(k) + "]"
+			if err := e.sanitizeElem(fp, top, field, field.Index(k), elemName); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := field.MapRange()
+		for iter.Next() {
+			elem := reflect.New(field.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			elemName := fieldName + "[" + fmt.Sprint(iter.Key().Interface()) + "]"
+			if err := e.sanitizeElem(fp, top, field, elem, elemName); err != nil {
+				return err
+			}
+			field.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
+
+// sanitizeElem 清洗单个元素：先执行元素规则，结构体元素再递归
+func (e *Engine) sanitizeElem(fp *modFieldPlan, top, parent, elem reflect.Value, elemName string) error {
+	if err := e.applyModifiers(fp.elemRules, top, parent, elem, elemName, elemName, elemName, fp.structField); err != nil {
+		return err
+	}
+
+	switch elem.Kind() {
+	case reflect.Struct:
+		return e.sanitizeStruct(top, elem, elemName)
+	case reflect.Ptr:
+		if !elem.IsNil() && elem.Elem().Kind() == reflect.Struct {
+			return e.sanitizeStruct(top, elem.Elem(), elemName)
+		}
+	}
+	return nil
+}
+
+// applyModifiers 依次执行清洗规则
+func (e *Engine) applyModifiers(rules []modRule, top, parent, field reflect.Value, displayName, structFieldName, namespace string, structField reflect.StructField) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	fl := fieldLevelPool.Get().(*fieldLevel)
+	fl.top = top
+	fl.parent = parent
+	fl.field = field
+	fl.fieldName = displayName
+	fl.structFieldName = structFieldName
+	fl.structField = structField
+
+	var err error
+	for i := range rules {
+		fl.param = rules[i].param
+		if err = rules[i].fn(fl); err != nil {
+			err = fmt.Errorf("modifier %s on %s: %w", rules[i].tag, namespace, err)
+			break
+		}
+	}
+
+	*fl = fieldLevel{}
+	fieldLevelPool.Put(fl)
+	return err
+}
+
+// registerBuiltinModifiers 注册内置清洗规则
+func (e *Engine) registerBuiltinModifiers() {
+	e.modifiers = map[string]ModifierFunc{
+		"trim":        StringModifier(strings.TrimSpace),
+		"trim_all":    StringModifier(stringx.TrimSpaceAll),
+		"lower":       StringModifier(strings.ToLower),
+		"upper":       StringModifier(strings.ToUpper),
+		"title":       StringModifier(stringx.ToTitleCase),
+		"nfc":         StringModifier(func(s string) string { return stringx.Normalize(s, 0) }),
+		"snake":       StringModifier(stringx.ToSnake),
+		"slug":        StringModifier(stringx.Slugify),
+		"strip_html":  StringModifier(stripHTML),
+		"escape_html": StringModifier(html.EscapeString),
+		"truncate":    modifyTruncate,
+		"default":     modifyDefault,
+	}
+}
+
+// StringModifier 将字符串转换函数包装为清洗规则，
+// 只作用于字符串（及非 nil 的字符串指针）字段，其他类型原样跳过
+func StringModifier(fn func(string) string) ModifierFunc {
+	return func(fl FieldLevel) error {
+		field := indirectSettable(fl.Field())
+		if field.Kind() == reflect.String && field.CanSet() {
+			field.SetString(fn(field.String()))
+		}
+		return nil
+	}
+}
+
+// indirectSettable 解引用非 nil 指针
+func indirectSettable(field reflect.Value) reflect.Value {
+	for field.Kind() == reflect.Ptr && !field.IsNil() {
+		field = field.Elem()
+	}
+	return field
+}
+
+// stripHTML 还原实体后移除 HTML 标签（复用 html 验证器的 htmlTagRegex）。
+// 先还原再移除，&lt;script&gt; 这类编码的标签同样被移除
+func stripHTML(s string) string {
+	if !strings.ContainsRune(s, '<') && !strings.ContainsRune(s, '&') {
+		return s
+	}
+	return htmlTagRegex.ReplaceAllString(html.UnescapeString(s), "")
+}
+
+// modifyTruncate 按 rune 截断字符串：truncate=N
+func modifyTruncate(fl FieldLevel) error {
+	n, err := strconv.Atoi(fl.Param())
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid truncate length %q", fl.Param())
+	}
+
+	field := indirectSettable(fl.Field())
+	if field.Kind() != reflect.String || !field.CanSet() {
+		return nil
+	}
+	s := field.String()
+	if utf8.RuneCountInString(s) <= n {
+		return nil
+	}
+	runes := []rune(s)
+	field.SetString(string(runes[:n]))
+	return nil
+}
+
+// modifyDefault 字段为零值时设置默认值：default=值。
+// 支持字符串、布尔、整数（含 time.Duration）、无符号整数、浮点数，nil 指针会被分配
+func modifyDefault(fl FieldLevel) error {
+	field := fl.Field()
+	if !field.CanSet() {
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		if !field.IsNil() {
+			return nil
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := setDefaultValue(elem.Elem(), fl.Param()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+	if !field.IsZero() {
+		return nil
+	}
+	return setDefaultValue(field, fl.Param())
+}
+
+// setDefaultValue 将字符串参数解析为字段类型的值
+func setDefaultValue(field reflect.Value, param string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(param)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(param)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(param, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(param, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(param, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported default for %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sanitizeAddress struct {
+	City string `mod:"trim,title"`
+	Zip  string `sanitize:"trim_all"`
+}
+
+type sanitizeItem struct {
+	Name string `json:"name" mod:"trim" validate:"required"`
+}
+
+type sanitizeDTO struct {
+	Email    string            `json:"email" mod:"trim,lower" validate:"required,email"`
+	Code     string            `mod:"upper"`
+	Name     string            `mod:"nfc"`
+	Slug     string            `mod:"trim,slug"`
+	Column   string            `mod:"snake"`
+	Bio      string            `mod:"strip_html,truncate=5"`
+	Comment  string            `mod:"escape_html"`
+	Role     string            `mod:"default=user"`
+	Limit    int               `mod:"default=10"`
+	Ratio    float64           `mod:"default=0.5"`
+	Enabled  bool              `mod:"default=true"`
+	Timeout  time.Duration     `mod:"default=1m30s"`
+	Retries  *uint8            `mod:"default=3"`
+	Nickname *string           `mod:"trim,upper"`
+	Tags     []string          `mod:"dive,trim,lower"`
+	Labels   map[string]string `mod:"dive,trim"`
+	Address  sanitizeAddress
+	Backup   *sanitizeAddress
+	Items    []sanitizeItem `json:"items" mod:"dive" validate:"dive"`
+	Ignored  string         `mod:"-"`
+	internal string
+}
+
+func TestSanitize(t *testing.T) {
+	e := NewEngine()
+
+	nickname := "  neo "
+	d := &sanitizeDTO{
+		Email:    "  Alice@Example.COM ",
+		Code:     "ab-1",
+		Name:     "e\u0301",
+		Slug:     " Hello World! ",
+		Column:   "UserName",
+		Bio:      "<p>Hi &amp; welcome</p>",
+		Comment:  `<a href="x">`,
+		Limit:    20,
+		Nickname: &nickname,
+		Tags:     []string{" Go ", "RUST"},
+		Labels:   map[string]string{"env": " prod "},
+		Address:  sanitizeAddress{City: " new york ", Zip: "12 345"},
+		Backup:   &sanitizeAddress{City: "paris"},
+		Items:    []sanitizeItem{{Name: " a "}},
+		Ignored:  " x ",
+	}
+	require.NoError(t, e.Sanitize(d))
+
+	assert.Equal(t, "alice@example.com", d.Email)
+	assert.Equal(t, "AB-1", d.Code)
+	assert.Equal(t, "\u00e9", d.Name)
+	assert.Equal(t, "hello-world", d.Slug)
+	assert.Equal(t, "user_name", d.Column)
+	assert.Equal(t, "Hi & ", d.Bio)
+	assert.Equal(t, "&lt;a href=&#34;x&#34;&gt;", d.Comment)
+	assert.Equal(t, "user", d.Role)
+	assert.Equal(t, 20, d.Limit)
+	assert.Equal(t, 0.5, d.Ratio)
+	assert.True(t, d.Enabled)
+	assert.Equal(t, 90*time.Second, d.Timeout)
+	require.NotNil(t, d.Retries)
+	assert.EqualValues(t, 3, *d.Retries)
+	assert.Equal(t, "NEO", *d.Nickname)
+	assert.Equal(t, []string{"go", "rust"}, d.Tags)
+	assert.Equal(t, map[string]string{"env": "prod"}, d.Labels)
+	assert.Equal(t, "New York", d.Address.City)
+	assert.Equal(t, "12345", d.Address.Zip)
+	assert.Equal(t, "Paris", d.Backup.City)
+	assert.Equal(t, "a", d.Items[0].Name)
+	assert.Equal(t, " x ", d.Ignored)
+}
+
+func TestSanitizeStruct(t *testing.T) {
+	v, err := New()
+	require.NoError(t, err)
+
+	d := &sanitizeDTO{Email: " Bob@Example.com ", Items: []sanitizeItem{{Name: "  "}}}
+	err = v.SanitizeStruct(d)
+	require.Error(t, err)
+	// 清洗后 email 合法，只剩清洗为空的 Name
+	assert.Equal(t, []string{"name"}, err.(ValidationErrors).Fields())
+	assert.Equal(t, "Items[0].Name", err.(ValidationErrors)[0].Namespace)
+	assert.Equal(t, "bob@example.com", d.Email)
+
+	d.Items[0].Name = "ok"
+	assert.NoError(t, SanitizeStruct(d))
+}
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"<b>bold</b> &amp; more", "bold & more"},
+		// 编码的标签还原后同样被移除，不会产生新的标签
+		{"&lt;script&gt;alert(1)&lt;/script&gt;", "alert(1)"},
+		{"&#60;img src=x onerror=alert(1)&#62;", ""},
+		{"<p>&lt;b&gt;hi&lt;/b&gt;</p>", "hi"},
+		{"1 &lt; 2", "1 < 2"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, stripHTML(tt.in), tt.in)
+	}
+}
+
+func TestSanitizeErrors(t *testing.T) {
+	e := NewEngine()
+
+	assert.Error(t, e.Sanitize(sanitizeDTO{}))
+	assert.Error(t, e.Sanitize((*sanitizeDTO)(nil)))
+	s := "x"
+	assert.Error(t, e.Sanitize(&s))
+
+	type unknown struct {
+		A string `mod:"trim,shout"`
+	}
+	assert.ErrorContains(t, e.Sanitize(&unknown{}), `unknown modifier "shout"`)
+
+	type badDefault struct {
+		N     int       `mod:"default=abc"`
+		Times []float64 `mod:"default=1"`
+	}
+	assert.ErrorContains(t, e.Sanitize(&badDefault{}), "modifier default on N")
+	assert.ErrorContains(t, e.Sanitize(&badDefault{N: 1}), "unsupported default")
+
+	type badTruncate struct {
+		S string `mod:"truncate=-1"`
+	}
+	assert.ErrorContains(t, e.Sanitize(&badTruncate{}), "invalid truncate length")
+}
+
+func TestRegisterModifier(t *testing.T) {
+	e := NewEngine()
+	assert.Error(t, e.RegisterModifier("", StringModifier(strings.TrimSpace)))
+	assert.Error(t, e.RegisterModifier("x", nil))
+
+	type S struct {
+		A string `mod:"reverse"`
+		B string `mod:"fail"`
+	}
+	assert.Error(t, e.Sanitize(&S{}))
+
+	require.NoError(t, e.RegisterModifier("reverse", StringModifier(func(s string) string {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	})))
+	require.NoError(t, e.RegisterModifier("fail", func(fl FieldLevel) error {
+		if fl.Field().String() == "" {
+			return errors.New("empty")
+		}
+		return nil
+	}))
+
+	s := &S{A: "abc", B: "ok"}
+	require.NoError(t, e.Sanitize(s))
+	assert.Equal(t, "cba", s.A)
+
+	err := e.Sanitize(&S{})
+	assert.ErrorContains(t, err, "modifier fail on B: empty")
+}
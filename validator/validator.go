@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -91,14 +93,36 @@ func (v *Validator) SetUseJSON(useJSON bool) {
 
 // Struct 验证结构体
 func (v *Validator) Struct(s interface{}) error {
-	err := v.engine.Struct(s)
-	if err != nil {
-		if validationErrors, ok := err.(ValidationErrors); ok {
-			return v.translateValidationErrors(validationErrors)
-		}
-		return err
+	return v.translateError(v.engine.Struct(s))
+}
+
+// StructCtx 验证结构体，上下文规则使用 ctx 执行
+func (v *Validator) StructCtx(ctx context.Context, s interface{}) error {
+	return v.translateError(v.engine.StructCtx(ctx, s))
+}
+
+// StructPartial 只验证列出的字段（及其子字段）
+func (v *Validator) StructPartial(s interface{}, fields ...string) error {
+	return v.translateError(v.engine.StructPartial(s, fields...))
+}
+
+// StructExcept 验证列出字段（及其子字段）以外的全部字段
+func (v *Validator) StructExcept(s interface{}, fields ...string) error {
+	return v.translateError(v.engine.StructExcept(s, fields...))
+}
+
+// StructGroups 只验证属于指定分组的字段
+func (v *Validator) StructGroups(s interface{}, groups ...string) error {
+	return v.translateError(v.engine.StructGroups(s, groups...))
+}
+
+// translateError 翻译引擎返回的验证错误（含与规则错误合并的情况），其他错误原样返回
+func (v *Validator) translateError(err error) error {
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		v.translateValidationErrors(validationErrors)
 	}
-	return nil
+	return err
 }
 
 // Var 验证单个变量
@@ -115,12 +139,50 @@ func (v *Validator) Var(field interface{}, tag string) error {
 	return nil
 }
 
+// Sanitize 按 mod 标签就地清洗结构体
+func (v *Validator) Sanitize(s interface{}) error {
+	return v.engine.Sanitize(s)
+}
+
+// SanitizeStruct 先按 mod 标签清洗结构体，再验证
+func (v *Validator) SanitizeStruct(s interface{}) error {
+	if err := v.engine.Sanitize(s); err != nil {
+		return err
+	}
+	return v.Struct(s)
+}
+
+// OpenAPISchema 由结构体类型和验证标签生成 OpenAPI 3.1 Schema
+func (v *Validator) OpenAPISchema(s interface{}, opts ...SchemaOption) (*Schema, error) {
+	return v.engine.OpenAPISchema(s, opts...)
+}
+
+// OpenAPISchemaJSON 生成缩进格式的 OpenAPI 3.1 Schema JSON
+func (v *Validator) OpenAPISchemaJSON(s interface{}, opts ...SchemaOption) ([]byte, error) {
+	return v.engine.OpenAPISchemaJSON(s, opts...)
+}
+
+// RegisterModifier 注册自定义清洗规则
+func (v *Validator) RegisterModifier(tag string, fn ModifierFunc) error {
+	return v.engine.RegisterModifier(tag, fn)
+}
+
 // RegisterValidation 注册自定义验证规则
 func (v *Validator) RegisterValidation(tag string, fn ValidatorFunc) error {
 	return v.engine.RegisterValidation(tag, fn)
 }
 
 
+// RegisterValidationCtx 注册上下文验证规则
+func (v *Validator) RegisterValidationCtx(tag string, fn ValidatorCtxFunc) error {
+	return v.engine.RegisterValidationCtx(tag, fn)
+}
+
+// RegisterCustomTypeFunc 注册自定义类型的解包函数
+func (v *Validator) RegisterCustomTypeFunc(fn CustomTypeFunc, types ...interface{}) error {
+	return v.engine.RegisterCustomTypeFunc(fn, types...)
+}
+
 // RegisterStructValidation 注册结构体级别验证规则
 func (v *Validator) RegisterStructValidation(fn StructValidatorFunc, typeName string) error {
 	return v.engine.RegisterStructValidation(fn, typeName)
@@ -487,16 +549,71 @@ func Struct(s interface{}) error {
 	return Default().Struct(s)
 }
 
+// StructCtx 使用默认验证器验证结构体，上下文规则使用 ctx 执行
+func StructCtx(ctx context.Context, s interface{}) error {
+	return Default().StructCtx(ctx, s)
+}
+
+// StructPartial 使用默认验证器只验证列出的字段
+func StructPartial(s interface{}, fields ...string) error {
+	return Default().StructPartial(s, fields...)
+}
+
+// StructExcept 使用默认验证器验证列出字段以外的全部字段
+func StructExcept(s interface{}, fields ...string) error {
+	return Default().StructExcept(s, fields...)
+}
+
+// StructGroups 使用默认验证器只验证属于指定分组的字段
+func StructGroups(s interface{}, groups ...string) error {
+	return Default().StructGroups(s, groups...)
+}
+
 // Var 使用默认验证器验证单个变量
 func Var(field interface{}, tag string) error {
 	return Default().Var(field, tag)
 }
 
+// Sanitize 使用默认验证器按 mod 标签就地清洗结构体
+func Sanitize(s interface{}) error {
+	return Default().Sanitize(s)
+}
+
+// SanitizeStruct 使用默认验证器先清洗再验证结构体
+func SanitizeStruct(s interface{}) error {
+	return Default().SanitizeStruct(s)
+}
+
+// OpenAPISchema 使用默认验证器生成 OpenAPI 3.1 Schema
+func OpenAPISchema(s interface{}, opts ...SchemaOption) (*Schema, error) {
+	return Default().OpenAPISchema(s, opts...)
+}
+
+// OpenAPISchemaJSON 使用默认验证器生成缩进格式的 OpenAPI 3.1 Schema JSON
+func OpenAPISchemaJSON(s interface{}, opts ...SchemaOption) ([]byte, error) {
+	return Default().OpenAPISchemaJSON(s, opts...)
+}
+
+// RegisterModifier 在默认验证器上注册自定义清洗规则
+func RegisterModifier(tag string, fn ModifierFunc) error {
+	return Default().RegisterModifier(tag, fn)
+}
+
 // RegisterValidation 在默认验证器上注册自定义验证规则
 func RegisterValidation(tag string, fn ValidatorFunc) error {
 	return Default().RegisterValidation(tag, fn)
 }
 
+// RegisterValidationCtx 在默认验证器上注册上下文验证规则
+func RegisterValidationCtx(tag string, fn ValidatorCtxFunc) error {
+	return Default().RegisterValidationCtx(tag, fn)
+}
+
+// RegisterCustomTypeFunc 在默认验证器上注册自定义类型的解包函数
+func RegisterCustomTypeFunc(fn CustomTypeFunc, types ...interface{}) error {
+	return Default().RegisterCustomTypeFunc(fn, types...)
+}
+
 // RegisterTranslation 在默认验证器上注册翻译
 func RegisterTranslation(locale xlanguage.Tag, tag, translation string) {
 	Default().RegisterTranslation(locale, tag, translation)
@@ -1,11 +1,13 @@
 package wait
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	"github.com/lazygophers/log"
 	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/xerror"
 )
 
 // Wgp 是 sync.WaitGroup 的对象池，用于复用 WaitGroup 对象
@@ -61,6 +63,47 @@ func Async[M any](process int, push func(chan M), logic func(M)) {
 	close(c)
 }
 
+// AsyncCtx 与 Async 相同，但由 ctx 控制取消
+// 参数:
+//
+//	ctx: 取消后不再调度通道中剩余的任务，logic 通过参数拿到同一个 ctx
+//	process: 并发处理的任务数量（协程数量）
+//	push: 任务推送函数，可自行监听 ctx 提前结束推送
+//	logic: 任务处理逻辑函数
+//
+// 返回值: ctx 结束时返回 xerror 超时/取消错误，不再等待正在执行的任务；否则返回 nil
+func AsyncCtx[M any](ctx context.Context, process int, push func(chan M), logic func(context.Context, M)) error {
+	if process <= 0 {
+		return nil
+	}
+
+	c := make(chan M, process*2)
+	w := Wgp.Get().(*sync.WaitGroup)
+
+	w.Add(process)
+	for i := 0; i < process; i++ {
+		routine.GoWithRecover(func() error {
+			defer w.Done()
+			for x := range c {
+				// ctx 已结束：只消费不执行，保证 push 不会阻塞
+				if ctx.Err() != nil {
+					continue
+				}
+				logic(ctx, x)
+			}
+			return nil
+		})
+	}
+
+	push(c)
+	close(c)
+
+	if err := waitAndPut(ctx, w); err != nil {
+		return err
+	}
+	return xerror.FromContext(ctx)
+}
+
 // AsyncAlwaysWithChan 使用指定数量的协程持续处理通道中的任务
 // 参数:
 //
@@ -149,6 +192,32 @@ func() {
 	w.Wait()
 }
 
+// AsyncUniqueCtx 与 AsyncUnique 相同，但由 ctx 控制取消，语义见 AsyncCtx
+func AsyncUniqueCtx[M UniqueTask](ctx context.Context, process int, push func(chan M), logic func(context.Context, M)) error {
+	return AsyncCtx(ctx, process, push, uniqueLogic("AsyncUniqueCtx", logic))
+}
+
+// uniqueLogic 包装 logic，使相同 UniqueKey 的任务不并发执行
+func uniqueLogic[M UniqueTask](name string, logic func(context.Context, M)) func(context.Context, M) {
+	// uniqueMap 用于存储任务唯一键，防止重复执行
+	var uniqueMap sync.Map
+	return func(ctx context.Context, x M) {
+		key := x.UniqueKey()
+		_, exist := uniqueMap.LoadOrStore(key, struct{}{})
+		if exist {
+			log.Warnf("task exist:%s", key)
+			return
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				log.Errorf("%s task panic [key=%s]: %v", name, key, r)
+			}
+			uniqueMap.Delete(key)
+		}()
+		logic(ctx, x)
+	}
+}
+
 // AsyncCollect 使用协程池处理任务并收集错误
 // 参数:
 //
@@ -201,6 +270,33 @@ func() {
 	return errCh
 }
 
+// AsyncAlwaysWithChanCtx 与 AsyncAlwaysWithChan 相同，但 ctx 结束后协程退出，通道中剩余任务不再处理
+// 注意: 调用者仍需负责关闭通道；ctx 结束后继续向无缓冲空间的通道发送会阻塞
+func AsyncAlwaysWithChanCtx[M any](ctx context.Context, process int, c chan M, logic func(context.Context, M)) {
+	if process <= 0 {
+		return
+	}
+
+	for i := 0; i < process; i++ {
+		routine.GoWithRecover(func() error {
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case x, ok := <-c:
+					if !ok {
+						return nil
+					}
+					if ctx.Err() != nil {
+						return nil
+					}
+					logic(ctx, x)
+				}
+			}
+		})
+	}
+}
+
 // AsyncAlwaysUnique 创建任务通道并启动带唯一性校验的协程
 // 参数:
 //
@@ -1,8 +1,11 @@
 package wait_test
 
 import (
+	"context"
+	"errors"
 	"io"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@
 
 	"github.com/lazygophers/log"
 	"github.com/lazygophers/utils/wait"
+	"github.com/lazygophers/utils/xerror"
 )
 
 func TestAsync(t *testing.T) {
@@ -317,3 +321,72 @@ func TestAsyncAlwaysUniqueWithChan(t *testing.T) {
 	assert.Contains(t, results, 200)
 	assert.Contains(t, results, 400)
 }
+
+func TestAsyncCtx(t *testing.T) {
+	t.Run("completes", func(t *testing.T) {
+		var sum int32
+		err := wait.AsyncCtx(context.Background(), 3, func(ch chan int) {
+			for i := 1; i <= 10; i++ {
+				ch <- i
+			}
+		}, func(ctx context.Context, x int) {
+			atomic.AddInt32(&sum, int32(x))
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(55), atomic.LoadInt32(&sum))
+	})
+
+	t.Run("cancel stops scheduling", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var executed int32
+		err := wait.AsyncCtx(ctx, 1, func(ch chan int) {
+			for i := 0; i < 100; i++ {
+				ch <- i
+			}
+		}, func(ctx context.Context, x int) {
+			if atomic.AddInt32(&executed, 1) == 5 {
+				cancel()
+			}
+		})
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, xerror.CodeCanceled, xerror.Code(err))
+		assert.Equal(t, int32(5), atomic.LoadInt32(&executed))
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		log.SetOutput(io.Discard)
+
+		var executed int32
+		err := wait.AsyncUniqueCtx(context.Background(), 2, func(ch chan TestTask) {
+			ch <- TestTask{ID: "task1", Value: 1}
+			ch <- TestTask{ID: "task2", Value: 2}
+		}, func(ctx context.Context, task TestTask) {
+			atomic.AddInt32(&executed, 1)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&executed))
+	})
+}
+
+func TestAsyncAlwaysWithChanCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan int, 10)
+	done := make(chan int, 10)
+
+	wait.AsyncAlwaysWithChanCtx(ctx, 2, c, func(ctx context.Context, x int) {
+		done <- x
+	})
+
+	c <- 1
+	assert.Equal(t, 1, <-done)
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	c <- 2
+	select {
+	case x := <-done:
+		t.Errorf("Expected no processing after cancel, got %d", x)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(c)
+}
@@ -1,15 +1,18 @@
 package wait
 
 import (
+	"context"
 	"sync"
 
 	"github.com/lazygophers/utils/routine"
 	"github.com/lazygophers/utils/runtime"
+	"github.com/lazygophers/utils/xerror"
 )
 
 // Worker 管理一组goroutine worker，通过任务队列和等待组协调任务执行
 // 使用NewWorker创建，Add提交任务，Wait等待完成
 type Worker struct {
+	ctx    context.Context // 取消后不再调度队列中的任务
 	w      *sync.WaitGroup // 用于等待所有任务完成的WaitGroup
 	c      chan func()     // 任务队列，接收待执行函数
 	closed bool            // 标记通道是否已关闭
@@ -26,6 +29,30 @@ func (p *Worker) Add(fn func()) {
 	p.c <- fn
 }
 
+// AddCtx 向工作队列提交一个任务，队列已满时阻塞直到有空间或 ctx 结束
+// ctx 或 Worker 的 ctx 已结束时不再提交，返回 xerror 超时/取消错误
+// 如果 Wait() 已调用，会 panic
+func (p *Worker) AddCtx(ctx context.Context, fn func()) error {
+	if p.closed {
+		panic("wait: Worker already closed, cannot add task")
+	}
+	if err := xerror.FromContext(ctx); err != nil {
+		return err
+	}
+	if err := xerror.FromContext(p.ctx); err != nil {
+		return err
+	}
+
+	select {
+	case p.c <- fn:
+		return nil
+	case <-ctx.Done():
+		return xerror.FromContext(ctx)
+	case <-p.ctx.Done():
+		return xerror.FromContext(p.ctx)
+	}
+}
+
 // Wait 等待所有任务完成
 // 注意：调用后不可再调用Add
 // 内部会关闭通道并将WaitGroup放回对象池
@@ -38,9 +65,45 @@ func (p *Worker) Wait() {
 	}
 }
 
+// WaitCtx 与 Wait 相同，但 ctx 结束时立即返回 xerror 超时/取消错误
+// 正在执行的任务不会被中断，WaitGroup 在全部任务结束后才放回对象池
+func (p *Worker) WaitCtx(ctx context.Context) error {
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	close(p.c)
+	return waitAndPut(ctx, p.w)
+}
+
+// waitAndPut 等待 w 完成后放回对象池；ctx 先结束时立即返回 xerror 超时/取消错误，
+// 放回对象池的动作在后台等待完成后进行
+func waitAndPut(ctx context.Context, w *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		w.Wait()
+		Wgp.Put(w)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return xerror.FromContext(ctx)
+	}
+}
+
 // NewWorker 创建Worker实例
 // max: 最大并发goroutine数量
 func NewWorker(max int) *Worker {
+	return NewWorkerCtx(context.Background(), max)
+}
+
+// NewWorkerCtx 创建绑定 ctx 的Worker实例
+// ctx 结束后队列中尚未开始的任务被丢弃，AddCtx 返回错误；已开始的任务不会被中断
+func NewWorkerCtx(ctx context.Context, max int) *Worker {
 	if max < 0 {
 		max = 0
 	}
@@ -65,6 +128,11 @@ func NewWorker(max int) *Worker {
 
 			// 从任务通道不断获取任务执行
 			for fn := range c {
+				// ctx 已结束：继续消费通道但不再执行，避免 Add 阻塞
+				if ctx.Err() != nil {
+					continue
+				}
+
 				// 每个任务在独立闭包中执行，确保异常不会影响其他任务
 				func() {
 					// 捕获panic并记录日志（不中断程序）
@@ -78,7 +146,8 @@ func() {
 	}
 
 	return &Worker{
-		c: c,
-		w: w,
+		ctx: ctx,
+		c:   c,
+		w:   w,
 	}
 }
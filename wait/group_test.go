@@ -1,13 +1,17 @@
 package wait_test
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/lazygophers/utils/wait"
+	"github.com/lazygophers/utils/xerror"
 )
 
 func TestNewWorker(t *testing.T) {
@@ -298,3 +302,56 @@ func BenchmarkWorker(b *testing.B) {
 		worker.Wait()
 	})
 }
+
+func TestWorkerCtx(t *testing.T) {
+	t.Run("cancel drops queued tasks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		worker := wait.NewWorkerCtx(ctx, 1)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var executed int32
+		assert.NoError(t, worker.AddCtx(context.Background(), func() {
+			close(started)
+			<-release
+		}))
+		<-started
+
+		assert.NoError(t, worker.AddCtx(context.Background(), func() {
+			atomic.AddInt32(&executed, 1)
+		}))
+
+		cancel()
+		close(release)
+
+		err := worker.AddCtx(context.Background(), func() {})
+		assert.True(t, errors.Is(err, context.Canceled))
+
+		worker.Wait()
+		assert.Equal(t, int32(0), atomic.LoadInt32(&executed))
+	})
+
+	t.Run("add blocked on full queue", func(t *testing.T) {
+		worker := wait.NewWorker(0)
+		assert.NoError(t, worker.AddCtx(context.Background(), func() {}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := worker.AddCtx(ctx, func() {})
+		assert.Equal(t, xerror.CodeTimeout, xerror.Code(err))
+	})
+
+	t.Run("wait released on cancel", func(t *testing.T) {
+		worker := wait.NewWorker(1)
+		release := make(chan struct{})
+		worker.Add(func() { <-release })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := worker.WaitCtx(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		close(release)
+		assert.NoError(t, worker.WaitCtx(context.Background()), "second wait is a no-op")
+	})
+}
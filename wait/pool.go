@@ -0,0 +1,408 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lazygophers/log"
+	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/runtime"
+	"github.com/lazygophers/utils/xerror"
+)
+
+var (
+	// ErrPoolFull 队列已满且拒绝策略为 PolicyDrop 时由 Submit 返回
+	ErrPoolFull = errors.New("wait: pool queue full")
+	// ErrPoolClosed 在 Shutdown/ShutdownNow 之后提交任务时返回
+	ErrPoolClosed = errors.New("wait: pool closed")
+)
+
+// RejectPolicy 决定队列已满时 Submit 的行为
+type RejectPolicy int
+
+const (
+	// PolicyBlock 阻塞直到队列有空间、ctx 结束或 WorkerPool 关闭
+	PolicyBlock RejectPolicy = iota
+	// PolicyDrop 丢弃新任务，Submit 返回 ErrPoolFull
+	PolicyDrop
+	// PolicyDropOldest 丢弃队列中最早的任务，为新任务腾出空间
+	PolicyDropOldest
+	// PolicyCallerRuns 在调用 Submit 的协程中直接执行新任务
+	PolicyCallerRuns
+)
+
+func (p RejectPolicy) String() string {
+	switch p {
+	case PolicyBlock:
+		return "block"
+	case PolicyDrop:
+		return "drop"
+	case PolicyDropOldest:
+		return "drop-oldest"
+	case PolicyCallerRuns:
+		return "caller-runs"
+	default:
+		return fmt.Sprintf("RejectPolicy(%d)", int(p))
+	}
+}
+
+// WorkerPoolConfig 是 WorkerPool 的配置
+type WorkerPoolConfig struct {
+	// MinWorkers 常驻 worker 数量，必须大于 0
+	MinWorkers int
+	// MaxWorkers worker 数量上限；小于等于 MinWorkers 时为固定大小的 WorkerPool。
+	// 所有 worker 都在忙且有任务入队时，按需启动额外 worker，空闲 IdleTimeout 后退出
+	MaxWorkers int
+	// QueueSize 任务队列容量，0 表示不排队，只有空闲 worker 能接收任务
+	QueueSize int
+	// Policy 队列已满时的拒绝策略，默认 PolicyBlock
+	Policy RejectPolicy
+	// IdleTimeout 额外 worker 的空闲退出时间，默认 1 分钟
+	IdleTimeout time.Duration
+	// TaskTimeout 任务默认超时，作为任务 ctx 的截止时间；0 表示不限制
+	TaskTimeout time.Duration
+}
+
+// WorkerPoolStats 是 WorkerPool 的运行统计
+type WorkerPoolStats struct {
+	Workers   int    // 当前 worker 数量
+	Queued    int    // 队列中等待执行的任务数量
+	Running   int    // 正在执行的任务数量
+	Completed uint64 // 正常执行结束的任务数量
+	Rejected  uint64 // 被拒绝或丢弃的任务数量（含 drop-oldest 挤出与 ShutdownNow 丢弃的任务）
+	Panicked  uint64 // 执行时 panic 的任务数量
+}
+
+type poolTask struct {
+	fn      func(ctx context.Context)
+	timeout time.Duration
+}
+
+// WorkerPool 带有界队列的 worker 池，支持固定或弹性的 worker 数量、队列满时的拒绝策略、
+// 任务超时、优雅关闭与运行统计。
+// 任务通过 ctx 感知超时与 ShutdownNow，执行中的任务不会被强行中断。
+type WorkerPool struct {
+	config WorkerPoolConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	queue chan poolTask
+	quit  chan struct{}
+
+	mu        sync.RWMutex // 保护 closed，写锁下关闭 queue，避免向已关闭通道发送
+	closed    bool
+	closeOnce sync.Once
+
+	wg      sync.WaitGroup
+	workers int32
+	idle    int32
+
+	running   int64
+	completed uint64
+	rejected  uint64
+	panicked  uint64
+}
+
+// NewWorkerPool 按 config 创建 WorkerPool 并启动 MinWorkers 个常驻 worker
+func NewWorkerPool(config WorkerPoolConfig) (*WorkerPool, error) {
+	if config.MinWorkers <= 0 {
+		return nil, fmt.Errorf("wait: pool min workers must be positive, got %d", config.MinWorkers)
+	}
+	if config.QueueSize < 0 {
+		return nil, fmt.Errorf("wait: pool queue size must not be negative, got %d", config.QueueSize)
+	}
+	if config.Policy < PolicyBlock || config.Policy > PolicyCallerRuns {
+		return nil, fmt.Errorf("wait: unknown reject policy %v", config.Policy)
+	}
+	if config.MaxWorkers < config.MinWorkers {
+		config.MaxWorkers = config.MinWorkers
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = time.Minute
+	}
+
+	p := &WorkerPool{
+		config: config,
+		queue:  make(chan poolTask, config.QueueSize),
+		quit:   make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < config.MinWorkers; i++ {
+		atomic.AddInt32(&p.workers, 1)
+		p.startWorker(true)
+	}
+
+	return p, nil
+}
+
+// Submit 提交任务，任务超时使用 WorkerPoolConfig.TaskTimeout
+func (p *WorkerPool) Submit(fn func(ctx context.Context)) error {
+	return p.submit(context.Background(), poolTask{fn: fn, timeout: p.config.TaskTimeout})
+}
+
+// SubmitCtx 提交任务；PolicyBlock 下等待队列空间时 ctx 结束返回 xerror 超时/取消错误
+func (p *WorkerPool) SubmitCtx(ctx context.Context, fn func(ctx context.Context)) error {
+	return p.submit(ctx, poolTask{fn: fn, timeout: p.config.TaskTimeout})
+}
+
+// SubmitTimeout 提交任务并指定该任务的超时，覆盖 WorkerPoolConfig.TaskTimeout
+func (p *WorkerPool) SubmitTimeout(timeout time.Duration, fn func(ctx context.Context)) error {
+	return p.submit(context.Background(), poolTask{fn: fn, timeout: timeout})
+}
+
+func (p *WorkerPool) submit(ctx context.Context, task poolTask) error {
+	callerRuns, err := p.enqueue(ctx, task)
+	if callerRuns {
+		// 在锁外执行，避免阻塞 Shutdown
+		p.run(task)
+	}
+	return err
+}
+
+// enqueue 按拒绝策略把任务放入队列；返回 true 表示应由调用方协程直接执行
+func (p *WorkerPool) enqueue(ctx context.Context, task poolTask) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		atomic.AddUint64(&p.rejected, 1)
+		return false, ErrPoolClosed
+	}
+
+	select {
+	case p.queue <- task:
+		p.reserve()
+		return false, nil
+	default:
+	}
+
+	// 队列已满：有空闲 worker 即将接收或能扩容时等待入队，否则按策略处理
+	if p.reserve() || p.config.Policy == PolicyBlock {
+		return false, p.send(ctx, task)
+	}
+	atomic.AddInt32(&p.idle, 1)
+
+	switch p.config.Policy {
+	case PolicyDrop:
+		atomic.AddUint64(&p.rejected, 1)
+		return false, ErrPoolFull
+
+	case PolicyDropOldest:
+		// 无缓冲队列没有可挤出的任务，退化为 PolicyDrop
+		if cap(p.queue) == 0 {
+			atomic.AddUint64(&p.rejected, 1)
+			return false, ErrPoolFull
+		}
+		for {
+			select {
+			case p.queue <- task:
+				p.reserve()
+				return false, nil
+			default:
+			}
+			select {
+			case <-p.queue:
+				atomic.AddInt32(&p.idle, 1)
+				atomic.AddUint64(&p.rejected, 1)
+			default:
+			}
+		}
+
+	default: // PolicyCallerRuns
+		return true, nil
+	}
+}
+
+// send 阻塞入队，调用方已通过 reserve 占用一个空闲 worker 名额，失败时归还
+func (p *WorkerPool) send(ctx context.Context, task poolTask) error {
+	select {
+	case p.queue <- task:
+		return nil
+	case <-ctx.Done():
+		atomic.AddInt32(&p.idle, 1)
+		atomic.AddUint64(&p.rejected, 1)
+		return xerror.FromContext(ctx)
+	case <-p.quit:
+		atomic.AddInt32(&p.idle, 1)
+		atomic.AddUint64(&p.rejected, 1)
+		return ErrPoolClosed
+	}
+}
+
+// reserve 为一个待入队任务占用空闲 worker 名额；没有空闲 worker 时尝试扩容。
+// 返回 false 表示 worker 已达上限，任务只能排队等待已有 worker 执行完当前任务
+func (p *WorkerPool) reserve() bool {
+	if atomic.AddInt32(&p.idle, -1) >= 0 {
+		return true
+	}
+	return p.maybeGrow()
+}
+
+// maybeGrow 未达到 MaxWorkers 时启动一个额外 worker
+func (p *WorkerPool) maybeGrow() bool {
+	for {
+		n := atomic.LoadInt32(&p.workers)
+		if int(n) >= p.config.MaxWorkers {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&p.workers, n, n+1) {
+			p.startWorker(false)
+			return true
+		}
+	}
+}
+
+// startWorker 启动一个 worker，新 worker 计入空闲名额。
+// idle = 等待任务的 worker 数 - 已入队未取走的任务数：入队时减一，worker 执行完任务后加一，
+// worker 取走任务时两者同时减一，因此不需要调整
+func (p *WorkerPool) startWorker(core bool) {
+	atomic.AddInt32(&p.idle, 1)
+
+	p.wg.Add(1)
+	routine.GoWithRecover(func() error {
+		defer p.wg.Done()
+		defer atomic.AddInt32(&p.workers, -1)
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !core {
+			timer = time.NewTimer(p.config.IdleTimeout)
+			timeout = timer.C
+			defer timer.Stop()
+		}
+
+		for {
+			select {
+			case task, ok := <-p.queue:
+				if !ok {
+					return nil
+				}
+				p.run(task)
+				atomic.AddInt32(&p.idle, 1)
+
+			case <-timeout:
+				// 只有空闲名额多于待执行任务时才退出，否则有提交者正等待本 worker 接收
+				if p.retire() {
+					return nil
+				}
+			}
+
+			if timer != nil {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(p.config.IdleTimeout)
+			}
+		}
+	})
+}
+
+// retire 空闲超时的额外 worker 归还自身的空闲名额，名额已被待入队任务占用时返回 false
+func (p *WorkerPool) retire() bool {
+	for {
+		n := atomic.LoadInt32(&p.idle)
+		if n <= 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&p.idle, n, n-1) {
+			return true
+		}
+	}
+}
+
+// run 执行单个任务，ShutdownNow 之后取出的任务直接丢弃
+func (p *WorkerPool) run(task poolTask) {
+	if p.ctx.Err() != nil {
+		atomic.AddUint64(&p.rejected, 1)
+		return
+	}
+
+	ctx := p.ctx
+	if task.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.timeout)
+		defer cancel()
+	}
+
+	atomic.AddInt64(&p.running, 1)
+	defer atomic.AddInt64(&p.running, -1)
+
+	defer func() {
+		if r := recover(); r != nil {
+			atomic.AddUint64(&p.panicked, 1)
+			log.Errorf("wait: pool task panic: %v", r)
+			log.Error(runtime.GetStack())
+			return
+		}
+		atomic.AddUint64(&p.completed, 1)
+	}()
+
+	task.fn(ctx)
+}
+
+// close 停止接收新任务并关闭队列，只执行一次
+func (p *WorkerPool) close() {
+	p.closeOnce.Do(func() {
+		// 先唤醒阻塞在 PolicyBlock 上的提交者，再获取写锁
+		close(p.quit)
+
+		p.mu.Lock()
+		p.closed = true
+		close(p.queue)
+		p.mu.Unlock()
+	})
+}
+
+// Shutdown 停止接收新任务，等待队列中的任务与执行中的任务全部完成。
+// ctx 先结束时返回 xerror 超时/取消错误，剩余任务在后台继续执行。
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.close()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return xerror.FromContext(ctx)
+	}
+}
+
+// ShutdownNow 停止接收新任务，丢弃队列中尚未开始的任务并取消执行中任务的 ctx，
+// 返回被丢弃的任务数量；不等待执行中的任务结束。
+func (p *WorkerPool) ShutdownNow() int {
+	p.cancel()
+	p.close()
+
+	dropped := 0
+	for range p.queue {
+		dropped++
+	}
+	atomic.AddUint64(&p.rejected, uint64(dropped))
+	return dropped
+}
+
+// Stats 返回当前运行统计
+func (p *WorkerPool) Stats() WorkerPoolStats {
+	return WorkerPoolStats{
+		Workers:   int(atomic.LoadInt32(&p.workers)),
+		Queued:    len(p.queue),
+		Running:   int(atomic.LoadInt64(&p.running)),
+		Completed: atomic.LoadUint64(&p.completed),
+		Rejected:  atomic.LoadUint64(&p.rejected),
+		Panicked:  atomic.LoadUint64(&p.panicked),
+	}
+}
@@ -0,0 +1,248 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazygophers/utils/wait"
+	"github.com/lazygophers/utils/xerror"
+)
+
+// blockPool 创建单 worker 的 WorkerPool，并用一个阻塞任务占住 worker
+func blockPool(t *testing.T, config wait.WorkerPoolConfig) (*wait.WorkerPool, chan struct{}) {
+	t.Helper()
+
+	config.MinWorkers = 1
+	pool, err := wait.NewWorkerPool(config)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, pool.Submit(func(ctx context.Context) {
+		close(started)
+		<-release
+	}))
+	<-started
+	return pool, release
+}
+
+func TestNewWorkerPool(t *testing.T) {
+	_, err := wait.NewWorkerPool(wait.WorkerPoolConfig{})
+	assert.Error(t, err)
+
+	_, err = wait.NewWorkerPool(wait.WorkerPoolConfig{MinWorkers: 1, QueueSize: -1})
+	assert.Error(t, err)
+
+	_, err = wait.NewWorkerPool(wait.WorkerPoolConfig{MinWorkers: 1, Policy: wait.RejectPolicy(99)})
+	assert.Error(t, err)
+
+	pool, err := wait.NewWorkerPool(wait.WorkerPoolConfig{MinWorkers: 2, QueueSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, pool.Stats().Workers)
+	assert.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestWorkerPoolSubmit(t *testing.T) {
+	pool, err := wait.NewWorkerPool(wait.WorkerPoolConfig{MinWorkers: 4, QueueSize: 100})
+	require.NoError(t, err)
+
+	var sum int64
+	for i := 1; i <= 100; i++ {
+		i := i
+		require.NoError(t, pool.Submit(func(ctx context.Context) {
+			atomic.AddInt64(&sum, int64(i))
+		}))
+	}
+
+	require.NoError(t, pool.Shutdown(context.Background()))
+	assert.Equal(t, int64(5050), atomic.LoadInt64(&sum))
+	assert.Equal(t, uint64(100), pool.Stats().Completed)
+	assert.Equal(t, 0, pool.Stats().Workers)
+
+	assert.Equal(t, wait.ErrPoolClosed, pool.Submit(func(ctx context.Context) {}))
+}
+
+func TestWorkerPoolPolicies(t *testing.T) {
+	t.Run("block", func(t *testing.T) {
+		pool, release := blockPool(t, wait.WorkerPoolConfig{QueueSize: 1})
+		require.NoError(t, pool.Submit(func(ctx context.Context) {}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := pool.SubmitCtx(ctx, func(ctx context.Context) {})
+		assert.Equal(t, xerror.CodeTimeout, xerror.Code(err))
+
+		close(release)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		assert.Equal(t, uint64(1), pool.Stats().Rejected)
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		pool, release := blockPool(t, wait.WorkerPoolConfig{QueueSize: 1, Policy: wait.PolicyDrop})
+		require.NoError(t, pool.Submit(func(ctx context.Context) {}))
+		assert.Equal(t, wait.ErrPoolFull, pool.Submit(func(ctx context.Context) {}))
+
+		close(release)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		stats := pool.Stats()
+		assert.Equal(t, uint64(2), stats.Completed)
+		assert.Equal(t, uint64(1), stats.Rejected)
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		pool, release := blockPool(t, wait.WorkerPoolConfig{QueueSize: 2, Policy: wait.PolicyDropOldest})
+
+		var (
+			mu  sync.Mutex
+			ran []int
+		)
+		for i := 1; i <= 3; i++ {
+			i := i
+			require.NoError(t, pool.Submit(func(ctx context.Context) {
+				mu.Lock()
+				ran = append(ran, i)
+				mu.Unlock()
+			}))
+		}
+
+		close(release)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		assert.Equal(t, []int{2, 3}, ran)
+		assert.Equal(t, uint64(1), pool.Stats().Rejected)
+	})
+
+	t.Run("caller runs", func(t *testing.T) {
+		pool, release := blockPool(t, wait.WorkerPoolConfig{QueueSize: 1, Policy: wait.PolicyCallerRuns})
+		require.NoError(t, pool.Submit(func(ctx context.Context) {}))
+
+		caller := make(chan struct{})
+		require.NoError(t, pool.Submit(func(ctx context.Context) {
+			close(caller)
+		}))
+		select {
+		case <-caller:
+		default:
+			t.Error("Expected task to run in the caller before Submit returns")
+		}
+
+		close(release)
+		require.NoError(t, pool.Shutdown(context.Background()))
+	})
+}
+
+func TestWorkerPoolElastic(t *testing.T) {
+	for _, queueSize := range []int{0, 2} {
+		pool, err := wait.NewWorkerPool(wait.WorkerPoolConfig{
+			MinWorkers:  1,
+			MaxWorkers:  3,
+			QueueSize:   queueSize,
+			IdleTimeout: 20 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		// 多轮提交，覆盖 worker 刚接收任务、尚未开始执行时再次提交的情况
+		for round := 0; round < 20; round++ {
+			release := make(chan struct{})
+			var started sync.WaitGroup
+			started.Add(3)
+			for i := 0; i < 3; i++ {
+				require.NoError(t, pool.Submit(func(ctx context.Context) {
+					started.Done()
+					<-release
+				}))
+			}
+			started.Wait()
+
+			stats := pool.Stats()
+			assert.Equal(t, 3, stats.Workers)
+			assert.Equal(t, 3, stats.Running)
+			close(release)
+		}
+
+		assert.Eventually(t, func() bool { return pool.Stats().Workers == 1 }, time.Second, 5*time.Millisecond,
+			"extra workers should exit after the idle timeout")
+		require.NoError(t, pool.Shutdown(context.Background()))
+	}
+}
+
+func TestWorkerPoolTaskTimeout(t *testing.T) {
+	pool, err := wait.NewWorkerPool(wait.WorkerPoolConfig{MinWorkers: 1, QueueSize: 2, TaskTimeout: time.Hour})
+	require.NoError(t, err)
+
+	deadlines := make(chan time.Duration, 2)
+	record := func(ctx context.Context) {
+		deadline, _ := ctx.Deadline()
+		deadlines <- time.Until(deadline)
+	}
+	require.NoError(t, pool.Submit(record))
+	require.NoError(t, pool.SubmitTimeout(10*time.Millisecond, func(ctx context.Context) {
+		<-ctx.Done()
+		assert.True(t, errors.Is(ctx.Err(), context.DeadlineExceeded))
+		record(ctx)
+	}))
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Greater(t, <-deadlines, time.Minute)
+	assert.LessOrEqual(t, <-deadlines, time.Duration(0))
+}
+
+func TestWorkerPoolPanic(t *testing.T) {
+	pool, err := wait.NewWorkerPool(wait.WorkerPoolConfig{MinWorkers: 1, QueueSize: 2})
+	require.NoError(t, err)
+
+	require.NoError(t, pool.Submit(func(ctx context.Context) { panic("pool boom") }))
+	require.NoError(t, pool.Submit(func(ctx context.Context) {}))
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	stats := pool.Stats()
+	assert.Equal(t, uint64(1), stats.Panicked)
+	assert.Equal(t, uint64(1), stats.Completed)
+}
+
+func TestWorkerPoolShutdown(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		pool, release := blockPool(t, wait.WorkerPoolConfig{QueueSize: 1})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := pool.Shutdown(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		close(release)
+		assert.NoError(t, pool.Shutdown(context.Background()))
+	})
+
+	t.Run("now", func(t *testing.T) {
+		config := wait.WorkerPoolConfig{QueueSize: 5}
+		config.MinWorkers = 1
+		pool, err := wait.NewWorkerPool(config)
+		require.NoError(t, err)
+
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		require.NoError(t, pool.Submit(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+		}))
+		<-started
+
+		var ran int32
+		for i := 0; i < 3; i++ {
+			require.NoError(t, pool.Submit(func(ctx context.Context) { atomic.AddInt32(&ran, 1) }))
+		}
+
+		assert.Equal(t, 3, pool.ShutdownNow())
+		<-cancelled
+		require.NoError(t, pool.Shutdown(context.Background()))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&ran))
+		assert.Equal(t, uint64(3), pool.Stats().Rejected)
+	})
+}
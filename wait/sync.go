@@ -1,11 +1,14 @@
 package wait
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
 
 	"github.com/lazygophers/log"
+	"github.com/lazygophers/utils/routine"
+	"github.com/lazygophers/utils/xerror"
 )
 
 var ErrPoolNotReady = errors.New("wait: pool not ready (call Ready(key, max) first)")
@@ -45,6 +48,16 @@ func (p *Pool) TryLock() bool {
 	}
 }
 
+// LockCtx 获取一个信号量，ctx 结束前仍未获取到时返回 xerror 超时/取消错误。
+func (p *Pool) LockCtx(ctx context.Context) error {
+	select {
+	case p.c <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return xerror.FromContext(ctx)
+	}
+}
+
 // Available 返回当前可用的信号量数量。
 func (p *Pool) Available() int {
 	return len(p.c)
@@ -210,3 +223,39 @@ func SyncTimeout(key string, timeout time.Duration, logic func() error) error {
 		return errors.New("wait: timeout")
 	}
 }
+
+// SyncCtx 在指定key的Pool上同步执行逻辑函数，由 ctx 控制超时与取消。
+// 等待信号量或等待 logic 完成期间 ctx 结束，立即返回 xerror 超时/取消错误；
+// 已开始的 logic 会继续执行直到返回（需自行监听传入的 ctx 提前退出），信号量在 logic 返回后释放。
+func SyncCtx(ctx context.Context, key string, logic func(ctx context.Context) error) error {
+	pool := getPool(key)
+	if pool == nil {
+		return ErrPoolNotReady
+	}
+
+	if err := xerror.FromContext(ctx); err != nil {
+		return err
+	}
+
+	log.Debugf("%s pool depth:%d", key, pool.Depth())
+	if err := pool.LockCtx(ctx); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	routine.Go(func() error {
+		defer func() {
+			pool.Unlock()
+			log.Infof("%s pool depth:%d", key, pool.Depth())
+		}()
+		done <- logic(ctx)
+		return nil
+	})
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return xerror.FromContext(ctx)
+	}
+}
@@ -1,6 +1,7 @@
 package wait_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"sync"
@@ -11,6 +12,7 @@
 
 	"github.com/lazygophers/log"
 	"github.com/lazygophers/utils/wait"
+	"github.com/lazygophers/utils/xerror"
 )
 
 func TestReady(t *testing.T) {
@@ -357,3 +359,64 @@ func BenchmarkLockUnlock(b *testing.B) {
 		}
 	})
 }
+
+func TestSyncCtx(t *testing.T) {
+	t.Run("pool not ready", func(t *testing.T) {
+		err := wait.SyncCtx(context.Background(), "test_sync_ctx_missing", func(ctx context.Context) error { return nil })
+		assert.Equal(t, wait.ErrPoolNotReady, err)
+	})
+
+	t.Run("returns logic error", func(t *testing.T) {
+		key := "test_sync_ctx_ok"
+		wait.Ready(key, 1)
+
+		expected := errors.New("logic error")
+		err := wait.SyncCtx(context.Background(), key, func(ctx context.Context) error { return expected })
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("cancelled while waiting for slot", func(t *testing.T) {
+		key := "test_sync_ctx_full"
+		wait.Ready(key, 1)
+		wait.Lock(key)
+		defer wait.Unlock(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		called := false
+		err := wait.SyncCtx(ctx, key, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, xerror.CodeTimeout, xerror.Code(err))
+		assert.False(t, called)
+	})
+
+	t.Run("cancelled while running", func(t *testing.T) {
+		key := "test_sync_ctx_running"
+		wait.Ready(key, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		released := make(chan struct{})
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		err := wait.SyncCtx(ctx, key, func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			close(released)
+			return nil
+		})
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, xerror.CodeCanceled, xerror.Code(err))
+
+		// 信号量在 logic 返回后释放
+		<-released
+		assert.Eventually(t, func() bool { return wait.TryLock(key) }, time.Second, 5*time.Millisecond)
+		wait.Unlock(key)
+	})
+}
@@ -33,6 +33,9 @@
 
 	// CodeDataCorrupted 数据损坏或不一致。
 	CodeDataCorrupted = 1010
+
+	// CodeCanceled 操作被调用方取消。
+	CodeCanceled = 1011
 )
 
 // NewSystemError 创建系统级错误（code = CodeSystem）。
@@ -90,6 +93,11 @@ func NewDataCorrupted(args ...any) *Error {
 	return New(CodeDataCorrupted, args...)
 }
 
+// NewCanceled 创建取消错误（code = CodeCanceled）。
+func NewCanceled(args ...any) *Error {
+	return New(CodeCanceled, args...)
+}
+
 // registerBuiltinLocale 把单语言内置错误码翻译表注册到 i18n.Default。
 // 各 codes_<lang>.go 在 init() 中调用此函数，复用 errorKey 拼装与 i18n.Default 写入逻辑。
 func registerBuiltinLocale(langStr string, codes map[int]string) {
@@ -1,6 +1,8 @@
 package xerror
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/lazygophers/utils/i18n"
@@ -27,6 +29,7 @@ func TestCodeShortcutConstructors(t *testing.T) {
 		{"Forbidden", NewForbidden, CodeForbidden},
 		{"Unavailable", NewUnavailable, CodeUnavailable},
 		{"DataCorrupted", NewDataCorrupted, CodeDataCorrupted},
+		{"Canceled", NewCanceled, CodeCanceled},
 	}
 	for _, c := range cases {
 		err := c.ctor("detail")
@@ -44,6 +47,7 @@ func TestCodeConstantsUnique(t *testing.T) {
 		CodeSystem, CodeSuccess,
 		CodeInvalidParam, CodeNoAuth, CodeNoData, CodeConflict, CodeNotLogin,
 		CodeTimeout, CodeRateLimited, CodeForbidden, CodeUnavailable, CodeDataCorrupted,
+		CodeCanceled,
 	}
 	seen := map[int]bool{}
 	for _, c := range codes {
@@ -90,6 +94,26 @@ func TestBuiltinMessagesNewIntegration(t *testing.T) {
 	}
 }
 
+func TestFromContext(t *testing.T) {
+	if err := FromContext(context.Background()); err != nil {
+		t.Errorf("live ctx: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := FromContext(ctx)
+	if Code(err) != CodeCanceled || !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled ctx: code=%d err=%v", Code(err), err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	err = FromContext(ctx)
+	if Code(err) != CodeTimeout || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expired ctx: code=%d err=%v", Code(err), err)
+	}
+}
+
 func makeTag(s string) xlanguage.Tag {
 	return xlanguage.Make(s)
 }
@@ -0,0 +1,20 @@
+package xerror
+
+import (
+	"context"
+	"errors"
+)
+
+// FromContext 把已结束 ctx 的错误转换为 *Error；ctx 未结束时返回 nil。
+// 超时（context.DeadlineExceeded）映射为 CodeTimeout，其余（含 context.Canceled）映射为 CodeCanceled。
+// 原始 ctx.Err() 作为 cause 挂载，errors.Is(err, context.Canceled) 等判断仍然成立。
+func FromContext(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewTimeout().WithCause(err)
+	}
+	return NewCanceled().WithCause(err)
+}
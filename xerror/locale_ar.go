@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "ممنوع",
 		CodeUnavailable:   "الخدمة غير متاحة",
 		CodeDataCorrupted: "البيانات تالفة",
+		CodeCanceled:      "تم الإلغاء",
 	})
 }
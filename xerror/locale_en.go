@@ -14,5 +14,6 @@ func init() {
 		CodeForbidden:     "forbidden",
 		CodeUnavailable:   "service unavailable",
 		CodeDataCorrupted: "data corrupted",
+		CodeCanceled:      "canceled",
 	})
 }
@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "prohibido",
 		CodeUnavailable:   "servicio no disponible",
 		CodeDataCorrupted: "datos dañados",
+		CodeCanceled:      "operación cancelada",
 	})
 }
@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "interdit",
 		CodeUnavailable:   "service indisponible",
 		CodeDataCorrupted: "données corrompues",
+		CodeCanceled:      "opération annulée",
 	})
 }
@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "アクセス禁止",
 		CodeUnavailable:   "サービス利用不可",
 		CodeDataCorrupted: "データ破損",
+		CodeCanceled:      "キャンセルされました",
 	})
 }
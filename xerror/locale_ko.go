@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "접근 금지",
 		CodeUnavailable:   "서비스 이용 불가",
 		CodeDataCorrupted: "데이터 손상",
+		CodeCanceled:      "취소됨",
 	})
 }
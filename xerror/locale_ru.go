@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "запрещено",
 		CodeUnavailable:   "сервис недоступен",
 		CodeDataCorrupted: "данные повреждены",
+		CodeCanceled:      "операция отменена",
 	})
 }
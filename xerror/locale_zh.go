@@ -14,5 +14,6 @@ func init() {
 		CodeForbidden:     "禁止访问",
 		CodeUnavailable:   "服务不可用",
 		CodeDataCorrupted: "数据损坏",
+		CodeCanceled:      "请求已取消",
 	})
 }
@@ -16,5 +16,6 @@ func init() {
 		CodeForbidden:     "禁止存取",
 		CodeUnavailable:   "服務不可用",
 		CodeDataCorrupted: "資料損壞",
+		CodeCanceled:      "請求已取消",
 	})
 }